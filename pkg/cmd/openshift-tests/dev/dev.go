@@ -27,6 +27,9 @@ func NewDevCommand() *cobra.Command {
 	cmd.AddCommand(
 		newRunAlertInvariantsCommand(),
 		newRunDisruptionInvariantsCommand(),
+		newReplayCommand(),
+		newListMonitorTestsCommand(),
+		newQueryCommand(),
 	)
 	return cmd
 }
@@ -0,0 +1,56 @@
+package dev
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/openshift/origin/pkg/defaultmonitortests"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/spf13/cobra"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+func newListMonitorTestsCommand() *cobra.Command {
+	clusterStability := string(monitortestframework.Stable)
+
+	cmd := &cobra.Command{
+		Use:   "list-monitor-tests",
+		Short: "List the registered monitor tests, the phases they run in, and whether they need a cluster",
+		Long: templates.LongDesc(`
+List every registered monitor test with the phases it participates in.
+
+Monitor tests that do not need a cluster can be run against saved artifacts with
+"openshift-tests dev replay".
+`),
+
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			monitorTestRegistry, err := defaultmonitortests.NewMonitorTestsFor(monitortestframework.MonitorTestInitializationInfo{
+				ClusterStabilityDuringTest: monitortestframework.ClusterStabilityDuringTest(clusterStability),
+			})
+			if err != nil {
+				return err
+			}
+
+			requiringCluster := monitorTestRegistry.ListMonitorTestsRequiringCluster()
+			phases := monitorTestRegistry.ListMonitorTestPhases()
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tNEEDS CLUSTER\tPHASES")
+			for _, name := range monitorTestRegistry.ListMonitorTests().List() {
+				phaseNames := []string{}
+				for _, phase := range phases[name] {
+					phaseNames = append(phaseNames, string(phase))
+				}
+				fmt.Fprintf(w, "%s\t%v\t%s\n", name, requiringCluster.Has(name), strings.Join(phaseNames, ","))
+			}
+			return w.Flush()
+		},
+	}
+	cmd.Flags().StringVar(&clusterStability,
+		"cluster-stability", clusterStability,
+		"The cluster stability to list monitor tests for, Stable or Disruptive.")
+	return cmd
+}
@@ -0,0 +1,133 @@
+package dev
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/intervalquery"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/spf13/cobra"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+type queryOpts struct {
+	intervalsFile string
+	output        string
+}
+
+func newQueryCommand() *cobra.Command {
+	o := queryOpts{
+		output: "table",
+	}
+
+	cmd := &cobra.Command{
+		Use:   "query QUERY",
+		Short: "Print the intervals from an intervals file that match a query",
+		Long: templates.LongDesc(`
+Print the intervals from an intervals file that match a query.
+
+Comparisons on source, reason, cause, message, locator.type, keys.<key>,
+annotations.<key>, level, duration, from, to, and display are combined with and,
+or, not, and parentheses.  For instance
+
+  openshift-tests dev query --intervals-file e2e-events.json \
+    'source=Disruption and duration>5s and keys.backend-disruption-name~"ingress.*"'
+`),
+
+		SilenceUsage: true,
+		Args:         cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := ""
+			if len(args) > 0 {
+				query = args[0]
+			}
+			return o.Run(query, os.Stdout)
+		},
+	}
+	cmd.Flags().StringVar(&o.intervalsFile,
+		"intervals-file", "e2e-events.json",
+		"Path to an intervals file (i.e. e2e-events_20230214-203340.json). Can be obtained from a CI run in openshift-tests junit artifacts.")
+	cmd.Flags().StringVarP(&o.output,
+		"output", "o", o.output,
+		"Output format: table, json, or csv.")
+	return cmd
+}
+
+func (o *queryOpts) Run(query string, out io.Writer) error {
+	matches, err := intervalquery.Parse(query)
+	if err != nil {
+		return fmt.Errorf("invalid query: %w", err)
+	}
+	intervals, err := readIntervalsFromFile(o.intervalsFile)
+	if err != nil {
+		return fmt.Errorf("error loading intervals file: %w", err)
+	}
+	matching := intervals.Filter(matches)
+
+	switch o.output {
+	case "table":
+		return writeIntervalTable(out, matching)
+	case "json":
+		intervalsJSON, err := monitorserialization.IntervalsToJSON(matching)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(out, "%s\n", intervalsJSON)
+		return err
+	case "csv":
+		return writeIntervalCSV(out, matching)
+	}
+	return fmt.Errorf("unknown output %q, expected table, json, or csv", o.output)
+}
+
+var intervalColumns = []string{"FROM", "TO", "DURATION", "LEVEL", "SOURCE", "LOCATOR", "REASON", "MESSAGE"}
+
+func intervalRow(interval monitorapi.Interval) []string {
+	to, duration := "", ""
+	if !interval.To.IsZero() {
+		to = interval.To.UTC().Format(time.RFC3339)
+		duration = interval.To.Sub(interval.From).String()
+	}
+	return []string{
+		interval.From.UTC().Format(time.RFC3339),
+		to,
+		duration,
+		interval.Level.String(),
+		string(interval.Source),
+		interval.Locator.OldLocator(),
+		string(interval.Message.Reason),
+		interval.Message.HumanMessage,
+	}
+}
+
+func writeIntervalTable(out io.Writer, intervals monitorapi.Intervals) error {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(intervalColumns, "\t"))
+	for _, interval := range intervals {
+		row := intervalRow(interval)
+		// keep every interval on a single line of the table.
+		row[len(row)-1] = strings.ReplaceAll(row[len(row)-1], "\n", `\n`)
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+func writeIntervalCSV(out io.Writer, intervals monitorapi.Intervals) error {
+	w := csv.NewWriter(out)
+	if err := w.Write(intervalColumns); err != nil {
+		return err
+	}
+	for _, interval := range intervals {
+		if err := w.Write(intervalRow(interval)); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,190 @@
+package dev
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/defaultmonitortests"
+	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+type replayOpts struct {
+	intervalsFile       string
+	resourcesDir        string
+	artifactDir         string
+	junitSuiteName      string
+	clusterStability    string
+	exactMonitorTests   []string
+	disableMonitorTests []string
+	offlineOnly         bool
+}
+
+func newReplayCommand() *cobra.Command {
+	o := replayOpts{
+		junitSuiteName:   "openshift-tests",
+		clusterStability: string(monitortestframework.Stable),
+	}
+
+	cmd := &cobra.Command{
+		Use:   "replay",
+		Short: "Run every monitor test against the intervals and tracked resources saved by a previous run",
+		Long: templates.LongDesc(`
+Replay a previous run of the monitor tests from its artifacts without a cluster.
+
+The intervals file (e2e-events_<suffix>.json) and the tracked resources saved alongside
+it (resource-<type>_<suffix>.zip) are loaded into a recorder, then every registered
+monitor test constructs its computed intervals and evaluates its tests.  The junit and
+artifacts are written to --artifact-dir exactly as a live run would write them.
+
+Monitor tests that need state gathered from a live cluster may report failures, use
+--offline-only to replay only the monitor tests that do not collect from a cluster.
+`),
+
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Run(context.Background())
+		},
+	}
+	cmd.Flags().StringVar(&o.intervalsFile,
+		"intervals-file", o.intervalsFile,
+		"Path to an intervals file (i.e. e2e-events_20230214-203340.json). Can be obtained from a CI run in openshift-tests junit artifacts.")
+	cmd.Flags().StringVar(&o.resourcesDir,
+		"resources-dir", o.resourcesDir,
+		"Directory containing the resource-<type>_<suffix>.zip files from the same run.  Defaults to the directory of --intervals-file.")
+	cmd.Flags().StringVar(&o.artifactDir,
+		"artifact-dir", o.artifactDir,
+		"The directory where junit and monitor test artifacts will be written.")
+	cmd.Flags().StringVar(&o.junitSuiteName,
+		"junit-suite-name", o.junitSuiteName,
+		"The name of the junit suite the monitor tests are reported in.")
+	cmd.Flags().StringVar(&o.clusterStability,
+		"cluster-stability", o.clusterStability,
+		"The cluster stability of the replayed run, Stable or Disruptive.  Determines which monitor tests are registered.")
+	cmd.Flags().StringSliceVar(&o.exactMonitorTests,
+		"monitor", o.exactMonitorTests,
+		"list of exactly which monitors to replay. All others will be disabled.")
+	cmd.Flags().StringSliceVar(&o.disableMonitorTests,
+		"disable-monitor", o.disableMonitorTests,
+		"list of monitors to disable.  Defaults for others will be honored.")
+	cmd.Flags().BoolVar(&o.offlineOnly,
+		"offline-only", o.offlineOnly,
+		"Only replay the monitor tests that do not collect data from or clean up a cluster.")
+	cmd.MarkFlagRequired("intervals-file")
+	cmd.MarkFlagRequired("artifact-dir")
+	return cmd
+}
+
+func (o *replayOpts) Run(ctx context.Context) error {
+	logrus.WithField("intervalsFile", o.intervalsFile).Info("loading e2e intervals")
+	intervals, err := readIntervalsFromFile(o.intervalsFile)
+	if err != nil {
+		return fmt.Errorf("error loading intervals file: %w", err)
+	}
+	logrus.Infof("loaded %d intervals", len(intervals))
+
+	// e2e-events_20230214-203340.json was written with the time suffix _20230214-203340
+	timeSuffix := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(o.intervalsFile), "e2e-events"), ".json")
+
+	resourcesDir := o.resourcesDir
+	if len(resourcesDir) == 0 {
+		resourcesDir = filepath.Dir(o.intervalsFile)
+	}
+	resources, err := readResourcesFromDir(resourcesDir, timeSuffix)
+	if err != nil {
+		return fmt.Errorf("error loading tracked resources: %w", err)
+	}
+
+	monitorTestRegistry, err := defaultmonitortests.NewMonitorTestsFor(monitortestframework.MonitorTestInitializationInfo{
+		ClusterStabilityDuringTest: monitortestframework.ClusterStabilityDuringTest(o.clusterStability),
+		ExactMonitorTests:          o.exactMonitorTests,
+		DisableMonitorTests:        o.disableMonitorTests,
+	})
+	if err != nil {
+		return err
+	}
+	if requiringCluster := monitorTestRegistry.ListMonitorTestsRequiringCluster(); o.offlineOnly {
+		monitorTestRegistry, err = monitorTestRegistry.GetRegistryFor(monitorTestRegistry.ListOfflineMonitorTests().List()...)
+		if err != nil {
+			return err
+		}
+	} else if len(requiringCluster) > 0 {
+		logrus.Warnf("these monitor tests collect from a cluster and may fail without their collected state: %s", strings.Join(requiringCluster.List(), ", "))
+	}
+
+	if err := os.MkdirAll(o.artifactDir, os.ModePerm); err != nil {
+		return err
+	}
+
+	startTime, stopTime := intervalBounds(intervals)
+	logrus.Infof("replaying monitor tests from %s to %s", startTime, stopTime)
+	resultState, err := monitor.Replay(
+		ctx,
+		monitor.NewReplayRecorder(intervals, resources),
+		o.artifactDir,
+		monitorTestRegistry,
+		startTime,
+		stopTime,
+		o.junitSuiteName,
+		timeSuffix,
+	)
+	if err != nil {
+		return err
+	}
+	logrus.Infof("monitor tests %s, results written to %s", resultState, o.artifactDir)
+	return nil
+}
+
+// readResourcesFromDir loads every tracked resource file in dir that was written with timeSuffix.
+func readResourcesFromDir(dir, timeSuffix string) (monitorapi.ResourcesMap, error) {
+	resourceFiles, err := filepath.Glob(filepath.Join(dir, fmt.Sprintf("resource-*%s.zip", timeSuffix)))
+	if err != nil {
+		return nil, err
+	}
+
+	ret := monitorapi.ResourcesMap{}
+	for _, resourceFile := range resourceFiles {
+		logrus.WithField("resourceFile", resourceFile).Info("loading tracked resources")
+		resources, err := monitorserialization.InstanceMapFromFile(resourceFile)
+		if err != nil {
+			return nil, err
+		}
+		for resourceType, instances := range resources {
+			if _, ok := ret[resourceType]; !ok {
+				ret[resourceType] = monitorapi.InstanceMap{}
+			}
+			for key, obj := range instances {
+				ret[resourceType][key] = obj
+			}
+			logrus.Infof("loaded %d %s", len(instances), resourceType)
+		}
+	}
+	return ret, nil
+}
+
+// intervalBounds returns the earliest start and latest end of the intervals, which stand in for the start and stop
+// time of the monitor that recorded them.
+func intervalBounds(intervals monitorapi.Intervals) (time.Time, time.Time) {
+	var from, to time.Time
+	for _, interval := range intervals {
+		if !interval.From.IsZero() && (from.IsZero() || interval.From.Before(from)) {
+			from = interval.From
+		}
+		if interval.To.After(to) {
+			to = interval.To
+		}
+		if interval.From.After(to) {
+			to = interval.From
+		}
+	}
+	return from, to
+}
@@ -0,0 +1,40 @@
+package merge_results
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+func NewMergeResultsCommand() *cobra.Command {
+	o := &MergeResultsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "merge-results SHARD_DIR...",
+		Short: "Merge the results of a suite run as several shards into one run result",
+		Long: templates.LongDesc(`
+Merge the results of the shards of a suite into one run result.
+
+Each SHARD_DIR is the --junit-dir of one invocation of openshift-tests run with
+--shard-count and --shard-index.  The junit_e2e_*.xml suites, the
+test-failures-summary*.json files and the e2e-events*.json intervals of every shard
+are combined and written to --output-dir as if a single invocation had run the whole
+suite, so risk analysis and the interval tooling can be pointed at the result.
+
+The monitor tests only run on shard 0.  A synthetic test reported by several shards
+with the same outcome is kept once.
+`),
+
+		SilenceUsage: true,
+		Args:         cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.ShardDirs = args
+			o.Out = cmd.OutOrStdout()
+			return o.Run()
+		},
+	}
+	cmd.Flags().StringVar(&o.OutputDir,
+		"output-dir", o.OutputDir,
+		"The directory the merged results are written to.")
+	cmd.MarkFlagRequired("output-dir")
+	return cmd
+}
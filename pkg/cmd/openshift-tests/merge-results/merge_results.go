@@ -0,0 +1,220 @@
+package merge_results
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/riskanalysis"
+	"github.com/openshift/origin/pkg/test"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	junitFilePrefix     = "junit_e2e_"
+	intervalsFilePrefix = "e2e-events"
+)
+
+type MergeResultsOptions struct {
+	ShardDirs []string
+	OutputDir string
+
+	Out io.Writer
+}
+
+func (o *MergeResultsOptions) Run() error {
+	if err := os.MkdirAll(o.OutputDir, 0755); err != nil {
+		return err
+	}
+
+	junitFiles, err := globShards(o.ShardDirs, junitFilePrefix+"*.xml")
+	if err != nil {
+		return err
+	}
+	if len(junitFiles) == 0 {
+		return fmt.Errorf("no %s*.xml files found in %v", junitFilePrefix, o.ShardDirs)
+	}
+	// every file is named after the time its invocation started, the merged run is named after the first of them.
+	timeSuffix := ""
+	for _, junitFile := range junitFiles {
+		suffix := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(junitFile), junitFilePrefix), ".xml")
+		if len(timeSuffix) == 0 || suffix < timeSuffix {
+			timeSuffix = suffix
+		}
+	}
+
+	if err := o.mergeJUnit(junitFiles, timeSuffix); err != nil {
+		return err
+	}
+	if err := o.mergeTestFailureSummaries(timeSuffix); err != nil {
+		return err
+	}
+	return o.mergeIntervals(timeSuffix)
+}
+
+func (o *MergeResultsOptions) mergeJUnit(junitFiles []string, timeSuffix string) error {
+	suites := []*junitapi.JUnitTestSuite{}
+	for _, junitFile := range junitFiles {
+		content, err := os.ReadFile(junitFile)
+		if err != nil {
+			return err
+		}
+		suite := &junitapi.JUnitTestSuite{}
+		if err := xml.Unmarshal(content, suite); err != nil {
+			return fmt.Errorf("failed to read junit from %s: %w", junitFile, err)
+		}
+		suites = append(suites, suite)
+	}
+
+	merged := mergeJUnitSuites(suites)
+	out, err := xml.MarshalIndent(merged, "", "    ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(o.OutputDir, fmt.Sprintf("%s%s.xml", junitFilePrefix, timeSuffix))
+	fmt.Fprintf(o.Out, "Writing %d tests from %d shards to %s\n", merged.NumTests, len(suites), path)
+	return os.WriteFile(path, test.StripANSI(out), 0640)
+}
+
+// mergeJUnitSuites combines the suites of the shards.  The shards ran at the same time, so the merged suite took as
+// long as the slowest shard.  Every shard runs the e2e tests of its own, but each reports the synthetic tests of the run
+// against the same cluster.  A result already reported by an earlier shard, with the same name and outcome, is dropped,
+// so a synthetic test that passed everywhere is reported once and one that failed on one shard only is a flake.
+func mergeJUnitSuites(suites []*junitapi.JUnitTestSuite) *junitapi.JUnitTestSuite {
+	merged := &junitapi.JUnitTestSuite{}
+	reported := sets.NewString()
+	for _, suite := range suites {
+		if len(merged.Name) == 0 {
+			merged.Name = suite.Name
+			merged.Properties = suite.Properties
+		}
+		if suite.Duration > merged.Duration {
+			merged.Duration = suite.Duration
+		}
+
+		// results repeated within a shard, like the failure and the pass of a flake, are all kept.
+		shardReported := sets.NewString()
+		for _, testCase := range suite.TestCases {
+			key := resultKey(testCase)
+			if reported.Has(key) {
+				continue
+			}
+			shardReported.Insert(key)
+
+			merged.NumTests++
+			switch {
+			case testCase.SkipMessage != nil:
+				merged.NumSkipped++
+			case testCase.FailureOutput != nil:
+				merged.NumFailed++
+			}
+			merged.TestCases = append(merged.TestCases, testCase)
+		}
+		reported = reported.Union(shardReported)
+		merged.Children = append(merged.Children, suite.Children...)
+	}
+	return merged
+}
+
+// resultKey identifies the name and outcome of a test result.
+func resultKey(testCase *junitapi.JUnitTestCase) string {
+	switch {
+	case testCase.SkipMessage != nil:
+		return "skipped/" + testCase.Name
+	case testCase.FailureOutput != nil:
+		return "failed/" + testCase.Name
+	default:
+		return "passed/" + testCase.Name
+	}
+}
+
+func (o *MergeResultsOptions) mergeTestFailureSummaries(timeSuffix string) error {
+	prowJobRuns := []*riskanalysis.ProwJobRun{}
+	for _, dir := range o.ShardDirs {
+		shardProwJobRuns, err := riskanalysis.ReadProwJobRuns(dir)
+		if err != nil {
+			return err
+		}
+		prowJobRuns = append(prowJobRuns, shardProwJobRuns...)
+	}
+	if len(prowJobRuns) == 0 {
+		fmt.Fprintf(o.Out, "No test failure summaries found, skipping\n")
+		return nil
+	}
+
+	merged, err := riskanalysis.MergeProwJobRuns(prowJobRuns)
+	if err != nil {
+		return err
+	}
+	return riskanalysis.WriteProwJobRun(o.OutputDir, timeSuffix, "", merged)
+}
+
+func (o *MergeResultsOptions) mergeIntervals(timeSuffix string) error {
+	intervalsFiles, err := globShards(o.ShardDirs, intervalsFilePrefix+"*.json")
+	if err != nil {
+		return err
+	}
+	if len(intervalsFiles) == 0 {
+		fmt.Fprintf(o.Out, "No intervals found, skipping\n")
+		return nil
+	}
+
+	shardIntervals := []monitorapi.Intervals{}
+	for _, intervalsFile := range intervalsFiles {
+		intervals, err := monitorserialization.EventsFromFile(intervalsFile)
+		if err != nil {
+			return fmt.Errorf("failed to read intervals from %s: %w", intervalsFile, err)
+		}
+		shardIntervals = append(shardIntervals, intervals)
+	}
+
+	merged, err := mergeIntervals(shardIntervals)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(o.OutputDir, fmt.Sprintf("%s%s.json", intervalsFilePrefix, timeSuffix))
+	fmt.Fprintf(o.Out, "Writing %d intervals to %s\n", len(merged), path)
+	return monitorserialization.EventsToFile(path, merged)
+}
+
+// mergeIntervals combines the intervals of the shards.  Every shard watches the same cluster, so the intervals about
+// the cluster itself are recorded once per shard and only one copy of each is kept.
+func mergeIntervals(shardIntervals []monitorapi.Intervals) (monitorapi.Intervals, error) {
+	seen := map[string]bool{}
+	merged := monitorapi.Intervals{}
+	for _, intervals := range shardIntervals {
+		for _, interval := range intervals {
+			key, err := monitorserialization.IntervalToOneLineJSON(interval)
+			if err != nil {
+				return nil, err
+			}
+			if seen[string(key)] {
+				continue
+			}
+			seen[string(key)] = true
+			merged = append(merged, interval)
+		}
+	}
+	sort.Sort(merged)
+	return merged, nil
+}
+
+// globShards returns the files matching pattern in every shard directory.
+func globShards(dirs []string, pattern string) ([]string, error) {
+	ret := []string{}
+	for _, dir := range dirs {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, matches...)
+	}
+	return ret, nil
+}
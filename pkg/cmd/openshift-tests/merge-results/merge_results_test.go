@@ -0,0 +1,101 @@
+package merge_results
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeJUnitSuites(t *testing.T) {
+	properties := []*junitapi.TestSuiteProperty{{Name: "TestVersion", Value: "v1"}}
+	merged := mergeJUnitSuites([]*junitapi.JUnitTestSuite{
+		{
+			Name:       "openshift-tests",
+			NumTests:   2,
+			NumFailed:  1,
+			Duration:   300,
+			Properties: properties,
+			TestCases:  []*junitapi.JUnitTestCase{{Name: "a", FailureOutput: &junitapi.FailureOutput{}}, {Name: "b"}},
+		},
+		{
+			Name:       "openshift-tests",
+			NumTests:   2,
+			NumSkipped: 1,
+			Duration:   500,
+			TestCases:  []*junitapi.JUnitTestCase{{Name: "c"}, {Name: "d", SkipMessage: &junitapi.SkipMessage{}}},
+		},
+	})
+
+	assert.Equal(t, "openshift-tests", merged.Name)
+	assert.Equal(t, properties, merged.Properties)
+	assert.Equal(t, uint(4), merged.NumTests)
+	assert.Equal(t, uint(1), merged.NumFailed)
+	assert.Equal(t, uint(1), merged.NumSkipped)
+	assert.Equal(t, float64(500), merged.Duration)
+	names := []string{}
+	for _, testCase := range merged.TestCases {
+		names = append(names, testCase.Name)
+	}
+	assert.Equal(t, []string{"a", "b", "c", "d"}, names)
+}
+
+func TestMergeJUnitSuitesSyntheticTests(t *testing.T) {
+	failure := &junitapi.FailureOutput{Output: "failed"}
+	merged := mergeJUnitSuites([]*junitapi.JUnitTestSuite{
+		{
+			Name: "openshift-tests",
+			TestCases: []*junitapi.JUnitTestCase{
+				{Name: "e2e-a"},
+				{Name: "synthetic-pass"},
+				{Name: "synthetic-flake"},
+				// a flake within a shard keeps both results
+				{Name: "e2e-flake", FailureOutput: failure},
+				{Name: "e2e-flake"},
+			},
+		},
+		{
+			Name: "openshift-tests",
+			TestCases: []*junitapi.JUnitTestCase{
+				{Name: "e2e-b"},
+				{Name: "synthetic-pass"},
+				{Name: "synthetic-flake", FailureOutput: failure},
+			},
+		},
+		{
+			Name: "openshift-tests",
+			TestCases: []*junitapi.JUnitTestCase{
+				{Name: "synthetic-pass"},
+				{Name: "synthetic-flake", FailureOutput: failure},
+			},
+		},
+	})
+
+	names := []string{}
+	for _, testCase := range merged.TestCases {
+		names = append(names, testCase.Name)
+	}
+	assert.Equal(t, []string{"e2e-a", "synthetic-pass", "synthetic-flake", "e2e-flake", "e2e-flake", "e2e-b", "synthetic-flake"}, names)
+	assert.Equal(t, uint(7), merged.NumTests)
+	assert.Equal(t, uint(2), merged.NumFailed)
+}
+
+func TestMergeIntervals(t *testing.T) {
+	start := time.Date(2023, 2, 14, 20, 30, 0, 0, time.UTC)
+	interval := func(node string, from time.Time) monitorapi.Interval {
+		return monitorapi.NewInterval(monitorapi.SourceNodeState, monitorapi.Warning).
+			Locator(monitorapi.NewLocator().NodeFromName(node)).
+			Message(monitorapi.NewMessage().HumanMessage("not ready")).
+			Build(from, from.Add(time.Minute))
+	}
+	shared := interval("shared", start)
+	first := interval("first", start.Add(2*time.Minute))
+	second := interval("second", start.Add(time.Minute))
+
+	merged, err := mergeIntervals([]monitorapi.Intervals{{shared, first}, {shared, second}})
+	require.NoError(t, err)
+	assert.Equal(t, monitorapi.Intervals{shared, second, first}, merged)
+}
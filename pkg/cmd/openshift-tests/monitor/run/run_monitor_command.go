@@ -21,17 +21,21 @@ import (
 
 	"github.com/openshift/origin/pkg/defaultmonitortests"
 	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/livestream"
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/kubectl/pkg/util/templates"
 )
 
 type RunMonitorFlags struct {
-	ArtifactDir         string
-	DisplayFromNow      bool
-	ExactMonitorTests   []string
-	DisableMonitorTests []string
-	FromRepository      string
+	ArtifactDir          string
+	DisplayFromNow       bool
+	ExactMonitorTests    []string
+	DisableMonitorTests  []string
+	MonitorPhaseTimeouts []string
+	IntervalSpillDir     string
+	LiveStreamAddress    string
+	FromRepository       string
 
 	genericclioptions.IOStreams
 }
@@ -88,6 +92,10 @@ func (f *RunMonitorFlags) BindFlags(flags *pflag.FlagSet) {
 	flags.StringSliceVar(&f.ExactMonitorTests, "monitor", f.ExactMonitorTests,
 		fmt.Sprintf("list of exactly which monitors to enable. All others will be disabled.  Current monitors are: [%s]", strings.Join(monitorNames, ", ")))
 	flags.StringSliceVar(&f.DisableMonitorTests, "disable-monitor", f.DisableMonitorTests, "list of monitors to disable.  Defaults for others will be honored.")
+	flags.StringSliceVar(&f.MonitorPhaseTimeouts, "monitor-phase-timeout", f.MonitorPhaseTimeouts,
+		"list of [<monitor>/]<phase>=<duration> bounding how long monitors may spend in a phase, for instance CollectData=30m.  Phases have no timeout unless one is set here, a duration of 0 disables the timeout.")
+	flags.StringVar(&f.IntervalSpillDir, "interval-spill-dir", f.IntervalSpillDir, "If set, monitor intervals are spilled to an append-only log in this directory instead of being held in memory.")
+	flags.StringVar(&f.LiveStreamAddress, "live-stream-address", f.LiveStreamAddress, "If set, serve the intervals and tracked resources over HTTP on this address, for instance localhost:8080.")
 	flags.StringVar(&f.FromRepository, "from-repository", f.FromRepository, "A container image repository to retrieve test images from.")
 }
 
@@ -109,28 +117,37 @@ func (f *RunMonitorFlags) ToOptions() (*RunMonitorOptions, error) {
 	}
 
 	return &RunMonitorOptions{
-		ArtifactDir:     f.ArtifactDir,
-		DisplayFilterFn: displayFilterFn,
-		MonitorTests:    monitorTestRegistry,
-		IOStreams:       f.IOStreams,
-		FromRepository:  f.FromRepository,
+		ArtifactDir:       f.ArtifactDir,
+		DisplayFilterFn:   displayFilterFn,
+		MonitorTests:      monitorTestRegistry,
+		IntervalSpillDir:  f.IntervalSpillDir,
+		LiveStreamAddress: f.LiveStreamAddress,
+		IOStreams:         f.IOStreams,
+		FromRepository:    f.FromRepository,
 	}, nil
 }
 
 func (f *RunMonitorFlags) getMonitorTestRegistry() (monitortestframework.MonitorTestRegistry, error) {
+	phaseTimeouts, err := monitortestframework.ParsePhaseTimeouts(f.MonitorPhaseTimeouts)
+	if err != nil {
+		return nil, err
+	}
 	monitorTestInfo := monitortestframework.MonitorTestInitializationInfo{
 		ClusterStabilityDuringTest: monitortestframework.Stable,
 		ExactMonitorTests:          f.ExactMonitorTests,
 		DisableMonitorTests:        f.DisableMonitorTests,
+		PhaseTimeouts:              phaseTimeouts,
 	}
 	return defaultmonitortests.NewMonitorTestsFor(monitorTestInfo)
 }
 
 type RunMonitorOptions struct {
-	ArtifactDir     string
-	DisplayFilterFn monitorapi.EventIntervalMatchesFunc
-	MonitorTests    monitortestframework.MonitorTestRegistry
-	FromRepository  string
+	ArtifactDir       string
+	DisplayFilterFn   monitorapi.EventIntervalMatchesFunc
+	MonitorTests      monitortestframework.MonitorTestRegistry
+	IntervalSpillDir  string
+	LiveStreamAddress string
+	FromRepository    string
 
 	genericclioptions.IOStreams
 }
@@ -168,7 +185,18 @@ func (o *RunMonitorOptions) Run() error {
 	}()
 	signal.Notify(abortCh, syscall.SIGINT, syscall.SIGTERM)
 
-	recorder := monitor.WrapWithJSONLRecorder(monitor.NewRecorder(), o.Out, o.DisplayFilterFn)
+	delegateRecorder, err := monitor.NewRecorderFor(o.IntervalSpillDir)
+	if err != nil {
+		return err
+	}
+	if len(o.LiveStreamAddress) > 0 {
+		var liveStream *livestream.Server
+		liveStream, delegateRecorder = livestream.NewServer(delegateRecorder, nil)
+		if err := liveStream.Start(ctx, o.LiveStreamAddress); err != nil {
+			return err
+		}
+	}
+	recorder := monitor.WrapWithJSONLRecorder(delegateRecorder, o.Out, o.DisplayFilterFn)
 	m := monitor.NewMonitor(
 		recorder,
 		restConfig,
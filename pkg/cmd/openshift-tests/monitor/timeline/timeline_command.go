@@ -9,6 +9,7 @@ import (
 
 	"github.com/openshift/origin/pkg/monitortests/testframework/timelineserializer"
 
+	"github.com/openshift/origin/pkg/monitor/intervalquery"
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
 	"github.com/openshift/origin/test/extended/testdata"
@@ -25,6 +26,7 @@ type TimelineOptions struct {
 
 	LocatorMatchers []string
 	Namespaces      []string
+	Query           string
 	OutputType      string
 	EndDate         string
 
@@ -96,6 +98,7 @@ func (o *TimelineOptions) Bind(flagset *pflag.FlagSet) error {
 	flagset.StringVar(&o.TimelineType, "type", o.TimelineType, "type of timeline to produce: "+strings.Join(sets.StringKeySet(o.KnownTimelines).List(), ","))
 	flagset.StringVar(&o.PodResourceFilename, "known-pods", o.PodResourceFilename, "resource-pods_<timestamp>.zip filename from openshift-tests.")
 	flagset.StringSliceVarP(&o.LocatorMatchers, "locator", "l", o.LocatorMatchers, "key=value selector for monitor event locators (where value is a regex).  for instance -lpod=openshift-etcd-installer.  The same key listed multiple times means an OR.  Each separate key is logically ANDed.  Precede value with a dash for anti-match")
+	flagset.StringVarP(&o.Query, "query", "q", o.Query, `interval query, for instance 'source=Disruption and duration>5s and keys.backend-disruption-name~"ingress.*"'.  Applied after the other filters.`)
 	flagset.StringVarP(&o.EndDate, "end-date", "e", o.EndDate, fmt.Sprintf("Stop date (default is one hour after latest event) in RFC3399 format in UTC timezone: %s", time.RFC3339))
 
 	return nil
@@ -129,6 +132,10 @@ func (o *TimelineOptions) Validate() error {
 		}
 	}
 
+	if _, err := intervalquery.Parse(o.Query); err != nil {
+		return fmt.Errorf("invalid --query: %w", err)
+	}
+
 	if len(o.EndDate) > 0 {
 		_, err := time.ParseInLocation(time.RFC3339, o.EndDate, time.UTC)
 		if err != nil {
@@ -170,6 +177,7 @@ func (o *TimelineOptions) ToTimeline() *Timeline {
 		LocatorMatcher:        locatorMatcher,
 		RemovedLocatorMatcher: inverseLocatorMatcher,
 		Namespaces:            o.Namespaces,
+		QueryFilter:           intervalquery.MustParse(o.Query),
 		EndDate:               endDateTime,
 
 		Renderer:       o.KnownRenderers[o.OutputType],
@@ -185,6 +193,7 @@ type Timeline struct {
 	LocatorMatcher        map[string][]*regexp.Regexp
 	RemovedLocatorMatcher map[string][]*regexp.Regexp
 	Namespaces            []string
+	QueryFilter           monitorapi.EventIntervalMatchesFunc
 	EndDate               *time.Time
 
 	Renderer       RenderFunc
@@ -210,6 +219,9 @@ func (o *Timeline) Run() error {
 	if len(o.RemovedLocatorMatcher) > 0 {
 		filteredEvents = filteredEvents.Filter(monitorapi.NotContainsAllParts(o.RemovedLocatorMatcher))
 	}
+	if o.QueryFilter != nil {
+		filteredEvents = filteredEvents.Filter(o.QueryFilter)
+	}
 	// compute intervals from raw
 	var to time.Time
 
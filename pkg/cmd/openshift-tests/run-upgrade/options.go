@@ -105,12 +105,17 @@ func (o *RunUpgradeSuiteOptions) Run(ctx context.Context) error {
 		return err
 	}
 
+	phaseTimeouts, err := monitortestframework.ParsePhaseTimeouts(o.GinkgoRunSuiteOptions.MonitorPhaseTimeouts)
+	if err != nil {
+		return err
+	}
 	// TODO the gingkoRunSuiteOptions needs to have flags then calculated options to express specified versus computed values
 	monitorTestInfo := monitortestframework.MonitorTestInitializationInfo{
 		ClusterStabilityDuringTest:        monitortestframework.Stable,
 		UpgradeTargetPayloadImagePullSpec: o.ToImage,
 		ExactMonitorTests:                 o.GinkgoRunSuiteOptions.ExactMonitorTests,
 		DisableMonitorTests:               o.GinkgoRunSuiteOptions.DisableMonitorTests,
+		PhaseTimeouts:                     phaseTimeouts,
 	}
 
 	o.GinkgoRunSuiteOptions.CommandEnv = o.TestCommandEnvironment()
@@ -80,10 +80,15 @@ func (o *RunSuiteOptions) Run(ctx context.Context) error {
 		stabilitySetting = o.Suite.ClusterStabilityDuringTest
 	}
 
+	phaseTimeouts, err := monitortestframework.ParsePhaseTimeouts(o.GinkgoRunSuiteOptions.MonitorPhaseTimeouts)
+	if err != nil {
+		return err
+	}
 	monitorTestInfo := monitortestframework.MonitorTestInitializationInfo{
 		ClusterStabilityDuringTest: monitortestframework.ClusterStabilityDuringTest(stabilitySetting),
 		ExactMonitorTests:          o.GinkgoRunSuiteOptions.ExactMonitorTests,
 		DisableMonitorTests:        o.GinkgoRunSuiteOptions.DisableMonitorTests,
+		PhaseTimeouts:              phaseTimeouts,
 	}
 
 	o.GinkgoRunSuiteOptions.CommandEnv = o.TestCommandEnvironment()
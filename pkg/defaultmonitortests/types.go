@@ -2,14 +2,18 @@ package defaultmonitortests
 
 import (
 	"fmt"
+	"os"
+	"strconv"
 
 	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/disruptioncorrelation"
 	"github.com/openshift/origin/pkg/monitortests/authentication/legacyauthenticationmonitortests"
 	"github.com/openshift/origin/pkg/monitortests/authentication/requiredsccmonitortests"
 	azuremetrics "github.com/openshift/origin/pkg/monitortests/cloud/azure/metrics"
 	"github.com/openshift/origin/pkg/monitortests/clusterversionoperator/legacycvomonitortests"
 	"github.com/openshift/origin/pkg/monitortests/clusterversionoperator/operatorstateanalyzer"
 	"github.com/openshift/origin/pkg/monitortests/clusterversionoperator/terminationmessagepolicy"
+	"github.com/openshift/origin/pkg/monitortests/etcd/disruptionetcd"
 	"github.com/openshift/origin/pkg/monitortests/etcd/etcdloganalyzer"
 	"github.com/openshift/origin/pkg/monitortests/etcd/legacyetcdmonitortests"
 	"github.com/openshift/origin/pkg/monitortests/imageregistry/disruptionimageregistry"
@@ -21,6 +25,7 @@ import (
 	"github.com/openshift/origin/pkg/monitortests/kubeapiserver/legacykubeapiservermonitortests"
 	"github.com/openshift/origin/pkg/monitortests/monitoring/disruptionmetricsapi"
 	"github.com/openshift/origin/pkg/monitortests/monitoring/statefulsetsrecreation"
+	"github.com/openshift/origin/pkg/monitortests/network/disruptionclusterdns"
 	"github.com/openshift/origin/pkg/monitortests/network/disruptioningress"
 	"github.com/openshift/origin/pkg/monitortests/network/disruptionpodnetwork"
 	"github.com/openshift/origin/pkg/monitortests/network/disruptionserviceloadbalancer"
@@ -33,7 +38,9 @@ import (
 	"github.com/openshift/origin/pkg/monitortests/storage/legacystoragemonitortests"
 	"github.com/openshift/origin/pkg/monitortests/testframework/additionaleventscollector"
 	"github.com/openshift/origin/pkg/monitortests/testframework/alertanalyzer"
+	"github.com/openshift/origin/pkg/monitortests/testframework/backendlatencyanalyzer"
 	"github.com/openshift/origin/pkg/monitortests/testframework/clusterinfoserializer"
+	"github.com/openshift/origin/pkg/monitortests/testframework/disruptioncorrelationanalyzer"
 	"github.com/openshift/origin/pkg/monitortests/testframework/disruptionexternalawscloudservicemonitoring"
 	"github.com/openshift/origin/pkg/monitortests/testframework/disruptionexternalazurecloudservicemonitoring"
 	"github.com/openshift/origin/pkg/monitortests/testframework/disruptionexternalgcpcloudservicemonitoring"
@@ -86,14 +93,16 @@ func NewMonitorTestsFor(info monitortestframework.MonitorTestInitializationInfo)
 		panic(fmt.Sprintf("unknown cluster stability level: %q", info.ClusterStabilityDuringTest))
 	}
 
+	if err := startingRegistry.SetPhaseTimeouts(info.PhaseTimeouts...); err != nil {
+		return nil, err
+	}
+
 	switch {
 	case len(info.ExactMonitorTests) > 0:
 		return startingRegistry.GetRegistryFor(info.ExactMonitorTests...)
 
 	case len(info.DisableMonitorTests) > 0:
-		testsToInclude := startingRegistry.ListMonitorTests()
-		testsToInclude.Delete(info.DisableMonitorTests...)
-		return startingRegistry.GetRegistryFor(testsToInclude.List()...)
+		return startingRegistry.GetRegistryWithout(info.DisableMonitorTests...)
 	}
 
 	return startingRegistry, nil
@@ -106,13 +115,15 @@ func newDefaultMonitorTests(info monitortestframework.MonitorTestInitializationI
 
 	monitorTestRegistry.AddMonitorTestOrDie("image-registry-availability", "Image Registry", disruptionimageregistry.NewAvailabilityInvariant())
 
-	monitorTestRegistry.AddMonitorTestOrDie("apiserver-availability", "kube-apiserver", disruptionlegacyapiservers.NewAvailabilityInvariant())
+	monitorTestRegistry.AddMonitorTestOrDie("apiserver-availability", "kube-apiserver", newAPIServerAvailability())
 	monitorTestRegistry.AddMonitorTestOrDie("apiserver-new-disruption-invariant", "kube-apiserver", disruptionnewapiserver.NewDisruptionInvariant())
 	monitorTestRegistry.AddMonitorTestOrDie("apiserver-incluster-availability", "kube-apiserver", disruptioninclusterapiserver.NewInvariantInClusterDisruption(info))
+	monitorTestRegistry.AddMonitorTestOrDie("etcd-availability", "etcd", disruptionetcd.NewAvailabilityInvariant())
 
 	monitorTestRegistry.AddMonitorTestOrDie("pod-network-avalibility", "Network / ovn-kubernetes", disruptionpodnetwork.NewPodNetworkAvalibilityInvariant(info))
 	monitorTestRegistry.AddMonitorTestOrDie("service-type-load-balancer-availability", "Networking / router", disruptionserviceloadbalancer.NewAvailabilityInvariant())
 	monitorTestRegistry.AddMonitorTestOrDie("ingress-availability", "Networking / router", disruptioningress.NewAvailabilityInvariant())
+	monitorTestRegistry.AddMonitorTestOrDie("cluster-dns-availability", "Networking / DNS", disruptionclusterdns.NewAvailabilityInvariant())
 
 	monitorTestRegistry.AddMonitorTestOrDie("alert-summary-serializer", "Test Framework", alertanalyzer.NewAlertSummarySerializer())
 	monitorTestRegistry.AddMonitorTestOrDie("metrics-endpoints-down", "Test Framework", metricsendpointdown.NewMetricsEndpointDown())
@@ -122,6 +133,8 @@ func newDefaultMonitorTests(info monitortestframework.MonitorTestInitializationI
 	monitorTestRegistry.AddMonitorTestOrDie("external-azure-cloud-service-availability", "Test Framework", disruptionexternalazurecloudservicemonitoring.NewCloudAvailabilityInvariant())
 	monitorTestRegistry.AddMonitorTestOrDie("pathological-event-analyzer", "Test Framework", pathologicaleventanalyzer.NewAnalyzer())
 	monitorTestRegistry.AddMonitorTestOrDie("disruption-summary-serializer", "Test Framework", disruptionserializer.NewDisruptionSummarySerializer())
+	monitorTestRegistry.AddMonitorTestOrDie("backend-latency-analyzer", "Test Framework", backendlatencyanalyzer.NewAnalyzer())
+	monitorTestRegistry.AddMonitorTestOrDie("disruption-correlation-analyzer", "Test Framework", disruptioncorrelationanalyzer.NewAnalyzer(disruptioncorrelation.DefaultWindow))
 
 	monitorTestRegistry.AddMonitorTestOrDie("monitoring-statefulsets-recreation", "Monitoring", statefulsetsrecreation.NewStatefulsetsChecker())
 	monitorTestRegistry.AddMonitorTestOrDie("metrics-api-availability", "Monitoring", disruptionmetricsapi.NewAvailabilityInvariant())
@@ -129,6 +142,17 @@ func newDefaultMonitorTests(info monitortestframework.MonitorTestInitializationI
 	return monitorTestRegistry
 }
 
+// samplerParityEnv opts in to sampling the apiservers with the samplers of pkg/disruption/ci alongside the backend
+// samplers, and failing where the two disagree.
+const samplerParityEnv = "OPENSHIFT_TESTS_DISRUPTION_SAMPLER_PARITY"
+
+func newAPIServerAvailability() monitortestframework.MonitorTest {
+	if samplerParity, _ := strconv.ParseBool(os.Getenv(samplerParityEnv)); samplerParity {
+		return disruptionlegacyapiservers.NewAvailabilityInvariantWithSamplerParity()
+	}
+	return disruptionlegacyapiservers.NewAvailabilityInvariant()
+}
+
 func newDisruptiveMonitorTests(info monitortestframework.MonitorTestInitializationInfo) monitortestframework.MonitorTestRegistry {
 	monitorTestRegistry := monitortestframework.NewMonitorTestRegistry()
 
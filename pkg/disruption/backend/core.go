@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/openshift/origin/pkg/disruption/sampler"
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
@@ -27,11 +28,38 @@ func (f ClientFunc) Do(r *http.Request) (*http.Response, error) {
 type SampleResult struct {
 	RequestResponse
 	Sample *sampler.Sample
+
+	// Probe is set instead of RequestResponse when the
+	// backend is sampled with a protocol other than HTTP.
+	Probe *ProbeResult
 }
 
 func (s SampleResult) Succeeded() bool { return s.Sample.Err == nil }
 func (s SampleResult) Error() string   { return s.Sample.Err.Error() }
 func (s SampleResult) Err() error      { return s.Sample.Err }
+func (s SampleResult) String() string {
+	if s.Probe != nil {
+		return s.Probe.String()
+	}
+	return s.RequestResponse.String()
+}
+
+func (s SampleResult) Fields() map[string]interface{} {
+	if s.Probe != nil {
+		return s.Probe.Fields()
+	}
+	return s.RequestResponse.Fields()
+}
+
+// Latency returns how long the backend took to answer the sample,
+// the round trip of the request, or the duration of the probe.
+func (s SampleResult) Latency() time.Duration {
+	if s.Probe != nil {
+		return s.Probe.Duration
+	}
+	return s.RoundTripDuration
+}
+
 func (s SampleResult) AggregateErr() error {
 	err := s.Sample.Err
 	if s.ShutdownResponseHeaderParseErr != nil {
@@ -9,6 +9,13 @@ type ProtocolType string
 const (
 	ProtocolHTTP1 ProtocolType = "http1"
 	ProtocolHTTP2 ProtocolType = "http2"
+
+	// ProtocolTCP samples a backend by opening a TCP connection to it.
+	ProtocolTCP ProtocolType = "tcp"
+	// ProtocolDNS samples a backend by resolving a name with it.
+	ProtocolDNS ProtocolType = "dns"
+	// ProtocolGRPC samples a backend by calling its gRPC health service.
+	ProtocolGRPC ProtocolType = "grpc"
 )
 
 type LoadBalancerType string
@@ -7,6 +7,7 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/openshift/origin/pkg/disruption/backend"
+	backendsampler "github.com/openshift/origin/pkg/disruption/backend/sampler"
 	"github.com/openshift/origin/pkg/monitor/backenddisruption"
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 
@@ -70,6 +71,8 @@ func (h *ciHandler) Unavailable(from, to *backend.SampleResult) {
 	}
 	message, eventReason, level := backenddisruption.DisruptionBegan(h.descriptor.DisruptionLocator().OldLocator(),
 		h.descriptor.GetConnectionType(), fmt.Errorf("%w - %s", from.AggregateErr(), info), "no-audit-id")
+	// the category lets the disruption be broken down by cause
+	message = message.WithAnnotation(monitorapi.AnnotationErrorCategory, string(backendsampler.CategoryOf(from.Err())))
 
 	klog.V(4).Info(message)
 	h.eventRecorder.Eventf(
@@ -0,0 +1,306 @@
+package fake
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	"github.com/openshift/origin/pkg/disruption/sampler"
+
+	"k8s.io/utils/clock"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+// FaultType is a way in which the fake backend misbehaves.
+type FaultType string
+
+const (
+	// RefuseConnections refuses the connections dialed with the Transport
+	// of the backend, the connections of any other client are reset.
+	// A request that arrives on an existing connection has its
+	// connection reset.
+	RefuseConnections FaultType = "RefuseConnections"
+	// RespondWithStatus responds with StatusCode, and a Retry-After
+	// response header if RetryAfter is set.
+	RespondWithStatus FaultType = "RespondWithStatus"
+	// Shutdown advertises a graceful shutdown in progress in the
+	// 'X-OpenShift-Disruption' response header, the elapsed time
+	// of the shutdown starts with the fault.
+	Shutdown FaultType = "Shutdown"
+	// AddLatency delays the response by Latency.
+	AddLatency FaultType = "AddLatency"
+	// HalfClose closes the write side of the connection instead of
+	// responding, the client reads an EOF.
+	HalfClose FaultType = "HalfClose"
+	// GoAway responds, and then closes the connection, an HTTP/2
+	// connection is closed with a GOAWAY frame.
+	GoAway FaultType = "GoAway"
+)
+
+// Fault is a misbehavior of the backend during a window of time.
+type Fault struct {
+	Type FaultType
+
+	// At is when the fault begins, relative to when the backend started.
+	At time.Duration
+
+	// For is how long the fault lasts.
+	For time.Duration
+
+	// StatusCode is the status code of a RespondWithStatus fault.
+	StatusCode int
+
+	// RetryAfter, if set, is sent in the Retry-After response
+	// header of a RespondWithStatus fault, in seconds.
+	RetryAfter time.Duration
+
+	// Latency is the delay of an AddLatency fault.
+	Latency time.Duration
+}
+
+func (f Fault) activeAt(elapsed time.Duration) bool {
+	return elapsed >= f.At && elapsed < f.At+f.For
+}
+
+// Schedule is the script of faults of the backend, faults may overlap,
+// and every fault that is active when a request arrives applies to it.
+type Schedule []Fault
+
+func (s Schedule) activeAt(elapsed time.Duration) map[FaultType]Fault {
+	active := map[FaultType]Fault{}
+	for _, f := range s {
+		if f.activeAt(elapsed) {
+			active[f.Type] = f
+		}
+	}
+	return active
+}
+
+// Config allows a user to specify how the fake backend behaves
+type Config struct {
+	// Schedule is the faults of the backend, it serves every
+	// request successfully outside of them.
+	Schedule Schedule
+
+	// HTTP2 serves HTTP/2.0 if true, otherwise HTTP/1.x
+	HTTP2 bool
+
+	// Clock is the clock the schedule follows, the real clock if
+	// not specified. With a fake clock, the samples taken with Sample
+	// are deterministic, and the latency that is added steps the fake
+	// clock, so it is seen by the sample but never times it out.
+	Clock clock.Clock
+
+	// Hostname is the host advertised in the 'X-OpenShift-Disruption'
+	// response header, fake-backend if not specified.
+	Hostname string
+
+	// ShutdownDelayDuration is the shutdown-delay-duration advertised
+	// in the 'X-OpenShift-Disruption' response header, 70s if not
+	// specified.
+	ShutdownDelayDuration time.Duration
+}
+
+// Backend is an in-process HTTPS server that misbehaves according
+// to a scripted schedule of faults, it lets the disruption samplers
+// and trackers be tested end to end without a cluster.
+type Backend struct {
+	config Config
+	server *httptest.Server
+	start  time.Time
+}
+
+type connKeyType int
+
+const connKey connKeyType = iota
+
+// NewBackend starts a new fake backend with the given configuration,
+// the schedule starts now on the clock of the backend.
+// The caller should call Close when finished, to shut it down.
+func NewBackend(config Config) *Backend {
+	if config.Clock == nil {
+		config.Clock = clock.RealClock{}
+	}
+	if len(config.Hostname) == 0 {
+		config.Hostname = "fake-backend"
+	}
+	if config.ShutdownDelayDuration == 0 {
+		config.ShutdownDelayDuration = 70 * time.Second
+	}
+
+	b := &Backend{config: config}
+	b.server = httptest.NewUnstartedServer(b)
+	b.server.Listener = &listener{Listener: b.server.Listener, backend: b}
+	b.server.Config.ConnContext = func(ctx context.Context, c net.Conn) context.Context {
+		// the handler resets and half-closes the TCP connection underneath TLS
+		if tlsConn, ok := c.(*tls.Conn); ok {
+			c = tlsConn.NetConn()
+		}
+		return context.WithValue(ctx, connKey, c)
+	}
+	b.server.EnableHTTP2 = config.HTTP2
+	b.start = config.Clock.Now()
+	b.server.StartTLS()
+	return b
+}
+
+// URL is the base URL of the backend, of the form https://ipaddr:port
+func (b *Backend) URL() string {
+	return b.server.URL
+}
+
+// TLSClientConfig returns a TLS configuration that trusts the backend.
+func (b *Backend) TLSClientConfig() *tls.Config {
+	return b.server.Client().Transport.(*http.Transport).TLSClientConfig.Clone()
+}
+
+// Transport returns a new http.Transport that trusts the backend, and
+// whose dial is refused while the backend refuses connections.
+//
+//	reuseConnection: true if the underlying TCP connection should be
+//	  reused for multiple requests
+func (b *Backend) Transport(reuseConnection bool) *http.Transport {
+	transport := b.server.Client().Transport.(*http.Transport).Clone()
+	transport.DisableKeepAlives = !reuseConnection
+	dialer := &net.Dialer{}
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if _, refuse := b.config.Schedule.activeAt(b.elapsed())[RefuseConnections]; refuse {
+			return nil, &net.OpError{Op: "dial", Net: network, Addr: b.server.Listener.Addr(),
+				Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}
+		}
+		return dialer.DialContext(ctx, network, addr)
+	}
+	return transport
+}
+
+// Client returns a backend.Client that sends requests to the
+// backend with a new Transport.
+func (b *Backend) Client(reuseConnection bool) backend.Client {
+	return &http.Client{Transport: b.Transport(reuseConnection)}
+}
+
+// Close shuts down the backend, and blocks until all
+// outstanding requests have completed.
+func (b *Backend) Close() {
+	b.server.Close()
+}
+
+// Sample takes the given number of samples of the backend with the
+// ProducerConsumer, one every interval on the clock of the backend,
+// and then closes the consumer.
+// Unlike sampler.Runner, the samples are taken one after the other,
+// so with a fake clock every sample, and every interval computed
+// from the samples, is deterministic.
+func (b *Backend) Sample(pc sampler.ProducerConsumer, interval time.Duration, samples int) {
+	for id := uint64(1); id <= uint64(samples); id++ {
+		at := b.start.Add(time.Duration(id-1) * interval)
+		if wait := at.Sub(b.config.Clock.Now()); wait > 0 {
+			b.config.Clock.Sleep(wait)
+		}
+
+		sample := &sampler.Sample{ID: id, StartedAt: b.config.Clock.Now()}
+		custom, err := pc.Produce(context.Background(), id)
+		sample.FinishedAt = b.config.Clock.Now()
+		sample.Err = err
+		pc.Consume(sample, custom)
+	}
+	pc.Close()
+}
+
+func (b *Backend) elapsed() time.Duration {
+	return b.config.Clock.Since(b.start)
+}
+
+func (b *Backend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	active := b.config.Schedule.activeAt(b.elapsed())
+	conn, _ := r.Context().Value(connKey).(*net.TCPConn)
+
+	if _, refuse := active[RefuseConnections]; refuse && conn != nil {
+		reset(conn)
+		return
+	}
+	if f, ok := active[AddLatency]; ok {
+		b.sleep(r.Context(), f.Latency)
+	}
+	if _, halfClose := active[HalfClose]; halfClose && conn != nil {
+		conn.CloseWrite()
+		return
+	}
+
+	// like the kube-apiserver, we send the shutdown response
+	// header to the clients that opt in to receive it
+	if r.Header.Get("X-Openshift-If-Disruption") == "true" {
+		shutdown, elapsed := false, time.Duration(0)
+		if f, ok := active[Shutdown]; ok {
+			shutdown, elapsed = true, b.elapsed()-f.At
+		}
+		w.Header().Set("X-Openshift-Disruption", fmt.Sprintf("shutdown=%t shutdown-delay-duration=%s elapsed=%s host=%s",
+			shutdown, b.config.ShutdownDelayDuration, elapsed, b.config.Hostname))
+	}
+	if _, goAway := active[GoAway]; goAway {
+		// the HTTP/2 server sends a GOAWAY frame for 'Connection: close'
+		w.Header().Set("Connection", "close")
+	}
+
+	code := http.StatusOK
+	if f, ok := active[RespondWithStatus]; ok {
+		code = f.StatusCode
+		if f.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(f.RetryAfter.Seconds())))
+		}
+	}
+	w.WriteHeader(code)
+	w.Write([]byte(http.StatusText(code)))
+}
+
+// sleep steps a fake clock, and waits for the real clock
+// unless the request is canceled first.
+func (b *Backend) sleep(ctx context.Context, d time.Duration) {
+	if fakeClock, ok := b.config.Clock.(*testingclock.FakeClock); ok {
+		fakeClock.Step(d)
+		return
+	}
+	select {
+	case <-b.config.Clock.After(d):
+	case <-ctx.Done():
+	}
+}
+
+// listener resets the connections accepted while the backend
+// refuses connections, the clients that do not dial with the
+// Transport of the backend see them reset instead of refused.
+type listener struct {
+	net.Listener
+	backend *Backend
+}
+
+func (l *listener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return conn, err
+		}
+		if _, refuse := l.backend.config.Schedule.activeAt(l.backend.elapsed())[RefuseConnections]; !refuse {
+			return conn, nil
+		}
+		if tcpConn, ok := conn.(*net.TCPConn); ok {
+			reset(tcpConn)
+			continue
+		}
+		conn.Close()
+	}
+}
+
+// reset closes the connection with a TCP RST rather than a FIN
+func reset(conn *net.TCPConn) {
+	conn.SetLinger(0)
+	conn.Close()
+}
@@ -0,0 +1,196 @@
+package fake
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	"github.com/openshift/origin/pkg/disruption/backend/disruption"
+	"github.com/openshift/origin/pkg/disruption/backend/latency"
+	"github.com/openshift/origin/pkg/disruption/backend/roundtripper"
+	backendsampler "github.com/openshift/origin/pkg/disruption/backend/sampler"
+	"github.com/openshift/origin/pkg/disruption/backend/shutdown"
+	"github.com/openshift/origin/pkg/disruption/ci"
+	"github.com/openshift/origin/pkg/disruption/sampler"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+
+	"k8s.io/client-go/tools/events"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestBackendFaults(t *testing.T) {
+	schedule := Schedule{
+		{Type: GoAway, At: time.Second, For: time.Second},
+		{Type: RefuseConnections, At: 4 * time.Second, For: 2 * time.Second},
+		{Type: HalfClose, At: 8 * time.Second, For: time.Second},
+		{Type: AddLatency, At: 11 * time.Second, For: time.Second, Latency: 3 * time.Second},
+	}
+	tests := []struct {
+		name     string
+		protocol backend.ProtocolType
+		want     []string
+	}{
+		{
+			name:     "http/2.0",
+			protocol: backend.ProtocolHTTP2,
+			want: []string{
+				"1 at=0s duration=0s reused=false category=<none>",
+				// the response to sample 2 comes with a GOAWAY
+				"2 at=1s duration=0s reused=true category=<none>",
+				"3 at=2s duration=0s reused=false category=<none>",
+				"4 at=3s duration=0s reused=true category=<none>",
+				// the connection in use is reset, and then a new one is refused
+				"5 at=4s duration=0s reused=true category=ConnectionReset",
+				"6 at=5s duration=0s reused= category=ConnectionRefused",
+				"7 at=6s duration=0s reused=false category=<none>",
+				"8 at=7s duration=0s reused=true category=<none>",
+				"9 at=8s duration=0s reused=true category=Unknown",
+				"10 at=9s duration=0s reused=false category=<none>",
+				"11 at=10s duration=0s reused=true category=<none>",
+				"12 at=11s duration=3s reused=true category=<none>",
+			},
+		},
+		{
+			name:     "http/1.1",
+			protocol: backend.ProtocolHTTP1,
+			want: []string{
+				"1 at=0s duration=0s reused=false category=<none>",
+				"2 at=1s duration=0s reused=true category=<none>",
+				"3 at=2s duration=0s reused=false category=<none>",
+				"4 at=3s duration=0s reused=true category=<none>",
+				// the client retries the request with a new connection when
+				// the one in use is reset, the new one is refused
+				"5 at=4s duration=0s reused=true category=ConnectionRefused",
+				"6 at=5s duration=0s reused= category=ConnectionRefused",
+				"7 at=6s duration=0s reused=false category=<none>",
+				"8 at=7s duration=0s reused=true category=<none>",
+				// the retry on a new connection is half-closed too
+				"9 at=8s duration=0s reused=false category=Unknown",
+				"10 at=9s duration=0s reused=false category=<none>",
+				"11 at=10s duration=0s reused=true category=<none>",
+				"12 at=11s duration=3s reused=true category=<none>",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			start := time.Date(2023, 2, 14, 20, 30, 0, 0, time.UTC)
+			b := NewBackend(Config{
+				HTTP2:    test.protocol == backend.ProtocolHTTP2,
+				Clock:    testingclock.NewFakeClock(start),
+				Schedule: schedule,
+			})
+			defer b.Close()
+
+			spy := &spy{start: start}
+			b.Sample(newPipeline(t, b, descriptor(test.protocol, monitorapi.ReusedConnectionType), &Recorder{}, spy), time.Second, 12)
+			if !reflect.DeepEqual(test.want, spy.got) {
+				t.Errorf("unexpected samples: %s", cmp.Diff(test.want, spy.got))
+			}
+		})
+	}
+}
+
+func TestBackendPipeline(t *testing.T) {
+	start := time.Date(2023, 2, 14, 20, 30, 0, 0, time.UTC)
+	b := NewBackend(Config{
+		HTTP2: true,
+		Clock: testingclock.NewFakeClock(start),
+		Schedule: Schedule{
+			{Type: RefuseConnections, At: 2 * time.Second, For: 2 * time.Second},
+			{Type: RespondWithStatus, At: 6 * time.Second, For: 2 * time.Second, StatusCode: http.StatusServiceUnavailable},
+			// the load balancer keeps sending requests to a server that has
+			// stopped accepting them at the end of its graceful shutdown
+			{Type: Shutdown, At: 10 * time.Second, For: 4 * time.Second},
+			{Type: RespondWithStatus, At: 12 * time.Second, For: 2 * time.Second, StatusCode: http.StatusTooManyRequests, RetryAfter: time.Second},
+			{Type: HalfClose, At: 16 * time.Second, For: time.Second},
+		},
+		ShutdownDelayDuration: 5 * time.Second,
+	})
+	defer b.Close()
+
+	r := &Recorder{}
+	b.Sample(newPipeline(t, b, descriptor(backend.ProtocolHTTP2, monitorapi.NewConnectionType), r, nil), time.Second, 19)
+
+	want := []string{
+		"Disruption Info DisruptionEnded 0s-0s",
+		// every request has its own URL, so each refused connection has an error of its own
+		"Disruption Error DisruptionBegan ConnectionRefused 2s-3s",
+		"Disruption Error DisruptionBegan ConnectionRefused 3s-4s",
+		"Disruption Info DisruptionEnded 4s-6s",
+		"Disruption Error DisruptionBegan HTTP5xx 6s-8s",
+		"Disruption Info DisruptionEnded 8s-12s",
+		// the shutdown interval ends 15s after the shutdown delay
+		"APIServerShutdown Error GracefulShutdownInterval 10s-30s",
+		"Disruption Error DisruptionBegan FaultyLoadBalancer 12s-14s",
+		"Disruption Info DisruptionEnded 14s-16s",
+		"Disruption Error DisruptionBegan Unknown 16s-17s",
+		"Disruption Info DisruptionEnded 17s-18s",
+		"BackendLatency Info LatencySummary 0s-18s",
+	}
+	got := []string{}
+	for _, interval := range r.Intervals() {
+		s := fmt.Sprintf("%s %s %s", interval.Source, interval.Level, interval.Message.Reason)
+		if category, ok := interval.Message.Annotations[monitorapi.AnnotationErrorCategory]; ok {
+			s = fmt.Sprintf("%s %s", s, category)
+		}
+		got = append(got, fmt.Sprintf("%s %s-%s", s, interval.From.Sub(start), interval.To.Sub(start)))
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("unexpected intervals: %s", cmp.Diff(want, got))
+	}
+}
+
+func descriptor(protocol backend.ProtocolType, connectionType monitorapi.BackendConnectionType) ci.TestDescriptor {
+	return ci.TestDescriptor{
+		TargetServer:     ci.KubeAPIServer,
+		LoadBalancerType: backend.ExternalLoadBalancerType,
+		ConnectionType:   connectionType,
+		Protocol:         protocol,
+	}
+}
+
+// newPipeline wires the collectors the way the disruption test
+// factory does, and returns the ProducerConsumer that feeds them.
+func newPipeline(t *testing.T, b *Backend, descriptor ci.TestDescriptor, r *Recorder, delegate backendsampler.SampleCollector) sampler.ProducerConsumer {
+	eventRecorder := &events.FakeRecorder{}
+	collector, _ := shutdown.NewSharedShutdownIntervalTracker(delegate, descriptor, r, eventRecorder)
+	collector, _ = disruption.NewIntervalTracker(collector, descriptor, r, eventRecorder)
+	collector, _ = latency.NewLatencyTracker(collector, descriptor, latency.Config{}, r)
+
+	client, err := roundtripper.NewClient(roundtripper.Config{
+		RT:                           b.Transport(descriptor.ConnectionType == monitorapi.ReusedConnectionType),
+		ClientTimeout:                5 * time.Second,
+		UserAgent:                    descriptor.Name(),
+		EnableShutdownResponseHeader: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to create a new client: %v", err)
+	}
+	requestor := backendsampler.NewHostPathRequestor(b.URL(), "/healthz")
+	return backendsampler.NewSampleProducerConsumer(client, requestor, backendsampler.NewResponseChecker(), collector)
+}
+
+// spy describes every sample it collects
+type spy struct {
+	start time.Time
+	got   []string
+}
+
+func (s *spy) Collect(result backend.SampleResult) {
+	if result.Sample == nil {
+		return
+	}
+	category := "<none>"
+	if !result.Succeeded() {
+		category = string(backendsampler.CategoryOf(result.Err()))
+	}
+	s.got = append(s.got, fmt.Sprintf("%d at=%s duration=%s reused=%s category=%s", result.Sample.ID,
+		result.Sample.StartedAt.Sub(s.start), result.Sample.FinishedAt.Sub(result.Sample.StartedAt), result.ConnectionReused(), category))
+}
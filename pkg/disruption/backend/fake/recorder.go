@@ -0,0 +1,52 @@
+package fake
+
+import (
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ monitorapi.RecorderWriter = &Recorder{}
+
+// Recorder is a monitorapi.RecorderWriter that keeps the intervals
+// recorded through it, in the order they were started, so a test
+// can compare them with the intervals it expects.
+type Recorder struct {
+	lock      sync.Mutex
+	intervals monitorapi.Intervals
+}
+
+func (r *Recorder) RecordResource(resourceType string, obj runtime.Object)   {}
+func (r *Recorder) Record(conditions ...monitorapi.Condition)                {}
+func (r *Recorder) RecordAt(t time.Time, conditions ...monitorapi.Condition) {}
+
+func (r *Recorder) AddIntervals(intervals ...monitorapi.Interval) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.intervals = append(r.intervals, intervals...)
+}
+
+func (r *Recorder) StartInterval(interval monitorapi.Interval) int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.intervals = append(r.intervals, interval)
+	return len(r.intervals) - 1
+}
+
+func (r *Recorder) EndInterval(startedInterval int, t time.Time) *monitorapi.Interval {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.intervals[startedInterval].To = t
+	interval := r.intervals[startedInterval]
+	return &interval
+}
+
+// Intervals returns a copy of the intervals recorded so far.
+func (r *Recorder) Intervals() monitorapi.Intervals {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append(monitorapi.Intervals{}, r.intervals...)
+}
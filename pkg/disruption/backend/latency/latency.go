@@ -0,0 +1,159 @@
+package latency
+
+import (
+	"time"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	backendsampler "github.com/openshift/origin/pkg/disruption/backend/sampler"
+	"github.com/openshift/origin/pkg/disruption/sampler"
+	"github.com/openshift/origin/pkg/monitor/backenddisruption"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+
+	"k8s.io/client-go/tools/events"
+)
+
+const (
+	// DefaultWindow is the number of samples the percentile is
+	// computed over, a minute worth of samples at the default
+	// sample interval of 1s.
+	DefaultWindow = backenddisruption.DefaultLatencyWindow
+	// DefaultPercentile is the percentile compared to the threshold.
+	DefaultPercentile = backenddisruption.DefaultLatencyPercentile
+	// DefaultThreshold is the latency above which the backend is
+	// deemed degraded, the legacy backend sampler uses the same.
+	DefaultThreshold = backenddisruption.DefaultLatencyThreshold
+)
+
+// Config allows a user to specify when the latency of a backend is degraded,
+// a zero value uses the default.
+type Config struct {
+	// Window is the number of the most recent successful samples that
+	// the percentile is computed over.
+	Window int
+
+	// Percentile is the percentile of the window that is compared to the
+	// threshold, 0.95 for the P95.
+	Percentile float64
+
+	// Threshold is the latency above which the backend is degraded.
+	Threshold time.Duration
+}
+
+// NewLatencyTracker returns a SampleCollector that does the following:
+//
+//   - keeps a sliding window of the latencies of the successful samples,
+//     failed samples are disruption and are tracked by the interval tracker
+//
+//   - records a LatencyDegraded interval in CI for as long as the
+//     configured percentile of the window is above the threshold
+//
+//   - records a LatencySummary interval with the percentiles and a
+//     histogram of every latency once the last sample has arrived
+//
+//     delegate: the next SampleCollector in the chain to be invoked
+//     descriptor: the disruption test the samples belong to
+//     config: when the latency is degraded
+//     monitorRecorder: Monitor API to start and end an interval in CI
+func NewLatencyTracker(delegate backendsampler.SampleCollector, descriptor backend.TestDescriptor, config Config,
+	monitorRecorder monitorapi.RecorderWriter) (backendsampler.SampleCollector, backend.WantEventRecorderAndMonitorRecorder) {
+	if config.Window <= 0 {
+		config.Window = DefaultWindow
+	}
+	if config.Percentile <= 0 {
+		config.Percentile = DefaultPercentile
+	}
+	if config.Threshold <= 0 {
+		config.Threshold = DefaultThreshold
+	}
+	t := &tracker{
+		delegate:        delegate,
+		descriptor:      descriptor,
+		config:          config,
+		monitorRecorder: monitorRecorder,
+		window:          backenddisruption.NewLatencyWindow(config.Window, config.Percentile),
+		openIntervalID:  -1,
+	}
+	return t, t
+}
+
+var _ backend.WantEventRecorderAndMonitorRecorder = &tracker{}
+
+type tracker struct {
+	delegate        backendsampler.SampleCollector
+	descriptor      backend.TestDescriptor
+	config          Config
+	monitorRecorder monitorapi.RecorderWriter
+
+	// window holds the latencies of the successful samples
+	window *backenddisruption.LatencyWindow
+
+	openIntervalID int
+	first, last    *sampler.Sample
+}
+
+// SetEventRecorder is a no-op, latency is recorded with intervals only
+func (t *tracker) SetEventRecorder(events.EventRecorder) {}
+
+// SetMonitorRecorder sets the interval recorder provided by the monitor API
+func (t *tracker) SetMonitorRecorder(monitorRecorder monitorapi.RecorderWriter) {
+	t.monitorRecorder = monitorRecorder
+}
+
+func (t *tracker) Collect(bs backend.SampleResult) {
+	// we receive sample in ordered sequence, 1, 2, ... n
+	if t.delegate != nil {
+		t.delegate.Collect(bs)
+	}
+	t.collect(bs)
+}
+
+func (t *tracker) collect(result backend.SampleResult) {
+	if result.Sample == nil {
+		// no more sample arriving, close the open interval and summarize
+		t.finish()
+		return
+	}
+	if t.first == nil {
+		t.first = result.Sample
+	}
+	t.last = result.Sample
+	if !result.Succeeded() {
+		return
+	}
+
+	current, ok := t.window.Observe(result.Latency())
+	if !ok {
+		// too few samples for the percentile to mean anything
+		return
+	}
+	switch {
+	case current > t.config.Threshold && t.openIntervalID == -1:
+		message := backenddisruption.LatencyDegradedMessage(t.descriptor.DisruptionLocator().OldLocator(),
+			t.config.Percentile, current, t.config.Threshold, t.window.Len())
+		interval := monitorapi.NewInterval(monitorapi.SourceBackendLatency, monitorapi.Warning).
+			Locator(t.descriptor.DisruptionLocator()).
+			Display().
+			Message(message).Build(result.Sample.StartedAt, time.Time{})
+		t.openIntervalID = t.monitorRecorder.StartInterval(interval)
+	case current <= t.config.Threshold && t.openIntervalID != -1:
+		t.monitorRecorder.EndInterval(t.openIntervalID, result.Sample.StartedAt)
+		t.openIntervalID = -1
+	}
+}
+
+func (t *tracker) finish() {
+	if t.openIntervalID != -1 {
+		t.monitorRecorder.EndInterval(t.openIntervalID, t.last.FinishedAt)
+		t.openIntervalID = -1
+	}
+	latencies := t.window.Latencies()
+	if len(latencies) == 0 {
+		return
+	}
+	message := backenddisruption.LatencySummaryMessage(t.descriptor.DisruptionLocator().OldLocator(), latencies)
+	t.monitorRecorder.AddIntervals(
+		monitorapi.NewInterval(monitorapi.SourceBackendLatency, monitorapi.Info).
+			Locator(t.descriptor.DisruptionLocator()).
+			Message(message).Build(t.first.StartedAt, t.last.FinishedAt),
+	)
+}
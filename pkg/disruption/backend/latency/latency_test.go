@@ -0,0 +1,108 @@
+package latency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	"github.com/openshift/origin/pkg/disruption/sampler"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type descriptor struct{ backend.TestDescriptor }
+
+func (descriptor) DisruptionLocator() monitorapi.Locator {
+	return monitorapi.NewLocator().Disruption("etcd-grpc-internal-lb-reused-connections", "etcd-grpc-internal-lb",
+		"internal-lb", "grpc", "etcd", monitorapi.ReusedConnectionType)
+}
+
+// recorder keeps the intervals recorded through it.
+type recorder struct {
+	intervals monitorapi.Intervals
+}
+
+func (r *recorder) RecordResource(resourceType string, obj runtime.Object)   {}
+func (r *recorder) Record(conditions ...monitorapi.Condition)                {}
+func (r *recorder) RecordAt(t time.Time, conditions ...monitorapi.Condition) {}
+func (r *recorder) AddIntervals(intervals ...monitorapi.Interval) {
+	r.intervals = append(r.intervals, intervals...)
+}
+func (r *recorder) StartInterval(interval monitorapi.Interval) int {
+	r.intervals = append(r.intervals, interval)
+	return len(r.intervals) - 1
+}
+func (r *recorder) EndInterval(startedInterval int, t time.Time) *monitorapi.Interval {
+	r.intervals[startedInterval].To = t
+	return &r.intervals[startedInterval]
+}
+
+func TestLatencyTracker(t *testing.T) {
+	start := time.Date(2023, 2, 14, 20, 30, 0, 0, time.UTC)
+	sample := func(id uint64, latency time.Duration, err error) backend.SampleResult {
+		at := start.Add(time.Duration(id) * time.Second)
+		return backend.SampleResult{
+			Sample: &sampler.Sample{ID: id, StartedAt: at, FinishedAt: at.Add(latency), Err: err},
+			Probe:  &backend.ProbeResult{Protocol: backend.ProtocolGRPC, Duration: latency},
+		}
+	}
+
+	r := &recorder{}
+	collector, _ := NewLatencyTracker(nil, descriptor{}, Config{Window: 4, Percentile: 0.5, Threshold: time.Second}, r)
+	latencies := []time.Duration{
+		// the window is not full yet
+		3 * time.Second, 3 * time.Second, 10 * time.Millisecond,
+		// the median is above the threshold from sample 4 to 5
+		3 * time.Second, 10 * time.Millisecond, 20 * time.Millisecond, 30 * time.Millisecond,
+		// a failed sample is disruption, it does not count
+		0,
+		// degraded again from sample 11 until the end
+		5 * time.Second, 5 * time.Second, 5 * time.Second,
+	}
+	for i, latency := range latencies {
+		var err error
+		if latency == 0 {
+			err = context.DeadlineExceeded
+		}
+		collector.Collect(sample(uint64(i+1), latency, err))
+	}
+	collector.Collect(backend.SampleResult{})
+
+	if len(r.intervals) != 3 {
+		t.Fatalf("expected 2 degraded intervals and a summary, but got: %v", r.intervals)
+	}
+	first, second, summary := r.intervals[0], r.intervals[1], r.intervals[2]
+	for _, degraded := range []monitorapi.Interval{first, second} {
+		if degraded.Source != monitorapi.SourceBackendLatency || degraded.Message.Reason != monitorapi.LatencyDegradedEventReason || degraded.Level != monitorapi.Warning {
+			t.Errorf("expected a LatencyDegraded interval, but got: %v", degraded)
+		}
+	}
+	if want := "p50"; first.Message.Annotations[monitorapi.AnnotationPercentile] != want {
+		t.Errorf("expected percentile: %s, but got: %v", want, first.Message.Annotations)
+	}
+	if !first.From.Equal(start.Add(4*time.Second)) || !first.To.Equal(start.Add(5*time.Second)) {
+		t.Errorf("expected the first degraded interval from sample 4 to 5, but got: %s - %s", first.From, first.To)
+	}
+	if want := start.Add(11*time.Second + 5*time.Second); !second.From.Equal(start.Add(11*time.Second)) || !second.To.Equal(want) {
+		t.Errorf("expected the second degraded interval to end with the last sample, but got: %s - %s", second.From, second.To)
+	}
+
+	if summary.Message.Reason != monitorapi.LatencySummaryEventReason || summary.Level != monitorapi.Info {
+		t.Errorf("expected a LatencySummary interval, but got: %v", summary)
+	}
+	for key, want := range map[monitorapi.AnnotationKey]string{
+		monitorapi.AnnotationCount:      "10",
+		monitorapi.AnnotationLatencyP50: "3s",
+		monitorapi.AnnotationLatencyP99: "5s",
+		monitorapi.AnnotationHistogram:  "50ms=4 100ms=0 250ms=0 500ms=0 1s=0 2.5s=0 5s=6 10s=0 +Inf=0",
+	} {
+		if got := summary.Message.Annotations[key]; want != got {
+			t.Errorf("expected %s: %s, but got: %s", key, want, got)
+		}
+	}
+	if !summary.From.Equal(start.Add(time.Second)) || !summary.To.Equal(start.Add(16*time.Second)) {
+		t.Errorf("expected the summary to span every sample, but got: %s - %s", summary.From, summary.To)
+	}
+}
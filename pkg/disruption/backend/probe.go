@@ -0,0 +1,46 @@
+package backend
+
+import (
+	"fmt"
+	"time"
+)
+
+// ProbeResult holds the result of a probe sent to a backend that
+// is not an HTTP server, such as a TCP port, a DNS server, or a
+// gRPC health service.
+type ProbeResult struct {
+	// Protocol is the protocol used to probe the backend.
+	Protocol ProtocolType
+
+	// Target is the address of the backend that was probed.
+	Target string
+
+	// Detail describes what the backend returned, if anything,
+	// for example the addresses a name resolved to, or the
+	// serving status reported by a gRPC health service.
+	Detail string
+
+	// Duration is how long the probe took.
+	Duration time.Duration
+}
+
+func (p ProbeResult) String() string {
+	return fmt.Sprintf("protocol=%s target=%s detail=%s duration=%s",
+		p.Protocol, p.Target, p.detail(), p.Duration.Round(time.Millisecond))
+}
+
+func (p ProbeResult) Fields() map[string]interface{} {
+	fields := map[string]interface{}{}
+	fields["protocol"] = p.Protocol
+	fields["target"] = p.Target
+	fields["detail"] = p.detail()
+	fields["duration"] = p.Duration.Round(time.Millisecond)
+	return fields
+}
+
+func (p ProbeResult) detail() string {
+	if len(p.Detail) == 0 {
+		return "<none>"
+	}
+	return p.Detail
+}
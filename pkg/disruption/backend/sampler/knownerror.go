@@ -1,11 +1,53 @@
 package sampler
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"net"
+	"os"
+	"syscall"
+)
+
+// ErrorCategory classifies why a sample failed, it helps tell apart a
+// problem with the load balancer or the network from an outage of the
+// server itself.
+type ErrorCategory string
+
+const (
+	// ConnectionRefused: nothing is listening on the backend port,
+	// or the load balancer has no healthy member to send it to.
+	ConnectionRefused ErrorCategory = "ConnectionRefused"
+	// ConnectionReset: the connection was torn down by the peer.
+	ConnectionReset ErrorCategory = "ConnectionReset"
+	// IOTimeout: the dial, the request, or the probe timed out.
+	IOTimeout ErrorCategory = "IOTimeout"
+	// TLSHandshakeFailure: the TLS handshake with the backend failed.
+	TLSHandshakeFailure ErrorCategory = "TLSHandshakeFailure"
+	// NoRouteToHost: the host or the network of the backend is unreachable.
+	NoRouteToHost ErrorCategory = "NoRouteToHost"
+	// DNSError: the host name of the backend could not be resolved.
+	DNSError ErrorCategory = "DNSError"
+	// HTTP5xx: the server responded with a 5xx status code.
+	HTTP5xx ErrorCategory = "HTTP5xx"
+	// HTTP429: the server responded with 429 Too Many Requests.
+	HTTP429 ErrorCategory = "HTTP429"
+	// UnexpectedStatusCode: the server responded with a status code
+	// that is neither a success, a 5xx, nor 429.
+	UnexpectedStatusCode ErrorCategory = "UnexpectedStatusCode"
+	// BodyReadError: the response body could not be read.
+	BodyReadError ErrorCategory = "BodyReadError"
+	// FaultyLoadBalancer: the load balancer sent a request to a
+	// server that had already stopped accepting new requests.
+	FaultyLoadBalancer ErrorCategory = "FaultyLoadBalancer"
+	// Unknown: the error did not match any of the above.
+	Unknown ErrorCategory = "Unknown"
 )
 
 type KnownError struct {
-	category string
+	category ErrorCategory
 	err      error
 }
 
@@ -17,6 +59,66 @@ func (ke KnownError) Error() string {
 	return fmt.Sprintf("category: %s err: %v", ke.category, ke.err)
 }
 
-func (ke KnownError) Category() string {
+func (ke KnownError) Category() ErrorCategory {
 	return ke.category
 }
+
+// CategoryOf returns the category of the given sample error,
+// or Unknown if it has not been categorized.
+func CategoryOf(err error) ErrorCategory {
+	var known *KnownError
+	if errors.As(err, &known) {
+		return known.category
+	}
+	return Unknown
+}
+
+// NewKnownError returns the given error in the given category, for an
+// error that Categorize can not tell apart, like a bad status code.
+func NewKnownError(category ErrorCategory, err error) *KnownError {
+	return &KnownError{category: category, err: err}
+}
+
+// Categorize wraps the given error in a KnownError, unless it is one
+// already, the category is worked out from the error itself.
+func Categorize(err error) error {
+	if err == nil {
+		return nil
+	}
+	var known *KnownError
+	if errors.As(err, &known) {
+		return err
+	}
+	return &KnownError{category: categoryOf(err), err: err}
+}
+
+func categoryOf(err error) ErrorCategory {
+	var (
+		dnsErr         *net.DNSError
+		recordErr      tls.RecordHeaderError
+		alertErr       tls.AlertError
+		verifyErr      *tls.CertificateVerificationError
+		unknownAuthErr x509.UnknownAuthorityError
+		hostnameErr    x509.HostnameError
+		invalidErr     x509.CertificateInvalidError
+		netErr         net.Error
+	)
+	switch {
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return ConnectionRefused
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.ECONNABORTED), errors.Is(err, syscall.EPIPE):
+		return ConnectionReset
+	case errors.Is(err, syscall.EHOSTUNREACH), errors.Is(err, syscall.ENETUNREACH):
+		return NoRouteToHost
+	case errors.As(err, &dnsErr):
+		// a DNS lookup that times out is a DNSError, not an IOTimeout
+		return DNSError
+	case errors.As(err, &recordErr), errors.As(err, &alertErr), errors.As(err, &verifyErr),
+		errors.As(err, &unknownAuthErr), errors.As(err, &hostnameErr), errors.As(err, &invalidErr):
+		return TLSHandshakeFailure
+	case errors.Is(err, os.ErrDeadlineExceeded), errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return IOTimeout
+	}
+	return Unknown
+}
@@ -0,0 +1,100 @@
+package sampler
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+)
+
+func TestCheckError(t *testing.T) {
+	opErr := func(errno syscall.Errno) error {
+		return &url.Error{Op: "Get", URL: "https://api:6443/healthz", Err: &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", errno)}}
+	}
+	tests := []struct {
+		err  error
+		want ErrorCategory
+	}{
+		{err: opErr(syscall.ECONNREFUSED), want: ConnectionRefused},
+		{err: opErr(syscall.ECONNRESET), want: ConnectionReset},
+		{err: opErr(syscall.EHOSTUNREACH), want: NoRouteToHost},
+		{err: opErr(syscall.ENETUNREACH), want: NoRouteToHost},
+		{err: &url.Error{Op: "Get", URL: "https://api:6443/healthz", Err: &net.OpError{Op: "read", Net: "tcp", Err: os.ErrDeadlineExceeded}}, want: IOTimeout},
+		{err: fmt.Errorf("probe failed: %w", context.DeadlineExceeded), want: IOTimeout},
+		{err: &net.DNSError{Err: "no such host", Name: "api", IsNotFound: true}, want: DNSError},
+		{err: &net.DNSError{Err: "i/o timeout", Name: "api", IsTimeout: true}, want: DNSError},
+		{err: &url.Error{Op: "Get", URL: "https://api:6443/healthz", Err: x509.UnknownAuthorityError{}}, want: TLSHandshakeFailure},
+		{err: errors.New("something else"), want: Unknown},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.want), func(t *testing.T) {
+			err := NewResponseChecker().CheckError(tt.err)
+			if got := CategoryOf(err); tt.want != got {
+				t.Errorf("expected category: %s, but got: %s for: %v", tt.want, got, err)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("expected the original error to be wrapped, but got: %v", err)
+			}
+		})
+	}
+
+	if err := NewResponseChecker().CheckError(nil); err != nil {
+		t.Errorf("expected no error, but got: %v", err)
+	}
+}
+
+func TestCheckResponse(t *testing.T) {
+	tests := []struct {
+		name string
+		rr   backend.RequestResponse
+		want ErrorCategory
+	}{
+		{
+			name: "internal server error",
+			rr:   backend.RequestResponse{Response: &http.Response{StatusCode: http.StatusInternalServerError, Status: "500 Internal Server Error"}},
+			want: HTTP5xx,
+		},
+		{
+			name: "too many requests",
+			rr: backend.RequestResponse{Response: &http.Response{
+				StatusCode: http.StatusTooManyRequests, Status: "429 Too Many Requests", Header: http.Header{"Retry-After": []string{"1"}},
+			}},
+			want: HTTP429,
+		},
+		{
+			name: "not found",
+			rr:   backend.RequestResponse{Response: &http.Response{StatusCode: http.StatusNotFound, Status: "404 Not Found"}},
+			want: UnexpectedStatusCode,
+		},
+		{
+			name: "body read error",
+			rr: backend.RequestResponse{
+				Response:                     &http.Response{StatusCode: http.StatusOK, Status: "200 OK"},
+				RequestContextAssociatedData: backend.RequestContextAssociatedData{ResponseBodyReadErr: io.ErrUnexpectedEOF},
+			},
+			want: BodyReadError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewResponseChecker().CheckResponse(tt.rr)
+			if got := CategoryOf(err); tt.want != got {
+				t.Errorf("expected category: %s, but got: %s for: %v", tt.want, got, err)
+			}
+		})
+	}
+
+	ok := backend.RequestResponse{Response: &http.Response{StatusCode: http.StatusOK, Status: "200 OK"}}
+	if err := NewResponseChecker().CheckResponse(ok); err != nil {
+		t.Errorf("expected no error, but got: %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package sampler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	"github.com/openshift/origin/pkg/disruption/sampler"
+)
+
+// Prober knows how to probe a backend that is not an HTTP server,
+// the probe is the non HTTP equivalent of a request sent by the
+// Requestor and checked by the ResponseChecker.
+// A Prober that also implements io.Closer is closed when the
+// sampler is done.
+type Prober interface {
+	// GetTarget returns the address of the backend being probed.
+	GetTarget() string
+
+	// Probe probes the backend once, if it returns an error,
+	// the sample is deemed to have failed.
+	Probe(ctx context.Context, sampleID uint64) (backend.ProbeResult, error)
+}
+
+// NewProbeProducerConsumer returns a ProducerConsumer, the Producer
+// probes the backend with the given Prober, and the consumer feeds
+// the result of each probe to the specified SampleCollector, the
+// same way NewSampleProducerConsumer does for HTTP requests.
+//
+//	prober: a Prober that can probe the backend
+//	timeout: the maximum amount of time a single probe can take
+//	collector: user specified SampleCollector that will collect each
+//	 sample result for further analysis.
+func NewProbeProducerConsumer(prober Prober, timeout time.Duration, collector SampleCollector) sampler.ProducerConsumer {
+	return &probeProducerConsumer{
+		prober:    prober,
+		timeout:   timeout,
+		collector: collector,
+	}
+}
+
+type probeProducerConsumer struct {
+	prober    Prober
+	timeout   time.Duration
+	collector SampleCollector
+}
+
+func (pc *probeProducerConsumer) Produce(stop context.Context, sampleID uint64) (interface{}, error) {
+	// like an HTTP request, we want a probe in progress to be able
+	// to complete even if the stop context is Canceled.
+	ctx, cancel := context.WithTimeout(context.Background(), pc.timeout)
+	defer cancel()
+
+	started := time.Now()
+	result, err := pc.prober.Probe(ctx, sampleID)
+	result.Duration = time.Since(started)
+	if err != nil {
+		return result, Categorize(fmt.Errorf("%s probe of %s failed - err: %w", result.Protocol, result.Target, err))
+	}
+	return result, nil
+}
+
+func (pc *probeProducerConsumer) Consume(s *sampler.Sample, custom interface{}) {
+	// should never happen, we panic if for some programmer error
+	result := custom.(backend.ProbeResult)
+	pc.collector.Collect(backend.SampleResult{
+		Sample: s,
+		Probe:  &result,
+	})
+}
+
+func (pc *probeProducerConsumer) Close() {
+	if closer, ok := pc.prober.(io.Closer); ok {
+		closer.Close()
+	}
+	// no more sample available, send an empty value
+	pc.collector.Collect(backend.SampleResult{})
+}
@@ -0,0 +1,193 @@
+package sampler
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	"github.com/openshift/origin/pkg/disruption/sampler"
+)
+
+func TestTCPProber(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	address := listener.Addr().String()
+	prober := NewTCPProber(address)
+
+	result, err := prober.Probe(context.TODO(), 1)
+	if err != nil {
+		t.Errorf("expected no error, but got: %v", err)
+	}
+	if result.Protocol != backend.ProtocolTCP || result.Target != address {
+		t.Errorf("expected a tcp probe of %s, but got: %s", address, result)
+	}
+	if !strings.Contains(result.Detail, "remote="+address) {
+		t.Errorf("expected the remote address in the detail, but got: %s", result.Detail)
+	}
+
+	listener.Close()
+	if _, err := prober.Probe(context.TODO(), 2); err == nil {
+		t.Errorf("expected an error once the listener is closed")
+	}
+}
+
+func TestDNSProber(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer conn.Close()
+	go serveDNS(conn, map[string]net.IP{"kubernetes.default.svc.cluster.local.": net.ParseIP("172.30.0.1")})
+	prober := NewDNSProber(conn.LocalAddr().String(), "kubernetes.default.svc.cluster.local.")
+
+	result, err := prober.Probe(context.TODO(), 1)
+	if err != nil {
+		t.Errorf("expected no error, but got: %v", err)
+	}
+	if want := "kubernetes.default.svc.cluster.local.=172.30.0.1"; result.Detail != want {
+		t.Errorf("expected detail: %s, but got: %s", want, result.Detail)
+	}
+
+	prober = NewDNSProber(conn.LocalAddr().String(), "missing.default.svc.cluster.local.")
+	if _, err := prober.Probe(context.TODO(), 2); err == nil {
+		t.Errorf("expected an error for a name that does not resolve")
+	}
+}
+
+// serveDNS answers A queries for the given names, and NXDOMAIN for any other name.
+func serveDNS(conn net.PacketConn, names map[string]net.IP) {
+	buf := make([]byte, 512)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		query := buf[:n]
+		// the question starts right after the 12 byte header
+		name, end := []string{}, 12
+		for query[end] != 0 {
+			label := int(query[end])
+			name = append(name, string(query[end+1:end+1+label]))
+			end += label + 1
+		}
+		question := query[12 : end+5]
+		qtype := binary.BigEndian.Uint16(query[end+1:])
+
+		resp := append([]byte{}, query[:2]...)
+		ip, ok := names[strings.Join(name, ".")+"."]
+		switch {
+		case !ok:
+			resp = append(resp, 0x81, 0x83, 0, 1, 0, 0, 0, 0, 0, 0)
+			resp = append(resp, question...)
+		case qtype != 1:
+			// no AAAA record
+			resp = append(resp, 0x81, 0x80, 0, 1, 0, 0, 0, 0, 0, 0)
+			resp = append(resp, question...)
+		default:
+			resp = append(resp, 0x81, 0x80, 0, 1, 0, 1, 0, 0, 0, 0)
+			resp = append(resp, question...)
+			// a pointer to the name in the question, type A, class IN, ttl 30
+			resp = append(resp, 0xc0, 12, 0, 1, 0, 1, 0, 0, 0, 30, 0, 4)
+			resp = append(resp, ip.To4()...)
+		}
+		conn.WriteTo(resp, addr)
+	}
+}
+
+func TestGRPCHealthProber(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	server := grpc.NewServer()
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	for _, reuse := range []bool{false, true} {
+		healthServer.SetServingStatus("etcd", healthpb.HealthCheckResponse_SERVING)
+		prober := NewGRPCHealthProber(listener.Addr().String(), "etcd", reuse, grpc.WithTransportCredentials(insecure.NewCredentials())).(*grpcHealthProber)
+
+		result, err := prober.Probe(context.TODO(), 1)
+		if err != nil {
+			t.Errorf("reuse=%t: expected no error, but got: %v", reuse, err)
+		}
+		if result.Detail != "status=SERVING" {
+			t.Errorf("reuse=%t: expected detail: status=SERVING, but got: %s", reuse, result.Detail)
+		}
+
+		healthServer.SetServingStatus("etcd", healthpb.HealthCheckResponse_NOT_SERVING)
+		result, err = prober.Probe(context.TODO(), 2)
+		if err == nil {
+			t.Errorf("reuse=%t: expected an error for a service that is not serving", reuse)
+		}
+		if result.Detail != "status=NOT_SERVING" {
+			t.Errorf("reuse=%t: expected detail: status=NOT_SERVING, but got: %s", reuse, result.Detail)
+		}
+
+		if (prober.conn != nil) != reuse {
+			t.Errorf("reuse=%t: expected the connection to be kept only when it is reused", reuse)
+		}
+		if err := prober.Close(); err != nil {
+			t.Errorf("reuse=%t: expected no error on close, but got: %v", reuse, err)
+		}
+	}
+}
+
+type fakeProber struct {
+	errs []error
+}
+
+func (p *fakeProber) GetTarget() string { return "fake:53" }
+func (p *fakeProber) Probe(ctx context.Context, sampleID uint64) (backend.ProbeResult, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		return backend.ProbeResult{}, errors.New("expected a probe deadline")
+	}
+	return backend.ProbeResult{Protocol: backend.ProtocolDNS, Target: p.GetTarget()}, p.errs[sampleID-1]
+}
+
+type sampleResults []backend.SampleResult
+
+func (r *sampleResults) Collect(s backend.SampleResult) { *r = append(*r, s) }
+
+func TestProbeProducerConsumer(t *testing.T) {
+	collector := &sampleResults{}
+	pc := NewProbeProducerConsumer(&fakeProber{errs: []error{nil, os.ErrDeadlineExceeded}}, time.Second, collector)
+
+	for id := uint64(1); id <= 2; id++ {
+		custom, err := pc.Produce(context.TODO(), id)
+		pc.Consume(&sampler.Sample{ID: id, Err: err}, custom)
+	}
+	pc.Close()
+
+	if len(*collector) != 3 {
+		t.Fatalf("expected 2 samples and the end marker, but got: %d", len(*collector))
+	}
+	first, second, last := (*collector)[0], (*collector)[1], (*collector)[2]
+	if !first.Succeeded() || first.Probe == nil {
+		t.Errorf("expected the first sample to succeed with a probe result, but got: %+v", first)
+	}
+	if want := "protocol=dns target=fake:53 detail=<none>"; !strings.HasPrefix(first.String(), want) {
+		t.Errorf("expected the sample to describe the probe: %s, but got: %s", want, first.String())
+	}
+	if want := "category: IOTimeout err: dns probe of fake:53 failed - err: i/o timeout"; second.Succeeded() || second.Error() != want {
+		t.Errorf("expected the second sample to fail with: %s, but got: %+v", want, second)
+	}
+	if last.Sample != nil {
+		t.Errorf("expected an empty sample result to mark the end, but got: %+v", last)
+	}
+}
@@ -0,0 +1,159 @@
+package sampler
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+)
+
+// NewTCPProber returns a Prober that opens a new TCP connection to the
+// given address for every sample, and closes it right away, for
+// example to sample the etcd client port.
+func NewTCPProber(address string) Prober {
+	return &tcpProber{address: address}
+}
+
+type tcpProber struct {
+	address string
+	dialer  net.Dialer
+}
+
+func (p *tcpProber) GetTarget() string { return p.address }
+
+func (p *tcpProber) Probe(ctx context.Context, _ uint64) (backend.ProbeResult, error) {
+	result := backend.ProbeResult{Protocol: backend.ProtocolTCP, Target: p.address}
+	conn, err := p.dialer.DialContext(ctx, "tcp", p.address)
+	if err != nil {
+		return result, err
+	}
+	defer conn.Close()
+	result.Detail = fmt.Sprintf("local=%s remote=%s", conn.LocalAddr(), conn.RemoteAddr())
+	return result, nil
+}
+
+// NewDNSProber returns a Prober that resolves the given name with the DNS
+// server at the given address for every sample, for example to sample
+// cluster DNS.  The name should be fully qualified, with a trailing dot,
+// so the search domains of the host are not tried.
+// A name that resolves to no address fails the sample.
+func NewDNSProber(server, name string) Prober {
+	p := &dnsProber{server: server, name: name}
+	p.resolver = &net.Resolver{
+		// the cgo resolver does not let us pick the server
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return p.dialer.DialContext(ctx, network, p.server)
+		},
+	}
+	return p
+}
+
+type dnsProber struct {
+	server   string
+	name     string
+	dialer   net.Dialer
+	resolver *net.Resolver
+}
+
+func (p *dnsProber) GetTarget() string { return p.server }
+
+func (p *dnsProber) Probe(ctx context.Context, _ uint64) (backend.ProbeResult, error) {
+	result := backend.ProbeResult{Protocol: backend.ProtocolDNS, Target: p.server}
+	addrs, err := p.resolver.LookupHost(ctx, p.name)
+	if err != nil {
+		return result, err
+	}
+	if len(addrs) == 0 {
+		return result, fmt.Errorf("%s resolved to no address", p.name)
+	}
+	result.Detail = fmt.Sprintf("%s=%s", p.name, strings.Join(addrs, ","))
+	return result, nil
+}
+
+// NewGRPCHealthProber returns a Prober that calls the gRPC health service
+// at the given target for the given service, an empty service asks about
+// the server as a whole.  A sample fails unless the service is SERVING.
+// When reuseConnection is true, every sample goes over the same client
+// connection, otherwise a new connection is dialed for every sample.
+// The dial options must include the transport credentials.
+func NewGRPCHealthProber(target, service string, reuseConnection bool, opts ...grpc.DialOption) Prober {
+	return &grpcHealthProber{
+		target:          target,
+		service:         service,
+		reuseConnection: reuseConnection,
+		opts:            opts,
+	}
+}
+
+type grpcHealthProber struct {
+	target          string
+	service         string
+	reuseConnection bool
+	opts            []grpc.DialOption
+
+	lock sync.Mutex
+	// conn is the client connection shared by the samples when
+	// reuseConnection is true, nil until the first sample
+	conn *grpc.ClientConn
+}
+
+func (p *grpcHealthProber) GetTarget() string { return p.target }
+
+func (p *grpcHealthProber) Probe(ctx context.Context, _ uint64) (backend.ProbeResult, error) {
+	result := backend.ProbeResult{Protocol: backend.ProtocolGRPC, Target: p.target}
+	conn, err := p.connection(ctx)
+	if err != nil {
+		return result, err
+	}
+	if !p.reuseConnection {
+		defer conn.Close()
+	}
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: p.service})
+	if err != nil {
+		return result, err
+	}
+	result.Detail = fmt.Sprintf("status=%s", resp.GetStatus())
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return result, fmt.Errorf("service %q is not serving, status: %s", p.service, resp.GetStatus())
+	}
+	return result, nil
+}
+
+func (p *grpcHealthProber) connection(ctx context.Context) (*grpc.ClientConn, error) {
+	if !p.reuseConnection {
+		return grpc.DialContext(ctx, p.target, p.opts...)
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.conn == nil {
+		// the dial does not block, the shared connection
+		// reconnects on its own when the backend goes away
+		conn, err := grpc.Dial(p.target, p.opts...)
+		if err != nil {
+			return nil, err
+		}
+		p.conn = conn
+	}
+	return p.conn, nil
+}
+
+// Close closes the shared client connection, if any.
+func (p *grpcHealthProber) Close() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.conn == nil {
+		return nil
+	}
+	err := p.conn.Close()
+	p.conn = nil
+	return err
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/openshift/origin/pkg/disruption/backend"
 	"github.com/openshift/origin/pkg/disruption/sampler"
+	"github.com/openshift/origin/pkg/monitor/backenddisruption"
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	"k8s.io/kubernetes/test/e2e/framework"
 
@@ -14,19 +15,18 @@ import (
 	"k8s.io/client-go/tools/events"
 )
 
+// Sampler is a backenddisruption.Sampler that also describes the disruption
+// test it runs, so it can be used by any availability MonitorTest.
 type Sampler interface {
+	backenddisruption.Sampler
+
 	GetTargetServerName() string
 	GetLoadBalancerType() string
-	GetConnectionType() monitorapi.BackendConnectionType
 	GetProtocol() string
-	GetDisruptionBackendName() string
-	GetLocator() monitorapi.Locator
-	GetURL() (string, error)
-	RunEndpointMonitoring(ctx context.Context, m monitorapi.RecorderWriter, eventRecorder events.EventRecorder) error
-	StartEndpointMonitoring(ctx context.Context, m monitorapi.RecorderWriter, eventRecorder events.EventRecorder) error
-	Stop()
 }
 
+var _ Sampler = &BackendSampler{}
+
 // BackendSampler has the machinery to run a disruption test in CI
 type BackendSampler struct {
 	TestConfiguration
@@ -69,12 +69,21 @@ func (bs *BackendSampler) GetURL() (string, error) {
 }
 
 func (bs *BackendSampler) RunEndpointMonitoring(ctx context.Context, m monitorapi.RecorderWriter, eventRecorder events.EventRecorder) error {
-	defer close(bs.samplerFinished)
+	return bs.run(bs.withCancel(ctx), m, eventRecorder)
+}
 
+// withCancel returns a context that Stop cancels, it is set before the sampler
+// runs so that Stop never waits on a sampler it cannot cancel.
+func (bs *BackendSampler) withCancel(ctx context.Context) context.Context {
 	ctx, cancel := context.WithCancel(ctx)
 	bs.lock.Lock()
+	defer bs.lock.Unlock()
 	bs.cancel = cancel
-	bs.lock.Unlock()
+	return ctx
+}
+
+func (bs *BackendSampler) run(ctx context.Context, m monitorapi.RecorderWriter, eventRecorder events.EventRecorder) error {
+	defer close(bs.samplerFinished)
 
 	if eventRecorder == nil {
 		fakeEventRecorder := events.NewFakeRecorder(100)
@@ -121,8 +130,9 @@ func (bs *BackendSampler) StartEndpointMonitoring(ctx context.Context, m monitor
 		return fmt.Errorf("monitor is required")
 	}
 
+	ctx = bs.withCancel(ctx)
 	go func() {
-		err := bs.RunEndpointMonitoring(ctx, m, eventRecorder)
+		err := bs.run(ctx, m, eventRecorder)
 		if err != nil {
 			utilruntime.HandleError(err)
 		}
@@ -135,9 +145,11 @@ func (bs *BackendSampler) Stop() {
 	cancel := bs.cancel
 	bs.lock.Unlock()
 
-	if cancel != nil {
-		cancel()
+	// the sampler was never started, there is nothing to wait for
+	if cancel == nil {
+		return
 	}
+	cancel()
 
 	// wait for the sampler to be done
 	<-bs.samplerFinished
@@ -10,6 +10,7 @@ import (
 
 	"github.com/openshift/origin/pkg/disruption/backend"
 	"github.com/openshift/origin/pkg/disruption/backend/disruption"
+	"github.com/openshift/origin/pkg/disruption/backend/latency"
 	"github.com/openshift/origin/pkg/disruption/backend/logger"
 	"github.com/openshift/origin/pkg/disruption/backend/roundtripper"
 	backendsampler "github.com/openshift/origin/pkg/disruption/backend/sampler"
@@ -26,6 +27,9 @@ type ServerNameType string
 const (
 	KubeAPIServer      ServerNameType = "kube-api"
 	OpenShiftAPIServer ServerNameType = "openshift-api"
+	OAuthAPIServer     ServerNameType = "oauth-api"
+	Etcd               ServerNameType = "etcd"
+	ClusterDNS         ServerNameType = "cluster-dns"
 )
 
 // Factory creates a new instance of a Disruption test from
@@ -69,6 +73,10 @@ type TestConfiguration struct {
 	// response header extractor, this should be true only when the
 	// request(s) are being sent to the kube-apiserver.
 	EnableShutdownResponseHeader bool
+
+	// Latency specifies when the latency of the backend is degraded,
+	// the zero value uses the defaults of the latency package.
+	Latency latency.Config
 }
 
 // TestDescriptor defines the disruption test type, the user must
@@ -85,8 +93,9 @@ type TestDescriptor struct {
 	// by the requests should be new or reused.
 	ConnectionType monitorapi.BackendConnectionType
 
-	// Protocol specifies the protocol used by the test,
-	// whether it is http/1x or http/2.0
+	// Protocol specifies the protocol used by the test, whether it
+	// is http/1x or http/2.0, or tcp, dns or grpc for a test created
+	// with NewProbeSampler.
 	Protocol backend.ProtocolType
 }
 
@@ -185,6 +194,7 @@ func (b *testFactory) New(c TestConfiguration) (Sampler, error) {
 
 	// we don't have access to the monitor and event recorder yet
 	collector, want := disruption.NewIntervalTracker(b.sharedShutdownInterval, c, nil, nil)
+	collector, wantLatency := latency.NewLatencyTracker(collector, c, c.Latency, nil)
 	collector = logger.NewLogger(collector, c)
 
 	pc := backendsampler.NewSampleProducerConsumer(client, requestor, backendsampler.NewResponseChecker(), collector)
@@ -193,7 +203,7 @@ func (b *testFactory) New(c TestConfiguration) (Sampler, error) {
 	backendSampler := &BackendSampler{
 		TestConfiguration:           c,
 		SampleRunner:                runner,
-		wantEventRecorderAndMonitor: []backend.WantEventRecorderAndMonitorRecorder{b.wantMonitorAndRecorder, want},
+		wantEventRecorderAndMonitor: []backend.WantEventRecorderAndMonitorRecorder{b.wantMonitorAndRecorder, want, wantLatency},
 		baseURL:                     requestor.GetBaseURL(),
 		hostNameDecoder:             b.hostNameDecoder,
 		samplerFinished:             samplerFinished,
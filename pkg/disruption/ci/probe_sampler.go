@@ -0,0 +1,48 @@
+package ci
+
+import (
+	"fmt"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	"github.com/openshift/origin/pkg/disruption/backend/disruption"
+	"github.com/openshift/origin/pkg/disruption/backend/latency"
+	"github.com/openshift/origin/pkg/disruption/backend/logger"
+	backendsampler "github.com/openshift/origin/pkg/disruption/backend/sampler"
+	"github.com/openshift/origin/pkg/disruption/sampler"
+)
+
+// NewProbeSampler returns a disruption test for a backend that is not an
+// HTTP server, each sample is a probe sent by the given Prober, see
+// NewTCPProber, NewDNSProber, and NewGRPCHealthProber.  The disruption
+// intervals and the events are recorded the same way as they are for
+// the HTTP backends created by a Factory.
+// Path and EnableShutdownResponseHeader of the configuration are not used.
+func NewProbeSampler(c TestConfiguration, prober backendsampler.Prober) (Sampler, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	switch c.Protocol {
+	case backend.ProtocolTCP, backend.ProtocolDNS, backend.ProtocolGRPC:
+	default:
+		return nil, fmt.Errorf("Protocol %q can not be probed, want one of: %s, %s, %s",
+			c.Protocol, backend.ProtocolTCP, backend.ProtocolDNS, backend.ProtocolGRPC)
+	}
+	if c.Timeout <= 0 {
+		return nil, fmt.Errorf("Timeout must be positive")
+	}
+
+	// we don't have access to the monitor and event recorder yet
+	collector, want := disruption.NewIntervalTracker(nil, c, nil, nil)
+	collector, wantLatency := latency.NewLatencyTracker(collector, c, c.Latency, nil)
+	collector = logger.NewLogger(collector, c)
+
+	pc := backendsampler.NewProbeProducerConsumer(prober, c.Timeout, collector)
+	runner := sampler.NewWithProducerConsumer(c.SampleInterval, pc)
+	return &BackendSampler{
+		TestConfiguration:           c,
+		SampleRunner:                runner,
+		wantEventRecorderAndMonitor: []backend.WantEventRecorderAndMonitorRecorder{want, wantLatency},
+		baseURL:                     prober.GetTarget(),
+		samplerFinished:             make(chan struct{}),
+	}, nil
+}
@@ -5,6 +5,7 @@ import (
 	"container/list"
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net"
@@ -16,6 +17,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 
+	backendsampler "github.com/openshift/origin/pkg/disruption/backend/sampler"
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	v1 "k8s.io/api/core/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -385,15 +387,17 @@ func (b *BackendSampler) CheckConnection(ctx context.Context) (string, error) {
 	}
 
 	// we don't have an error, but the response code was an error, then we have to set an artificial error for the logic below to work.
+	// the errors are categorized the same way the samplers of pkg/disruption categorize theirs.
 	switch {
 	case getErr != nil:
-		sampleErr = getErr
+		sampleErr = backendsampler.Categorize(getErr)
 	case bodyReadErr != nil:
-		sampleErr = bodyReadErr
+		sampleErr = backendsampler.NewKnownError(backendsampler.BodyReadError, bodyReadErr)
 	case b.expectedStatusCode > 0 && b.expectedStatusCode == resp.StatusCode:
 		// don't fail
 	case resp.StatusCode < 200 || resp.StatusCode > 399:
-		sampleErr = fmt.Errorf("error running request: %v: %v", resp.Status, string(body))
+		sampleErr = backendsampler.NewKnownError(backendsampler.StatusCodeCategory(resp.StatusCode),
+			fmt.Errorf("error running request: %v: %v", resp.Status, string(body)))
 	default:
 		if bodyMatchErr := b.bodyMatches(body); bodyMatchErr != nil {
 			sampleErr = bodyMatchErr
@@ -525,26 +529,7 @@ func (b *disruptionSampler) produceSamples(ctx context.Context, interval time.Du
 		// returned.  Imagine a timeout set on a DNS lookup of 30s: when the GET finally fails and returns, the outage
 		// was actually 30s before.
 		currDisruptionSample := b.newSample(ctx)
-		go func() {
-			uid, sampleErr := b.backendSampler.CheckConnection(ctx)
-			currDisruptionSample.setSampleError(sampleErr)
-			currDisruptionSample.setRequestAuditID(uid)
-			if sampleErr != nil {
-				// We'd like to include these UUIDs in the backend-disruption.json file but this is
-				// not possible without some work as we're basing everything off intervals today. There is
-				// no place to store request UUIDs without stuffing them into the  interval message, which would break
-				// the code that determines when disruption started/stopped based on the similarity of the message.
-				// For now we will just log clearly the requests that failed and use this to correlate with the
-				// audit log manually.
-				logrus.WithFields(logrus.Fields{
-					"this-instance": b.backendSampler.locator,
-					"backend":       b.backendSampler.GetDisruptionBackendName(),
-					"type":          b.backendSampler.connectionType,
-					"auditID":       uid,
-				}).Errorf("disruption sample failed: %v", sampleErr)
-			}
-			close(currDisruptionSample.finished)
-		}()
+		go b.takeSample(ctx, currDisruptionSample)
 
 		select {
 		case <-ticker.C:
@@ -554,22 +539,36 @@ func (b *disruptionSampler) produceSamples(ctx context.Context, interval time.Du
 	}
 }
 
+// takeSample checks the connection to the backend and finishes the sample with the result.
+func (b *disruptionSampler) takeSample(ctx context.Context, sample *disruptionSample) {
+	started := time.Now()
+	uid, sampleErr := b.backendSampler.CheckConnection(ctx)
+	sample.setLatency(time.Since(started))
+	sample.setSampleError(sampleErr)
+	sample.setRequestAuditID(uid)
+	if sampleErr != nil {
+		// We'd like to include these UUIDs in the backend-disruption.json file but this is
+		// not possible without some work as we're basing everything off intervals today. There is
+		// no place to store request UUIDs without stuffing them into the  interval message, which would break
+		// the code that determines when disruption started/stopped based on the similarity of the message.
+		// For now we will just log clearly the requests that failed and use this to correlate with the
+		// audit log manually.
+		logrus.WithFields(logrus.Fields{
+			"this-instance": b.backendSampler.locator,
+			"backend":       b.backendSampler.GetDisruptionBackendName(),
+			"type":          b.backendSampler.connectionType,
+			"auditID":       uid,
+		}).Errorf("disruption sample failed: %v", sampleErr)
+	}
+	close(sample.finished)
+}
+
 // consumeSamples only exits when the ctx is closed
 func (b *disruptionSampler) consumeSamples(ctx context.Context, consumerDoneCh chan struct{}, interval time.Duration, monitorRecorder monitorapi.RecorderWriter, eventRecorder events.EventRecorder) {
 	defer close(consumerDoneCh)
 
-	firstSample := true
-	previousError := fmt.Errorf("never checked before")
-	previousIntervalID := -1
-	var previousSampleTime *time.Time
-
-	// when we exit this function, we want to set a final duration of failure.  We don't actually know whether it ended
-	// or how long it took to ask
-	defer func() {
-		if previousIntervalID != -1 && previousSampleTime != nil {
-			monitorRecorder.EndInterval(previousIntervalID, previousSampleTime.Add(interval))
-		}
-	}()
+	consumer := newSampleConsumer(b.backendSampler, interval, monitorRecorder, eventRecorder)
+	defer consumer.finish()
 
 	for {
 		select {
@@ -595,80 +594,133 @@ func (b *disruptionSampler) consumeSamples(ctx context.Context, consumerDoneCh c
 			return
 		}
 
-		previouslyAvailable := previousError == nil
-		currentError := currSample.getSampleError()
-		currentlyAvailable := currentError == nil
-		currSampleTime := currSample.startTime
+		consumer.consume(currSample)
+	}
+}
 
-		switch {
-		case currentlyAvailable && previouslyAvailable:
-			// we are continuing to function.  no condition change.
+// sampleConsumer records the success/failure edges of the samples of a backend into the monitorRecorder.  It is given
+// the finished samples one by one, in the order they were started.
+type sampleConsumer struct {
+	backendSampler  *BackendSampler
+	interval        time.Duration
+	monitorRecorder monitorapi.RecorderWriter
+	eventRecorder   events.EventRecorder
+	latency         *latencyTracker
 
-		case !currentlyAvailable && !previouslyAvailable:
-			// we are continuing to fail, check to see if the error is new
-			if previousError.Error() == currentError.Error() && !firstSample {
-				// if the error is the same and this isn't the first sample we have, skip
-				break
-			}
+	firstSample        bool
+	previousError      error
+	previousIntervalID int
+	previousSampleTime *time.Time
+}
 
-			// if the error is new or the first we have seen.
-			// end the previous interval if we have one, because we need to start a new interval
-			if previousIntervalID != -1 {
-				monitorRecorder.EndInterval(previousIntervalID, currSample.startTime)
-			}
+func newSampleConsumer(backendSampler *BackendSampler, interval time.Duration, monitorRecorder monitorapi.RecorderWriter, eventRecorder events.EventRecorder) *sampleConsumer {
+	return &sampleConsumer{
+		backendSampler:     backendSampler,
+		interval:           interval,
+		monitorRecorder:    monitorRecorder,
+		eventRecorder:      eventRecorder,
+		latency:            newLatencyTracker(backendSampler.GetLocator(), monitorRecorder),
+		firstSample:        true,
+		previousError:      fmt.Errorf("never checked before"),
+		previousIntervalID: -1,
+	}
+}
 
-			// start a new interval with the new error
-			message, eventReason, level := DisruptionBegan(b.backendSampler.GetLocator().OldLocator(), b.backendSampler.GetConnectionType(), currentError, currSample.getRequestAuditID())
-			framework.Logf(message.BuildString())
-			eventRecorder.Eventf(
-				&v1.ObjectReference{Kind: "OpenShiftTest", Namespace: "kube-system", Name: b.backendSampler.GetDisruptionBackendName()}, nil,
-				v1.EventTypeWarning, string(eventReason), "detected", message.BuildString())
-			currInterval := monitorapi.NewInterval(monitorapi.SourceDisruption, level).
-				Locator(b.backendSampler.GetLocator()).
-				Display().
-				Message(message).Build(currSample.startTime, time.Time{})
-			previousIntervalID = monitorRecorder.StartInterval(currInterval)
-
-		case currentlyAvailable && !previouslyAvailable:
-			// end the previous interval if we have one because our state changed
-			if previousIntervalID != -1 {
-				monitorRecorder.EndInterval(previousIntervalID, currSample.startTime)
-			}
+// finish sets a final duration of failure.  We don't actually know whether it ended or how long it took to ask.
+func (c *sampleConsumer) finish() {
+	if c.previousIntervalID != -1 && c.previousSampleTime != nil {
+		c.monitorRecorder.EndInterval(c.previousIntervalID, c.previousSampleTime.Add(c.interval))
+	}
+	c.latency.finish()
+}
 
-			message := DisruptionEndedMessage(b.backendSampler.GetLocator().OldLocator(), b.backendSampler.GetConnectionType())
-			eventRecorder.Eventf(
-				&v1.ObjectReference{Kind: "OpenShiftTest", Namespace: "kube-system", Name: b.backendSampler.GetDisruptionBackendName()}, nil,
-				v1.EventTypeNormal, string(monitorapi.DisruptionEndedEventReason), "detected", message.BuildString())
-			currInterval := monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Info).
-				Locator(b.backendSampler.GetLocator()).
-				Message(message).Build(currSample.startTime, time.Time{})
-			previousIntervalID = monitorRecorder.StartInterval(currInterval)
-
-		case !currentlyAvailable && previouslyAvailable:
-			// end the previous interval if we have one because our state changed
-			if previousIntervalID != -1 {
-				monitorRecorder.EndInterval(previousIntervalID, currSample.startTime)
-			}
+func (c *sampleConsumer) consume(currSample *disruptionSample) {
+	previouslyAvailable := c.previousError == nil
+	currentError := currSample.getSampleError()
+	currentlyAvailable := currentError == nil
+	currSampleTime := currSample.startTime
+	c.latency.observe(currSampleTime, currSample.getLatency(), currentlyAvailable)
 
-			message, eventReason, level := DisruptionBegan(b.backendSampler.GetLocator().OldLocator(), b.backendSampler.GetConnectionType(), currentError, currSample.getRequestAuditID())
-			framework.Logf(message.BuildString())
-			eventRecorder.Eventf(
-				&v1.ObjectReference{Kind: "OpenShiftTest", Namespace: "kube-system", Name: b.backendSampler.GetDisruptionBackendName()}, nil,
-				v1.EventTypeWarning, string(eventReason), "detected", message.BuildString())
-			currInterval := monitorapi.NewInterval(monitorapi.SourceDisruption, level).
-				Locator(b.backendSampler.GetLocator()).
-				Message(message).Display().Build(currSample.startTime, time.Time{})
-			previousIntervalID = monitorRecorder.StartInterval(currInterval)
+	switch {
+	case currentlyAvailable && previouslyAvailable:
+		// we are continuing to function.  no condition change.
+
+	case !currentlyAvailable && !previouslyAvailable:
+		// we are continuing to fail, check to see if the error is new
+		if c.previousError.Error() == currentError.Error() && !c.firstSample {
+			// if the error is the same and this isn't the first sample we have, skip
+			break
+		}
 
-		default:
-			panic("math broke resulting in this weird error you need to find")
+		// if the error is new or the first we have seen.
+		// end the previous interval if we have one, because we need to start a new interval
+		if c.previousIntervalID != -1 {
+			c.monitorRecorder.EndInterval(c.previousIntervalID, currSample.startTime)
+		}
+
+		// start a new interval with the new error
+		message, eventReason, level := disruptionBegan(c.backendSampler.GetLocator().OldLocator(), c.backendSampler.GetConnectionType(), currentError, currSample.getRequestAuditID())
+		framework.Logf(message.BuildString())
+		c.eventRecorder.Eventf(
+			&v1.ObjectReference{Kind: "OpenShiftTest", Namespace: "kube-system", Name: c.backendSampler.GetDisruptionBackendName()}, nil,
+			v1.EventTypeWarning, string(eventReason), "detected", message.BuildString())
+		currInterval := monitorapi.NewInterval(monitorapi.SourceDisruption, level).
+			Locator(c.backendSampler.GetLocator()).
+			Display().
+			Message(message).Build(currSample.startTime, time.Time{})
+		c.previousIntervalID = c.monitorRecorder.StartInterval(currInterval)
+
+	case currentlyAvailable && !previouslyAvailable:
+		// end the previous interval if we have one because our state changed
+		if c.previousIntervalID != -1 {
+			c.monitorRecorder.EndInterval(c.previousIntervalID, currSample.startTime)
+		}
+
+		message := DisruptionEndedMessage(c.backendSampler.GetLocator().OldLocator(), c.backendSampler.GetConnectionType())
+		c.eventRecorder.Eventf(
+			&v1.ObjectReference{Kind: "OpenShiftTest", Namespace: "kube-system", Name: c.backendSampler.GetDisruptionBackendName()}, nil,
+			v1.EventTypeNormal, string(monitorapi.DisruptionEndedEventReason), "detected", message.BuildString())
+		currInterval := monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Info).
+			Locator(c.backendSampler.GetLocator()).
+			Message(message).Build(currSample.startTime, time.Time{})
+		c.previousIntervalID = c.monitorRecorder.StartInterval(currInterval)
+
+	case !currentlyAvailable && previouslyAvailable:
+		// end the previous interval if we have one because our state changed
+		if c.previousIntervalID != -1 {
+			c.monitorRecorder.EndInterval(c.previousIntervalID, currSample.startTime)
 		}
 
-		firstSample = false
-		previousError = currentError
-		t := currSampleTime // make sure we get a copy
-		previousSampleTime = &t
+		message, eventReason, level := disruptionBegan(c.backendSampler.GetLocator().OldLocator(), c.backendSampler.GetConnectionType(), currentError, currSample.getRequestAuditID())
+		framework.Logf(message.BuildString())
+		c.eventRecorder.Eventf(
+			&v1.ObjectReference{Kind: "OpenShiftTest", Namespace: "kube-system", Name: c.backendSampler.GetDisruptionBackendName()}, nil,
+			v1.EventTypeWarning, string(eventReason), "detected", message.BuildString())
+		currInterval := monitorapi.NewInterval(monitorapi.SourceDisruption, level).
+			Locator(c.backendSampler.GetLocator()).
+			Message(message).Display().Build(currSample.startTime, time.Time{})
+		c.previousIntervalID = c.monitorRecorder.StartInterval(currInterval)
+
+	default:
+		panic("math broke resulting in this weird error you need to find")
+	}
+
+	c.firstSample = false
+	c.previousError = currentError
+	t := currSampleTime // make sure we get a copy
+	c.previousSampleTime = &t
+}
+
+// disruptionBegan is DisruptionBegan with the category of the sample error in an annotation, the message keeps the
+// error as the client returned it.
+func disruptionBegan(locator string, connectionType monitorapi.BackendConnectionType, err error, auditID string) (*monitorapi.MessageBuilder, monitorapi.IntervalReason, monitorapi.IntervalLevel) {
+	category := backendsampler.CategoryOf(err)
+	var known *backendsampler.KnownError
+	if errors.As(err, &known) {
+		err = known.Unwrap()
 	}
+	message, eventReason, level := DisruptionBegan(locator, connectionType, err, auditID)
+	return message.WithAnnotation(monitorapi.AnnotationErrorCategory, string(category)), eventReason, level
 }
 
 func (b *disruptionSampler) popOldestSample(ctx context.Context) *disruptionSample {
@@ -703,6 +755,8 @@ type disruptionSample struct {
 	startTime      time.Time
 	sampleErr      error
 	requestAuditID string
+	// latency is how long the sample took, from the start of the request to the end of the response
+	latency time.Duration
 
 	finished chan struct{}
 }
@@ -726,6 +780,18 @@ func (s *disruptionSample) getSampleError() error {
 	return s.sampleErr
 }
 
+func (s *disruptionSample) setLatency(latency time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.latency = latency
+}
+
+func (s *disruptionSample) getLatency() time.Duration {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.latency
+}
+
 func (s *disruptionSample) setRequestAuditID(auditID string) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
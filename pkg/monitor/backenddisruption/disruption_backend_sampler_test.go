@@ -9,12 +9,16 @@ import (
 	"testing"
 	"time"
 
+	"github.com/openshift/origin/pkg/disruption/backend/fake"
+	backendsampler "github.com/openshift/origin/pkg/disruption/backend/sampler"
 	monitor2 "github.com/openshift/origin/pkg/monitor"
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"k8s.io/client-go/tools/events"
+	testingclock "k8s.io/utils/clock/testing"
 )
 
 func TestBackendSampler_checkConnection(t *testing.T) {
@@ -54,9 +58,10 @@ func TestBackendSampler_checkConnection(t *testing.T) {
 		cancelImmediately bool
 	}
 	tests := []struct {
-		name    string
-		fields  fields
-		wantErr bool
+		name         string
+		fields       fields
+		wantErr      bool
+		wantCategory backendsampler.ErrorCategory
 	}{
 		{
 			name: "simple-200",
@@ -76,7 +81,8 @@ func TestBackendSampler_checkConnection(t *testing.T) {
 				path:                  "/200",
 				expect:                "other",
 			},
-			wantErr: true,
+			wantErr:      true,
+			wantCategory: backendsampler.Unknown,
 		},
 		{
 			// 302 response missing Location header
@@ -105,7 +111,8 @@ func TestBackendSampler_checkConnection(t *testing.T) {
 				connectionType:        monitorapi.NewConnectionType,
 				path:                  "/503",
 			},
-			wantErr: true,
+			wantErr:      true,
+			wantCategory: backendsampler.HTTP5xx,
 		},
 		{
 			name: "timeout",
@@ -114,7 +121,8 @@ func TestBackendSampler_checkConnection(t *testing.T) {
 				connectionType:        monitorapi.NewConnectionType,
 				path:                  "/timeout",
 			},
-			wantErr: true,
+			wantErr:      true,
+			wantCategory: backendsampler.IOTimeout,
 		},
 		{
 			name: "cancel-immediately",
@@ -146,9 +154,13 @@ func TestBackendSampler_checkConnection(t *testing.T) {
 				cancel()
 			}
 
-			if _, err := backend.CheckConnection(ctx); (err != nil) != tt.wantErr {
+			_, err := backend.CheckConnection(ctx)
+			if (err != nil) != tt.wantErr {
 				t.Errorf("CheckConnection() error = %v, wantErr %v", err, tt.wantErr)
 			}
+			if err != nil && backendsampler.CategoryOf(err) != tt.wantCategory {
+				t.Errorf("CheckConnection() error category = %v, wantCategory %v", backendsampler.CategoryOf(err), tt.wantCategory)
+			}
 		})
 	}
 }
@@ -294,6 +306,33 @@ func Test_disruptionSampler_consumeSamples(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			name:          "error-category-annotation",
+			estimatedTime: 1 * time.Second,
+			produceSamples: func(ctx context.Context, backendSampler *disruptionSampler) {
+				now := time.Now()
+				firstSample := backendSampler.newSample(ctx)
+				firstSample.startTime = now
+				firstSample.setSampleError(backendsampler.NewKnownError(backendsampler.HTTP5xx, fmt.Errorf("error running request: 503 Service Unavailable")))
+
+				secondSample := backendSampler.newSample(ctx)
+				secondSample.startTime = firstSample.startTime.Add(1 * time.Second)
+				secondSample.setSampleError(nil)
+
+				close(firstSample.finished)
+				close(secondSample.finished)
+			},
+			validateSamples: func(t *testing.T, eventIntervals []monitorapi.Interval) error {
+				if !assert.Equal(t, 2, len(eventIntervals)) {
+					return nil
+				}
+
+				assert.Equal(t, string(backendsampler.HTTP5xx), eventIntervals[0].Message.Annotations[monitorapi.AnnotationErrorCategory])
+				assert.Contains(t, eventIntervals[0].Message.HumanMessage, "error running request: 503 Service Unavailable")
+				assert.NotContains(t, eventIntervals[0].Message.HumanMessage, "category:", "the category is an annotation, not part of the message")
+				return nil
+			},
+		},
 		{
 			// Disruption with a message of "dial tcp: lookup [hostname]: i/o timeout" should not be considered real disruption
 			name:          "dial-tcp-lookup-warn-not-error",
@@ -353,7 +392,57 @@ func Test_disruptionSampler_consumeSamples(t *testing.T) {
 			cancel()
 			<-consumptionDone
 
-			tt.validateSamples(t, monitor.Intervals(time.Time{}, time.Time{}))
+			// the latency of the samples is not what is tested here
+			tt.validateSamples(t, monitor.Intervals(time.Time{}, time.Time{}).Filter(monitorapi.IsDisruptionEvent))
 		})
 	}
 }
+
+func TestBackendSampler_consumeFakeBackendSamples(t *testing.T) {
+	// the samples are taken every second on the fake clock of the
+	// backend, the faults begin and end half way between two samples
+	start := time.Date(2023, 2, 14, 20, 30, 0, 0, time.UTC)
+	fakeClock := testingclock.NewFakeClock(start)
+	b := fake.NewBackend(fake.Config{
+		Clock: fakeClock,
+		Schedule: fake.Schedule{
+			{Type: fake.RespondWithStatus, At: 1500 * time.Millisecond, For: time.Second, StatusCode: http.StatusServiceUnavailable},
+			{Type: fake.HalfClose, At: 2500 * time.Millisecond, For: time.Second},
+		},
+	})
+	defer b.Close()
+
+	sampler := NewSimpleBackendFromOpenshiftTests(b.URL(), "fake-backend", "/healthz", monitorapi.NewConnectionType).
+		WithTLSConfig(b.TLSClientConfig())
+	recorder := &fake.Recorder{}
+	consumer := newSampleConsumer(sampler, time.Second, recorder, events.NewFakeRecorder(100))
+	// the samples are taken and consumed one by one, the way produceSamples and consumeSamples take them every second
+	disruptionSampler := newDisruptionSampler(sampler)
+	for i := 0; i < 6; i++ {
+		sample := newDisruptionSample(fakeClock.Now())
+		disruptionSampler.takeSample(context.Background(), sample)
+		consumer.consume(sample)
+		fakeClock.Step(time.Second)
+	}
+	consumer.finish()
+
+	intervals := recorder.Intervals().Filter(monitorapi.IsDisruptionEvent)
+	require.Len(t, intervals, 4, "available, two outages with different errors, and available again")
+	assert.Equal(t, monitorapi.DisruptionEndedEventReason, intervals[0].Message.Reason)
+	assert.Equal(t, monitorapi.Error, intervals[1].Level)
+	assert.Contains(t, intervals[1].Message.HumanMessage, "503 Service Unavailable")
+	assert.Equal(t, monitorapi.Error, intervals[2].Level)
+	assert.Contains(t, intervals[2].Message.HumanMessage, "EOF")
+	assert.Equal(t, monitorapi.DisruptionEndedEventReason, intervals[3].Message.Reason)
+	at := func(seconds int) time.Time { return start.Add(time.Duration(seconds) * time.Second) }
+	for i, want := range [][2]time.Time{{at(0), at(2)}, {at(2), at(3)}, {at(3), at(4)}, {at(4), at(6)}} {
+		assert.Equal(t, want[0], intervals[i].From, "start of interval %d", i)
+		assert.Equal(t, want[1], intervals[i].To, "end of interval %d", i)
+	}
+
+	summaries := recorder.Intervals().Filter(func(interval monitorapi.Interval) bool {
+		return interval.Source == monitorapi.SourceBackendLatency && interval.Message.Reason == monitorapi.LatencySummaryEventReason
+	})
+	require.Len(t, summaries, 1, "the latency of the successful samples is summarized")
+	assert.Equal(t, "4", summaries[0].Message.Annotations[monitorapi.AnnotationCount])
+}
@@ -0,0 +1,248 @@
+package backenddisruption
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+const (
+	// DefaultLatencyWindow is the number of samples the latency percentile is computed over, a minute worth of samples
+	// at the sample interval of 1s.
+	DefaultLatencyWindow = 60
+	// DefaultLatencyPercentile is the percentile of the window compared to the threshold.
+	DefaultLatencyPercentile = 0.95
+	// DefaultLatencyThreshold is the latency above which a backend is deemed degraded.
+	DefaultLatencyThreshold = 2 * time.Second
+)
+
+// LatencyBuckets are the upper bounds of the buckets of a LatencyHistogram.
+var LatencyBuckets = []time.Duration{
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	1 * time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// LatencyHistogram counts latencies by the LatencyBuckets they fall in, its last count is for the latencies above
+// every bucket.
+type LatencyHistogram []int64
+
+func NewLatencyHistogram() LatencyHistogram {
+	return make(LatencyHistogram, len(LatencyBuckets)+1)
+}
+
+func (h LatencyHistogram) Observe(latency time.Duration) {
+	h[sort.Search(len(LatencyBuckets), func(i int) bool { return latency <= LatencyBuckets[i] })]++
+}
+
+// LatencyBucketName names bucket i of a LatencyHistogram by its upper bound, +Inf for the last bucket.
+func LatencyBucketName(i int) string {
+	if i >= len(LatencyBuckets) {
+		return "+Inf"
+	}
+	return LatencyBuckets[i].String()
+}
+
+// String encodes the histogram as the value of the histogram annotation, for instance "50ms=10 100ms=2 ... +Inf=0".
+func (h LatencyHistogram) String() string {
+	buckets := []string{}
+	for i, count := range h {
+		buckets = append(buckets, fmt.Sprintf("%s=%d", LatencyBucketName(i), count))
+	}
+	return strings.Join(buckets, " ")
+}
+
+// ParseLatencyHistogram decodes the value of the histogram annotation.
+func ParseLatencyHistogram(value string) (LatencyHistogram, error) {
+	h := NewLatencyHistogram()
+	buckets := strings.Fields(value)
+	if len(buckets) != len(h) {
+		return nil, fmt.Errorf("expected %d latency buckets, got %d: %q", len(h), len(buckets), value)
+	}
+	for i, bucket := range buckets {
+		name, count, ok := strings.Cut(bucket, "=")
+		if !ok || name != LatencyBucketName(i) {
+			return nil, fmt.Errorf("expected latency bucket %s, got %q", LatencyBucketName(i), bucket)
+		}
+		var err error
+		if h[i], err = strconv.ParseInt(count, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid count for latency bucket %s: %w", name, err)
+		}
+	}
+	return h, nil
+}
+
+// LatencyPercentile returns the latency that the given fraction of the latencies are at or below, 0.99 for the P99,
+// by the nearest rank.
+func LatencyPercentile(latencies []time.Duration, percentile float64) time.Duration {
+	if len(latencies) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration{}, latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := int(math.Ceil(percentile * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
+
+// LatencyWindow is a sliding window over the latencies of the most recent successful samples of a backend, it also
+// keeps every latency it has seen for the summary.
+type LatencyWindow struct {
+	size       int
+	percentile float64
+
+	// window holds the most recent latencies, next is where the next latency goes once it is full
+	window []time.Duration
+	next   int
+	// latencies holds every latency
+	latencies []time.Duration
+}
+
+func NewLatencyWindow(size int, percentile float64) *LatencyWindow {
+	return &LatencyWindow{size: size, percentile: percentile}
+}
+
+// Observe adds the latency of a successful sample.  Once the window is full, it returns the percentile of the window
+// and true, until then there are too few samples for the percentile to mean anything.
+func (w *LatencyWindow) Observe(latency time.Duration) (time.Duration, bool) {
+	w.latencies = append(w.latencies, latency)
+	if len(w.window) < w.size {
+		w.window = append(w.window, latency)
+	} else {
+		w.window[w.next] = latency
+		w.next = (w.next + 1) % w.size
+	}
+	if len(w.window) < w.size {
+		return 0, false
+	}
+	return LatencyPercentile(w.window, w.percentile), true
+}
+
+// Len is the number of latencies in the window.
+func (w *LatencyWindow) Len() int {
+	return len(w.window)
+}
+
+// Latencies returns every latency observed.
+func (w *LatencyWindow) Latencies() []time.Duration {
+	return w.latencies
+}
+
+// PercentileName returns the short name of a percentile, p95 for 0.95.
+func PercentileName(percentile float64) string {
+	return fmt.Sprintf("p%g", math.Round(percentile*1000)/10)
+}
+
+// LatencyDegradedMessage describes a backend that is available, but slower than the threshold.
+func LatencyDegradedMessage(locator string, percentile float64, latency, threshold time.Duration, samples int) *monitorapi.MessageBuilder {
+	return monitorapi.NewMessage().
+		Reason(monitorapi.LatencyDegradedEventReason).
+		WithAnnotation(monitorapi.AnnotationPercentile, PercentileName(percentile)).
+		WithAnnotation(monitorapi.AnnotationLatency, latency.Round(time.Millisecond).String()).
+		HumanMessagef("%s %s latency of the last %d samples is %s, above the threshold of %s",
+			locator, PercentileName(percentile), samples, latency.Round(time.Millisecond), threshold)
+}
+
+// LatencySummaryMessage summarizes the latencies of the successful samples of a backend.
+func LatencySummaryMessage(locator string, latencies []time.Duration) *monitorapi.MessageBuilder {
+	histogram := NewLatencyHistogram()
+	for _, latency := range latencies {
+		histogram.Observe(latency)
+	}
+	p50 := LatencyPercentile(latencies, 0.50).Round(time.Millisecond)
+	p95 := LatencyPercentile(latencies, 0.95).Round(time.Millisecond)
+	p99 := LatencyPercentile(latencies, 0.99).Round(time.Millisecond)
+	return monitorapi.NewMessage().
+		Reason(monitorapi.LatencySummaryEventReason).
+		WithAnnotation(monitorapi.AnnotationCount, strconv.Itoa(len(latencies))).
+		WithAnnotation(monitorapi.AnnotationLatencyP50, p50.String()).
+		WithAnnotation(monitorapi.AnnotationLatencyP95, p95.String()).
+		WithAnnotation(monitorapi.AnnotationLatencyP99, p99.String()).
+		WithAnnotation(monitorapi.AnnotationHistogram, histogram.String()).
+		HumanMessagef("%s latency over %d samples: p50=%s p95=%s p99=%s", locator, len(latencies), p50, p95, p99)
+}
+
+// latencyTracker records the LatencyDegraded and LatencySummary intervals of a BackendSampler, the same way the
+// latency tracker of pkg/disruption/backend/latency does for the samplers of pkg/disruption.
+type latencyTracker struct {
+	locator         monitorapi.Locator
+	threshold       time.Duration
+	percentile      float64
+	window          *LatencyWindow
+	monitorRecorder monitorapi.RecorderWriter
+
+	openIntervalID int
+	first, last    time.Time
+}
+
+func newLatencyTracker(locator monitorapi.Locator, monitorRecorder monitorapi.RecorderWriter) *latencyTracker {
+	return &latencyTracker{
+		locator:         locator,
+		threshold:       DefaultLatencyThreshold,
+		percentile:      DefaultLatencyPercentile,
+		window:          NewLatencyWindow(DefaultLatencyWindow, DefaultLatencyPercentile),
+		monitorRecorder: monitorRecorder,
+		openIntervalID:  -1,
+	}
+}
+
+// observe is called with every sample, in the order they were started.  A failed sample is disruption, its latency
+// does not count.
+func (t *latencyTracker) observe(startedAt time.Time, latency time.Duration, succeeded bool) {
+	if t.first.IsZero() {
+		t.first = startedAt
+	}
+	t.last = startedAt.Add(latency)
+	if !succeeded {
+		return
+	}
+
+	current, ok := t.window.Observe(latency)
+	if !ok {
+		return
+	}
+	switch {
+	case current > t.threshold && t.openIntervalID == -1:
+		message := LatencyDegradedMessage(t.locator.OldLocator(), t.percentile, current, t.threshold, t.window.Len())
+		interval := monitorapi.NewInterval(monitorapi.SourceBackendLatency, monitorapi.Warning).
+			Locator(t.locator).
+			Display().
+			Message(message).Build(startedAt, time.Time{})
+		t.openIntervalID = t.monitorRecorder.StartInterval(interval)
+	case current <= t.threshold && t.openIntervalID != -1:
+		t.monitorRecorder.EndInterval(t.openIntervalID, startedAt)
+		t.openIntervalID = -1
+	}
+}
+
+// finish ends the LatencyDegraded interval that is still open, and summarizes the latency of every successful sample.
+func (t *latencyTracker) finish() {
+	if t.openIntervalID != -1 {
+		t.monitorRecorder.EndInterval(t.openIntervalID, t.last)
+		t.openIntervalID = -1
+	}
+	latencies := t.window.Latencies()
+	if len(latencies) == 0 {
+		return
+	}
+	t.monitorRecorder.AddIntervals(
+		monitorapi.NewInterval(monitorapi.SourceBackendLatency, monitorapi.Info).
+			Locator(t.locator).
+			Message(LatencySummaryMessage(t.locator.OldLocator(), latencies)).Build(t.first, t.last),
+	)
+}
@@ -0,0 +1,103 @@
+package backenddisruption
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/disruption/backend/fake"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatencyHistogram(t *testing.T) {
+	histogram := NewLatencyHistogram()
+	for _, latency := range []time.Duration{time.Millisecond, 50 * time.Millisecond, 51 * time.Millisecond, 3 * time.Second, time.Minute} {
+		histogram.Observe(latency)
+	}
+	encoded := histogram.String()
+	assert.Equal(t, "50ms=2 100ms=1 250ms=0 500ms=0 1s=0 2.5s=0 5s=1 10s=0 +Inf=1", encoded)
+
+	decoded, err := ParseLatencyHistogram(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, histogram, decoded)
+
+	_, err = ParseLatencyHistogram("50ms=2 100ms=1")
+	assert.Error(t, err, "every bucket is required")
+	_, err = ParseLatencyHistogram("50ms=2 100ms=1 250ms=0 500ms=0 1s=0 2s=0 5s=1 10s=0 +Inf=1")
+	assert.Error(t, err, "the buckets must match")
+}
+
+func TestLatencyPercentile(t *testing.T) {
+	latencies := []time.Duration{}
+	for i := 100; i > 0; i-- {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	assert.Equal(t, 50*time.Millisecond, LatencyPercentile(latencies, 0.50))
+	assert.Equal(t, 99*time.Millisecond, LatencyPercentile(latencies, 0.99))
+	assert.Equal(t, 100*time.Millisecond, LatencyPercentile(latencies, 1))
+	assert.Equal(t, 100*time.Millisecond, latencies[0], "the latencies are not sorted in place")
+	assert.Equal(t, time.Duration(0), LatencyPercentile(nil, 0.99))
+
+	assert.Equal(t, "p95", PercentileName(0.95))
+	assert.Equal(t, "p99.9", PercentileName(0.999))
+}
+
+func TestLatencyWindow(t *testing.T) {
+	window := NewLatencyWindow(3, 0.5)
+	for _, latency := range []time.Duration{time.Second, 3 * time.Second} {
+		_, ok := window.Observe(latency)
+		assert.False(t, ok, "the window is not full")
+	}
+	current, ok := window.Observe(2 * time.Second)
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Second, current)
+
+	// the oldest latency leaves the window
+	current, _ = window.Observe(10 * time.Millisecond)
+	assert.Equal(t, 2*time.Second, current)
+	current, _ = window.Observe(10 * time.Millisecond)
+	assert.Equal(t, 10*time.Millisecond, current)
+	assert.Equal(t, 3, window.Len())
+	assert.Len(t, window.Latencies(), 5, "every latency is kept for the summary")
+}
+
+func TestLatencyTracker(t *testing.T) {
+	start := time.Date(2023, 2, 14, 20, 30, 0, 0, time.UTC)
+	locator := monitorapi.NewLocator().LocateDisruptionCheck("kube-api", "openshift-tests", monitorapi.NewConnectionType)
+	recorder := &fake.Recorder{}
+	tracker := newLatencyTracker(locator, recorder)
+	tracker.window = NewLatencyWindow(2, 0.5)
+	tracker.percentile = 0.5
+	tracker.threshold = time.Second
+
+	samples := []struct {
+		latency   time.Duration
+		succeeded bool
+	}{
+		{latency: 3 * time.Second, succeeded: true},
+		// degraded from the second sample, a failed sample does not count
+		{latency: 3 * time.Second, succeeded: true},
+		{latency: 10 * time.Second, succeeded: false},
+		// the lower median is back under the threshold
+		{latency: 10 * time.Millisecond, succeeded: true},
+		{latency: 10 * time.Millisecond, succeeded: true},
+	}
+	for i, sample := range samples {
+		tracker.observe(start.Add(time.Duration(i)*time.Second), sample.latency, sample.succeeded)
+	}
+	tracker.finish()
+
+	intervals := recorder.Intervals()
+	require.Len(t, intervals, 2, "a degraded interval and a summary")
+	degraded, summary := intervals[0], intervals[1]
+	assert.Equal(t, monitorapi.LatencyDegradedEventReason, degraded.Message.Reason)
+	assert.Equal(t, monitorapi.Warning, degraded.Level)
+	assert.Equal(t, start.Add(time.Second), degraded.From)
+	assert.Equal(t, start.Add(3*time.Second), degraded.To)
+
+	assert.Equal(t, monitorapi.LatencySummaryEventReason, summary.Message.Reason)
+	assert.Equal(t, "4", summary.Message.Annotations[monitorapi.AnnotationCount])
+	assert.Equal(t, start, summary.From)
+	assert.Equal(t, start.Add(4*time.Second+10*time.Millisecond), summary.To)
+}
@@ -0,0 +1,289 @@
+package backenddisruption
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+)
+
+// DefaultParityTolerance is how much longer, at either end, one sampler may see a disruption than the other before it
+// is reported as a difference.  Two samplers take a sample every second, but not at the same instant, so the same
+// disruption begins and ends up to a second apart.
+const DefaultParityTolerance = time.Second
+
+var _ Sampler = &ParitySampler{}
+
+// ParitySampler samples the same backend with a primary and a candidate Sampler side by side, so the disruption seen by
+// the candidate can be compared with the disruption seen by the primary before the candidate replaces it.
+// Only the primary records into the monitor, the candidate keeps its intervals to itself so that the disruption of the
+// backend is not counted twice.  Everything but the sampling is answered by the primary.
+type ParitySampler struct {
+	Sampler
+
+	candidate         Sampler
+	candidateRecorder *intervalRecorder
+	tolerance         time.Duration
+
+	lock            sync.Mutex
+	candidateCancel context.CancelFunc
+	candidateErr    error
+}
+
+// NewParitySampler returns a Sampler that runs candidate alongside primary, the differences larger than tolerance
+// between the two are reported by Parity.
+func NewParitySampler(primary, candidate Sampler, tolerance time.Duration) *ParitySampler {
+	return &ParitySampler{
+		Sampler:           primary,
+		candidate:         candidate,
+		candidateRecorder: &intervalRecorder{},
+		tolerance:         tolerance,
+	}
+}
+
+func (p *ParitySampler) RunEndpointMonitoring(ctx context.Context, m monitorapi.RecorderWriter, eventRecorder events.EventRecorder) error {
+	if m == nil {
+		return fmt.Errorf("monitor is required")
+	}
+	p.startCandidate(ctx)
+	return p.Sampler.RunEndpointMonitoring(ctx, m, eventRecorder)
+}
+
+func (p *ParitySampler) StartEndpointMonitoring(ctx context.Context, m monitorapi.RecorderWriter, eventRecorder events.EventRecorder) error {
+	if m == nil {
+		return fmt.Errorf("monitor is required")
+	}
+	p.startCandidate(ctx)
+	return p.Sampler.StartEndpointMonitoring(ctx, m, eventRecorder)
+}
+
+// startCandidate runs the candidate in the background, a failure of the candidate is reported by Parity rather than
+// failing the primary.  The events of the candidate are discarded, they would repeat those of the primary.
+func (p *ParitySampler) startCandidate(ctx context.Context) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.candidateCancel != nil {
+		return
+	}
+	// the candidate is stopped by canceling its context, whether or not it has
+	// got far enough to be stopped by its own Stop.
+	ctx, p.candidateCancel = context.WithCancel(ctx)
+
+	go func() {
+		if err := p.candidate.RunEndpointMonitoring(ctx, p.candidateRecorder, nil); err != nil {
+			p.lock.Lock()
+			defer p.lock.Unlock()
+			p.candidateErr = err
+		}
+	}()
+}
+
+// Stop stops both samplers, they both have to drain, so they are stopped in parallel.
+func (p *ParitySampler) Stop() {
+	p.lock.Lock()
+	candidateCancel := p.candidateCancel
+	p.lock.Unlock()
+
+	wg := sync.WaitGroup{}
+	if candidateCancel != nil {
+		candidateCancel()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.candidate.Stop()
+		}()
+	}
+	p.Sampler.Stop()
+	wg.Wait()
+}
+
+// Parity compares the disruption that the primary recorded into finalIntervals with the disruption of the candidate.
+func (p *ParitySampler) Parity(finalIntervals monitorapi.Intervals) DisruptionParity {
+	primary := finalIntervals.Filter(monitorapi.And(monitorapi.IsEventForLocator(p.GetLocator()), isDisruption))
+	candidate := p.candidateRecorder.Intervals().Filter(monitorapi.And(monitorapi.IsEventForLocator(p.candidate.GetLocator()), isDisruption))
+
+	parity := CompareDisruption(primary, candidate, p.tolerance)
+	parity.PrimaryBackendName = p.GetDisruptionBackendName()
+	parity.CandidateBackendName = p.candidate.GetDisruptionBackendName()
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	parity.CandidateErr = p.candidateErr
+	return parity
+}
+
+func isDisruption(interval monitorapi.Interval) bool {
+	return interval.Source == monitorapi.SourceDisruption && interval.Level == monitorapi.Error
+}
+
+// DisruptionSpan is a window of time in which a backend was disrupted.
+type DisruptionSpan struct {
+	From, To time.Time
+}
+
+func (s DisruptionSpan) Duration() time.Duration {
+	return s.To.Sub(s.From)
+}
+
+func (s DisruptionSpan) String() string {
+	return fmt.Sprintf("%s - %s (%s)", s.From.UTC().Format("Jan 02 15:04:05.000"), s.To.UTC().Format("Jan 02 15:04:05.000"), s.Duration())
+}
+
+// DisruptionParity is where the disruption seen by two samplers of the same backend differs.
+type DisruptionParity struct {
+	PrimaryBackendName   string
+	CandidateBackendName string
+
+	// PrimaryDisruption and CandidateDisruption are the total disruption seen by each sampler.
+	PrimaryDisruption   time.Duration
+	CandidateDisruption time.Duration
+
+	// PrimaryOnly is the disruption seen by the primary alone, and CandidateOnly by the candidate alone.
+	PrimaryOnly   []DisruptionSpan
+	CandidateOnly []DisruptionSpan
+
+	// CandidateErr is why the candidate failed to sample, if it did.
+	CandidateErr error
+}
+
+// Matches is true if both samplers ran and saw the same disruption.
+func (p DisruptionParity) Matches() bool {
+	return p.CandidateErr == nil && len(p.PrimaryOnly) == 0 && len(p.CandidateOnly) == 0
+}
+
+func (p DisruptionParity) String() string {
+	lines := []string{
+		fmt.Sprintf("%s saw %s of disruption, %s saw %s of disruption",
+			p.PrimaryBackendName, p.PrimaryDisruption, p.CandidateBackendName, p.CandidateDisruption),
+	}
+	if p.CandidateErr != nil {
+		lines = append(lines, fmt.Sprintf("%s failed: %v", p.CandidateBackendName, p.CandidateErr))
+	}
+	for _, span := range p.PrimaryOnly {
+		lines = append(lines, fmt.Sprintf("only %s was disrupted: %s", p.PrimaryBackendName, span))
+	}
+	for _, span := range p.CandidateOnly {
+		lines = append(lines, fmt.Sprintf("only %s was disrupted: %s", p.CandidateBackendName, span))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// CompareDisruption returns the disruption seen in one set of intervals but not the other.  A difference no longer than
+// tolerance is the two samplers not taking their samples at the same instant, and is not reported.
+func CompareDisruption(primary, candidate monitorapi.Intervals, tolerance time.Duration) DisruptionParity {
+	primarySpans, candidateSpans := mergeSpans(primary), mergeSpans(candidate)
+	return DisruptionParity{
+		PrimaryDisruption:   totalDuration(primarySpans),
+		CandidateDisruption: totalDuration(candidateSpans),
+		PrimaryOnly:         longerThan(subtractSpans(primarySpans, candidateSpans), tolerance),
+		CandidateOnly:       longerThan(subtractSpans(candidateSpans, primarySpans), tolerance),
+	}
+}
+
+// mergeSpans returns the windows of time covered by the intervals, sorted, with overlapping and adjacent intervals
+// merged.  An interval that has not ended, or is empty, covers nothing.
+func mergeSpans(intervals monitorapi.Intervals) []DisruptionSpan {
+	spans := []DisruptionSpan{}
+	for _, interval := range intervals {
+		if interval.To.IsZero() || !interval.To.After(interval.From) {
+			continue
+		}
+		spans = append(spans, DisruptionSpan{From: interval.From, To: interval.To})
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].From.Before(spans[j].From) })
+
+	merged := []DisruptionSpan{}
+	for _, span := range spans {
+		if last := len(merged) - 1; last >= 0 && !span.From.After(merged[last].To) {
+			if span.To.After(merged[last].To) {
+				merged[last].To = span.To
+			}
+			continue
+		}
+		merged = append(merged, span)
+	}
+	return merged
+}
+
+// subtractSpans returns the parts of a that are not covered by b, both must be merged.
+func subtractSpans(a, b []DisruptionSpan) []DisruptionSpan {
+	ret := []DisruptionSpan{}
+	for _, span := range a {
+		from := span.From
+		for _, other := range b {
+			if !other.To.After(from) || !other.From.Before(span.To) {
+				continue
+			}
+			if other.From.After(from) {
+				ret = append(ret, DisruptionSpan{From: from, To: other.From})
+			}
+			if other.To.After(from) {
+				from = other.To
+			}
+		}
+		if span.To.After(from) {
+			ret = append(ret, DisruptionSpan{From: from, To: span.To})
+		}
+	}
+	return ret
+}
+
+func longerThan(spans []DisruptionSpan, d time.Duration) []DisruptionSpan {
+	ret := []DisruptionSpan{}
+	for _, span := range spans {
+		if span.Duration() > d {
+			ret = append(ret, span)
+		}
+	}
+	return ret
+}
+
+func totalDuration(spans []DisruptionSpan) time.Duration {
+	var total time.Duration
+	for _, span := range spans {
+		total += span.Duration()
+	}
+	return total
+}
+
+// intervalRecorder keeps the intervals of the candidate of a ParitySampler.
+type intervalRecorder struct {
+	lock      sync.Mutex
+	intervals monitorapi.Intervals
+}
+
+func (r *intervalRecorder) RecordResource(resourceType string, obj runtime.Object)   {}
+func (r *intervalRecorder) Record(conditions ...monitorapi.Condition)                {}
+func (r *intervalRecorder) RecordAt(t time.Time, conditions ...monitorapi.Condition) {}
+
+func (r *intervalRecorder) AddIntervals(intervals ...monitorapi.Interval) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.intervals = append(r.intervals, intervals...)
+}
+
+func (r *intervalRecorder) StartInterval(interval monitorapi.Interval) int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.intervals = append(r.intervals, interval)
+	return len(r.intervals) - 1
+}
+
+func (r *intervalRecorder) EndInterval(startedInterval int, t time.Time) *monitorapi.Interval {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.intervals[startedInterval].To = t
+	interval := r.intervals[startedInterval]
+	return &interval
+}
+
+func (r *intervalRecorder) Intervals() monitorapi.Intervals {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append(monitorapi.Intervals{}, r.intervals...)
+}
@@ -0,0 +1,154 @@
+package backenddisruption
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/disruption/backend/fake"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/events"
+)
+
+func TestCompareDisruption(t *testing.T) {
+	start := time.Date(2023, 2, 14, 20, 30, 0, 0, time.UTC)
+	at := func(seconds float64) time.Time {
+		return start.Add(time.Duration(seconds * float64(time.Second)))
+	}
+	disruption := func(from, to float64) monitorapi.Interval {
+		return monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Error).Build(at(from), at(to))
+	}
+
+	tests := []struct {
+		name          string
+		primary       monitorapi.Intervals
+		candidate     monitorapi.Intervals
+		primaryOnly   []DisruptionSpan
+		candidateOnly []DisruptionSpan
+	}{
+		{
+			name: "no disruption",
+		},
+		{
+			name:      "samples taken at different instants",
+			primary:   monitorapi.Intervals{disruption(10, 20)},
+			candidate: monitorapi.Intervals{disruption(10.6, 20.6)},
+		},
+		{
+			name:    "one error interval per error",
+			primary: monitorapi.Intervals{disruption(10, 20)},
+			// the candidate saw the error change half way through
+			candidate: monitorapi.Intervals{disruption(15, 20), disruption(10, 15)},
+		},
+		{
+			name:        "disruption not seen by the candidate",
+			primary:     monitorapi.Intervals{disruption(10, 20), disruption(30, 33)},
+			candidate:   monitorapi.Intervals{disruption(10, 20)},
+			primaryOnly: []DisruptionSpan{{From: at(30), To: at(33)}},
+		},
+		{
+			name:          "disruption of different lengths",
+			primary:       monitorapi.Intervals{disruption(10, 12)},
+			candidate:     monitorapi.Intervals{disruption(10, 20)},
+			candidateOnly: []DisruptionSpan{{From: at(12), To: at(20)}},
+		},
+		{
+			name:          "disruption split by the other sampler",
+			primary:       monitorapi.Intervals{disruption(10, 20)},
+			candidate:     monitorapi.Intervals{disruption(5, 12), disruption(14, 16), disruption(18, 25)},
+			primaryOnly:   []DisruptionSpan{{From: at(12), To: at(14)}, {From: at(16), To: at(18)}},
+			candidateOnly: []DisruptionSpan{{From: at(5), To: at(10)}, {From: at(20), To: at(25)}},
+		},
+		{
+			name:      "intervals that have not ended are ignored",
+			primary:   monitorapi.Intervals{disruption(10, 12)},
+			candidate: monitorapi.Intervals{disruption(10, 12), monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Error).Build(at(30), time.Time{})},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parity := CompareDisruption(test.primary, test.candidate, DefaultParityTolerance)
+			if test.primaryOnly == nil {
+				test.primaryOnly = []DisruptionSpan{}
+			}
+			if test.candidateOnly == nil {
+				test.candidateOnly = []DisruptionSpan{}
+			}
+			assert.Equal(t, test.primaryOnly, parity.PrimaryOnly)
+			assert.Equal(t, test.candidateOnly, parity.CandidateOnly)
+			assert.Equal(t, len(test.primaryOnly) == 0 && len(test.candidateOnly) == 0, parity.Matches())
+		})
+	}
+}
+
+func TestParitySampler(t *testing.T) {
+	b := fake.NewBackend(fake.Config{
+		Schedule: fake.Schedule{
+			{Type: fake.RespondWithStatus, At: 1500 * time.Millisecond, For: 1500 * time.Millisecond, StatusCode: http.StatusServiceUnavailable},
+		},
+	})
+	defer b.Close()
+
+	newSampler := func(name string) *BackendSampler {
+		return NewSimpleBackendFromOpenshiftTests(b.URL(), name, "/healthz", monitorapi.NewConnectionType).
+			WithTLSConfig(b.TLSClientConfig())
+	}
+	sampler := NewParitySampler(newSampler("primary"), newSampler("candidate"), DefaultParityTolerance)
+	assert.Equal(t, "primary", sampler.GetDisruptionBackendName())
+
+	recorder := &fake.Recorder{}
+	ctx, cancel := context.WithTimeout(context.Background(), 4500*time.Millisecond)
+	defer cancel()
+	require.NoError(t, sampler.StartEndpointMonitoring(ctx, recorder, nil))
+	<-ctx.Done()
+	sampler.Stop()
+
+	// only the primary records into the monitor
+	for _, interval := range recorder.Intervals() {
+		assert.Equal(t, "primary", monitorapi.BackendDisruptionNameFromLocator(interval.Locator))
+	}
+	parity := sampler.Parity(recorder.Intervals())
+	assert.True(t, parity.Matches(), parity.String())
+	assert.Equal(t, "candidate", parity.CandidateBackendName)
+	assert.Greater(t, parity.PrimaryDisruption, time.Duration(0))
+	assert.Greater(t, parity.CandidateDisruption, time.Duration(0))
+}
+
+// blockingSampler only returns from Stop once RunEndpointMonitoring has returned, and RunEndpointMonitoring only
+// returns once its context is canceled.
+type blockingSampler struct {
+	Sampler
+	finished chan struct{}
+}
+
+func (s *blockingSampler) RunEndpointMonitoring(ctx context.Context, m monitorapi.RecorderWriter, eventRecorder events.EventRecorder) error {
+	defer close(s.finished)
+	<-ctx.Done()
+	return nil
+}
+
+func (s *blockingSampler) Stop() {
+	<-s.finished
+}
+
+func TestParitySamplerStopCancelsCandidate(t *testing.T) {
+	primary := NewSimpleBackendFromOpenshiftTests("https://127.0.0.1:1", "primary", "/healthz", monitorapi.NewConnectionType)
+	sampler := NewParitySampler(primary, &blockingSampler{finished: make(chan struct{})}, DefaultParityTolerance)
+
+	// the context is never canceled, only Stop ends the candidate
+	sampler.startCandidate(context.Background())
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		sampler.Stop()
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Stop did not return")
+	}
+}
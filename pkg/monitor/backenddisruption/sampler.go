@@ -0,0 +1,30 @@
+package backenddisruption
+
+import (
+	"context"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"k8s.io/client-go/tools/events"
+)
+
+var _ Sampler = &BackendSampler{}
+
+// Sampler monitors the availability of a backend.  Every availability MonitorTest samples its backends through it, so a
+// BackendSampler and a sampler built by pkg/disruption/ci can be used interchangeably, or side by side with a
+// ParitySampler.
+type Sampler interface {
+	// GetDisruptionBackendName is the name the historical disruption of the backend is looked up by.
+	GetDisruptionBackendName() string
+	// GetLocator is the locator of the disruption intervals the sampler records.
+	GetLocator() monitorapi.Locator
+	GetConnectionType() monitorapi.BackendConnectionType
+	GetURL() (string, error)
+
+	// RunEndpointMonitoring samples the backend until ctx is cancelled or Stop is called, and records the disruption
+	// intervals into m.  It blocks until the sampler is stopped.
+	RunEndpointMonitoring(ctx context.Context, m monitorapi.RecorderWriter, eventRecorder events.EventRecorder) error
+	// StartEndpointMonitoring is RunEndpointMonitoring in the background.
+	StartEndpointMonitoring(ctx context.Context, m monitorapi.RecorderWriter, eventRecorder events.EventRecorder) error
+	// Stop stops the sampler and waits until every interval is recorded.
+	Stop()
+}
@@ -0,0 +1,386 @@
+// Package intervalquery compiles a small expression language into a monitorapi.EventIntervalMatchesFunc.
+//
+// A query is a set of comparisons joined by and, or, and not, grouped with parentheses.  and binds tighter than or.
+//
+//	source=Disruption and duration>5s and keys.backend-disruption-name~"ingress.*"
+//	not (level=Info or reason=Started)
+//	from>=2023-02-14T20:30:00Z and to<2023-02-14T21:00:00Z
+//
+// The fields are
+//
+//	source, reason, cause, message, locator.type   compared as strings
+//	keys.<key>, annotations.<key>                 compared as strings, a missing key is ""
+//	level                                         Info < Warning < Error
+//	duration                                      a Go duration, intervals that have not ended have no duration
+//	from, to                                      RFC3339 times
+//	display                                       true or false
+//
+// and the operators are = and != for every field, ~ and !~ for a regular expression match of a string field, and
+// <, <=, >, >= for level, duration, from, and to.  Values containing spaces or operator characters must be quoted.
+package intervalquery
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// Parse compiles query.  An empty query matches every interval.
+func Parse(query string) (monitorapi.EventIntervalMatchesFunc, error) {
+	tokens, err := lex(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return func(monitorapi.Interval) bool { return true }, nil
+	}
+	ret, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", next.text, next.pos)
+	}
+	return ret, nil
+}
+
+// MustParse is like Parse but panics if the query is invalid.
+func MustParse(query string) monitorapi.EventIntervalMatchesFunc {
+	ret, err := Parse(query)
+	if err != nil {
+		panic(err)
+	}
+	return ret
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOperator
+	tokenOpenParen
+	tokenCloseParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// operators are ordered so that the longest operator is matched first.
+var operators = []string{"!=", "!~", "<=", ">=", "=", "~", "<", ">"}
+
+func lex(query string) ([]token, error) {
+	tokens := []token{}
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenOpenParen, text: "(", pos: i})
+			i++
+
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenCloseParen, text: ")", pos: i})
+			i++
+
+		case c == '"':
+			end := i + 1
+			for ; end < len(query); end++ {
+				if query[end] == '\\' {
+					end++
+					continue
+				}
+				if query[end] == '"' {
+					break
+				}
+			}
+			if end >= len(query) {
+				return nil, fmt.Errorf("unterminated string at position %d", i)
+			}
+			value, err := strconv.Unquote(query[i : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid string at position %d: %w", i, err)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: value, pos: i})
+			i = end + 1
+
+		case strings.ContainsRune("!=~<>", rune(c)):
+			matched := ""
+			for _, operator := range operators {
+				if strings.HasPrefix(query[i:], operator) {
+					matched = operator
+					break
+				}
+			}
+			if len(matched) == 0 {
+				return nil, fmt.Errorf("unexpected %q at position %d", c, i)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: matched, pos: i})
+			i += len(matched)
+
+		default:
+			end := i
+			for ; end < len(query); end++ {
+				if strings.ContainsRune(" \t\n\r()\"!=~<>", rune(query[end])) {
+					break
+				}
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: query[i:end], pos: i})
+			i = end
+		}
+	}
+	tokens = append(tokens, token{kind: tokenEOF, text: "end of query", pos: len(query)})
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	next   int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) pop() token {
+	ret := p.tokens[p.next]
+	if ret.kind != tokenEOF {
+		p.next++
+	}
+	return ret
+}
+
+func (p *parser) peekKeyword(keyword string) bool {
+	next := p.peek()
+	return next.kind == tokenWord && strings.EqualFold(next.text, keyword)
+}
+
+func (p *parser) parseOr() (monitorapi.EventIntervalMatchesFunc, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("or") {
+		p.pop()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = or(left, right)
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (monitorapi.EventIntervalMatchesFunc, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("and") {
+		p.pop()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = and(left, right)
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (monitorapi.EventIntervalMatchesFunc, error) {
+	if p.peekKeyword("not") {
+		p.pop()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(interval monitorapi.Interval) bool { return !inner(interval) }, nil
+	}
+
+	if p.peek().kind == tokenOpenParen {
+		open := p.pop()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if next := p.pop(); next.kind != tokenCloseParen {
+			return nil, fmt.Errorf("expected ) to close ( at position %d, got %q at position %d", open.pos, next.text, next.pos)
+		}
+		return inner, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (monitorapi.EventIntervalMatchesFunc, error) {
+	field := p.pop()
+	if field.kind != tokenWord {
+		return nil, fmt.Errorf("expected a field at position %d, got %q", field.pos, field.text)
+	}
+	operator := p.pop()
+	if operator.kind != tokenOperator {
+		return nil, fmt.Errorf("expected an operator after %q at position %d, got %q", field.text, operator.pos, operator.text)
+	}
+	value := p.pop()
+	if value.kind != tokenWord && value.kind != tokenString {
+		return nil, fmt.Errorf("expected a value after %q at position %d, got %q", operator.text, value.pos, value.text)
+	}
+
+	ret, err := compare(field.text, operator.text, value.text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid comparison at position %d: %w", field.pos, err)
+	}
+	return ret, nil
+}
+
+func and(left, right monitorapi.EventIntervalMatchesFunc) monitorapi.EventIntervalMatchesFunc {
+	return func(interval monitorapi.Interval) bool { return left(interval) && right(interval) }
+}
+
+func or(left, right monitorapi.EventIntervalMatchesFunc) monitorapi.EventIntervalMatchesFunc {
+	return func(interval monitorapi.Interval) bool { return left(interval) || right(interval) }
+}
+
+func compare(field, operator, value string) (monitorapi.EventIntervalMatchesFunc, error) {
+	switch {
+	case field == "source":
+		return compareStrings(field, operator, value, func(interval monitorapi.Interval) string { return string(interval.Source) })
+	case field == "reason":
+		return compareStrings(field, operator, value, func(interval monitorapi.Interval) string { return string(interval.Message.Reason) })
+	case field == "cause":
+		return compareStrings(field, operator, value, func(interval monitorapi.Interval) string { return interval.Message.Cause })
+	case field == "message":
+		return compareStrings(field, operator, value, func(interval monitorapi.Interval) string { return interval.Message.HumanMessage })
+	case field == "locator.type":
+		return compareStrings(field, operator, value, func(interval monitorapi.Interval) string { return string(interval.Locator.Type) })
+	case strings.HasPrefix(field, "keys."):
+		key := monitorapi.LocatorKey(strings.TrimPrefix(field, "keys."))
+		return compareStrings(field, operator, value, func(interval monitorapi.Interval) string { return interval.Locator.Keys[key] })
+	case strings.HasPrefix(field, "annotations."):
+		key := monitorapi.AnnotationKey(strings.TrimPrefix(field, "annotations."))
+		return compareStrings(field, operator, value, func(interval monitorapi.Interval) string { return interval.Message.Annotations[key] })
+
+	case field == "level":
+		level, err := monitorapi.ConditionLevelFromString(value)
+		if err != nil {
+			return nil, err
+		}
+		return compareOrdered(field, operator, func(interval monitorapi.Interval) (int, bool) {
+			return compareInts(int(interval.Level), int(level)), true
+		})
+
+	case field == "duration":
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, err
+		}
+		return compareOrdered(field, operator, func(interval monitorapi.Interval) (int, bool) {
+			if interval.To.IsZero() {
+				return 0, false
+			}
+			return compareInts64(int64(interval.To.Sub(interval.From)), int64(duration)), true
+		})
+
+	case field == "from" || field == "to":
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, err
+		}
+		return compareOrdered(field, operator, func(interval monitorapi.Interval) (int, bool) {
+			curr := interval.From
+			if field == "to" {
+				curr = interval.To
+			}
+			if curr.IsZero() {
+				return 0, false
+			}
+			return curr.Compare(t), true
+		})
+
+	case field == "display":
+		display, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, err
+		}
+		switch operator {
+		case "=":
+			return func(interval monitorapi.Interval) bool { return interval.Display == display }, nil
+		case "!=":
+			return func(interval monitorapi.Interval) bool { return interval.Display != display }, nil
+		}
+		return nil, fmt.Errorf("%q only supports = and !=", field)
+	}
+
+	return nil, fmt.Errorf("unknown field %q", field)
+}
+
+func compareStrings(field, operator, value string, get func(monitorapi.Interval) string) (monitorapi.EventIntervalMatchesFunc, error) {
+	switch operator {
+	case "=":
+		return func(interval monitorapi.Interval) bool { return get(interval) == value }, nil
+	case "!=":
+		return func(interval monitorapi.Interval) bool { return get(interval) != value }, nil
+	case "~", "!~":
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return nil, err
+		}
+		negate := operator == "!~"
+		return func(interval monitorapi.Interval) bool { return re.MatchString(get(interval)) != negate }, nil
+	}
+	return nil, fmt.Errorf("%q does not support %q, only =, !=, ~ and !~", field, operator)
+}
+
+// compareOrdered builds a matcher from cmp, which returns how the interval compares to the value and false when the
+// interval has no value to compare.  An interval without a value only matches !=.
+func compareOrdered(field, operator string, cmp func(monitorapi.Interval) (int, bool)) (monitorapi.EventIntervalMatchesFunc, error) {
+	var accept func(int) bool
+	switch operator {
+	case "=":
+		accept = func(c int) bool { return c == 0 }
+	case "!=":
+		accept = func(c int) bool { return c != 0 }
+	case "<":
+		accept = func(c int) bool { return c < 0 }
+	case "<=":
+		accept = func(c int) bool { return c <= 0 }
+	case ">":
+		accept = func(c int) bool { return c > 0 }
+	case ">=":
+		accept = func(c int) bool { return c >= 0 }
+	default:
+		return nil, fmt.Errorf("%q does not support %q, only =, !=, <, <=, > and >=", field, operator)
+	}
+	return func(interval monitorapi.Interval) bool {
+		c, ok := cmp(interval)
+		if !ok {
+			return operator == "!="
+		}
+		return accept(c)
+	}, nil
+}
+
+func compareInts(a, b int) int {
+	return compareInts64(int64(a), int64(b))
+}
+
+func compareInts64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
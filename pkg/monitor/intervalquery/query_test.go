@@ -0,0 +1,87 @@
+package intervalquery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestParse(t *testing.T) {
+	start := time.Date(2023, 2, 14, 20, 30, 0, 0, time.UTC)
+	ingress := monitorapi.Interval{
+		Condition: monitorapi.Condition{
+			Level: monitorapi.Error,
+			Locator: monitorapi.Locator{
+				Type: monitorapi.LocatorTypeDisruption,
+				Keys: map[monitorapi.LocatorKey]string{
+					monitorapi.LocatorBackendDisruptionNameKey: "ingress-to-console-new-connections",
+				},
+			},
+			Message: monitorapi.Message{
+				Reason:       monitorapi.DisruptionBeganEventReason,
+				HumanMessage: "backend unreachable",
+				Annotations: map[monitorapi.AnnotationKey]string{
+					monitorapi.AnnotationReason: string(monitorapi.DisruptionBeganEventReason),
+				},
+			},
+		},
+		Source:  monitorapi.SourceDisruption,
+		Display: true,
+		From:    start,
+		To:      start.Add(10 * time.Second),
+	}
+	open := monitorapi.Interval{
+		Condition: monitorapi.Condition{Level: monitorapi.Info},
+		Source:    monitorapi.SourceE2ETest,
+		From:      start,
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		interval monitorapi.Interval
+		want     bool
+		wantErr  bool
+	}{
+		{name: "empty", query: "", interval: open, want: true},
+		{name: "request example", query: `source=Disruption and duration>5s and keys.backend-disruption-name~"ingress.*"`, interval: ingress, want: true},
+		{name: "duration too short", query: `source=Disruption and duration>15s`, interval: ingress, want: false},
+		{name: "open intervals have no duration", query: `duration<1s`, interval: open, want: false},
+		{name: "open intervals differ from every duration", query: `duration!=1s`, interval: open, want: true},
+		{name: "or", query: `source=E2ETest or level=Error`, interval: ingress, want: true},
+		{name: "and binds tighter than or", query: `source=E2ETest and level=Info or display=true`, interval: ingress, want: true},
+		{name: "not with parentheses", query: `not (source=E2ETest or level=Info)`, interval: ingress, want: true},
+		{name: "level ordering", query: `level>=Warning`, interval: ingress, want: true},
+		{name: "level ordering excludes info", query: `level>=Warning`, interval: open, want: false},
+		{name: "annotation", query: `annotations.reason=DisruptionBegan`, interval: ingress, want: true},
+		{name: "missing key is empty", query: `keys.node=""`, interval: ingress, want: true},
+		{name: "regex mismatch", query: `message!~"unreachable$"`, interval: ingress, want: false},
+		{name: "locator type", query: `locator.type=Disruption`, interval: ingress, want: true},
+		{name: "time window", query: `from>=2023-02-14T20:00:00Z and to<2023-02-14T21:00:00Z`, interval: ingress, want: true},
+		{name: "time window excludes open", query: `to<2023-02-14T21:00:00Z`, interval: open, want: false},
+		{name: "keywords are case insensitive", query: `source=Disruption AND NOT level=Info`, interval: ingress, want: true},
+		{name: "unknown field", query: `bogus=1`, wantErr: true},
+		{name: "bad operator for field", query: `source>Disruption`, wantErr: true},
+		{name: "bad duration", query: `duration>five`, wantErr: true},
+		{name: "bad level", query: `level=Fatal`, wantErr: true},
+		{name: "unbalanced", query: `(source=Disruption`, wantErr: true},
+		{name: "missing value", query: `source=`, wantErr: true},
+		{name: "trailing tokens", query: `source=Disruption level=Info`, wantErr: true},
+		{name: "unterminated string", query: `message="abc`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, err := Parse(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := matches(tt.interval); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
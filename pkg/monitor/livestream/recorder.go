@@ -0,0 +1,135 @@
+package livestream
+
+import (
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// subscriberBufferSize is how many intervals may wait for a slow client before intervals for it are dropped.
+const subscriberBufferSize = 1000
+
+// broadcastRecorder passes every interval to its delegate and to every subscriber.  Intervals are broadcast when they
+// are added, when they are started, and again when they are ended.  Recording never blocks on a subscriber.
+type broadcastRecorder struct {
+	delegate monitorapi.Recorder
+
+	lock        sync.Mutex
+	subscribers map[*subscriber]bool
+}
+
+type subscriber struct {
+	filter    intervalFilter
+	intervals chan monitorapi.Interval
+
+	lock    sync.Mutex
+	dropped int
+}
+
+var _ monitorapi.Recorder = &broadcastRecorder{}
+
+func (m *broadcastRecorder) subscribe(filter intervalFilter) *subscriber {
+	ret := &subscriber{
+		filter:    filter,
+		intervals: make(chan monitorapi.Interval, subscriberBufferSize),
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.subscribers[ret] = true
+	return ret
+}
+
+func (m *broadcastRecorder) unsubscribe(s *subscriber) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.subscribers, s)
+}
+
+// takeDropped returns the number of intervals dropped since the last call.
+func (s *subscriber) takeDropped() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	ret := s.dropped
+	s.dropped = 0
+	return ret
+}
+
+func (m *broadcastRecorder) broadcast(intervals ...monitorapi.Interval) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for s := range m.subscribers {
+		for _, interval := range intervals {
+			if !s.filter.matches(interval) {
+				continue
+			}
+			select {
+			case s.intervals <- interval:
+			default:
+				s.lock.Lock()
+				s.dropped++
+				s.lock.Unlock()
+			}
+		}
+	}
+}
+
+func (m *broadcastRecorder) CurrentResourceState() monitorapi.ResourcesMap {
+	return m.delegate.CurrentResourceState()
+}
+
+func (m *broadcastRecorder) RecordResource(resourceType string, obj runtime.Object) {
+	m.delegate.RecordResource(resourceType, obj)
+}
+
+// Record captures one or more conditions at the current time. All conditions are recorded
+// in monotonic order as EventInterval objects.
+func (m *broadcastRecorder) Record(conditions ...monitorapi.Condition) {
+	m.RecordAt(time.Now().UTC(), conditions...)
+}
+
+// RecordAt captures one or more conditions at the provided time. All conditions are recorded
+// as EventInterval objects.
+func (m *broadcastRecorder) RecordAt(t time.Time, conditions ...monitorapi.Condition) {
+	if len(conditions) == 0 {
+		return
+	}
+	intervals := monitorapi.Intervals{}
+	for _, condition := range conditions {
+		intervals = append(intervals, monitorapi.Interval{
+			Condition: condition,
+			From:      t,
+			To:        t,
+		})
+	}
+	m.AddIntervals(intervals...)
+}
+
+// AddIntervals provides a mechanism to directly inject eventIntervals
+func (m *broadcastRecorder) AddIntervals(intervals ...monitorapi.Interval) {
+	m.delegate.AddIntervals(intervals...)
+	m.broadcast(intervals...)
+}
+
+// StartInterval inserts a record at time t with the provided condition and returns an opaque
+// locator to the interval. The caller may close the sample at any point by invoking EndInterval().
+func (m *broadcastRecorder) StartInterval(interval monitorapi.Interval) int {
+	ret := m.delegate.StartInterval(interval)
+	m.broadcast(interval)
+	return ret
+}
+
+// EndInterval updates the To of the interval started by StartInterval if it is greater than
+// the from.
+func (m *broadcastRecorder) EndInterval(startedInterval int, t time.Time) *monitorapi.Interval {
+	ret := m.delegate.EndInterval(startedInterval, t)
+	if ret != nil {
+		m.broadcast(*ret)
+	}
+	return ret
+}
+
+func (m *broadcastRecorder) Intervals(from, to time.Time) monitorapi.Intervals {
+	return m.delegate.Intervals(from, to)
+}
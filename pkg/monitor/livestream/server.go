@@ -0,0 +1,245 @@
+// Package livestream serves the intervals, tracked resources, and test progress of a running openshift-tests process
+// over a local HTTP server, so a long run can be watched while it happens instead of after the artifacts are written.
+package livestream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// ProgressFunc returns the current progress of the test suite, it must be safe to encode as JSON.
+type ProgressFunc func() interface{}
+
+// Server streams intervals as they are recorded.  Create it with NewServer, record through the Recorder it returns,
+// then Start it.
+//
+//	GET /intervals  streams intervals as JSON lines, or as Server-Sent Events with format=sse or an
+//	                Accept: text/event-stream header.  Intervals are filtered by source, level, and
+//	                locator=<key>=<value>; each may be repeated.  history=true first sends the intervals
+//	                recorded before the request.
+//	GET /resources  returns the tracked resources.
+//	GET /progress   returns the progress of the test suite.
+type Server struct {
+	recorder   *broadcastRecorder
+	progressFn ProgressFunc
+}
+
+// NewServer wraps delegate so that every interval recorded through the returned recorder is also streamed.
+// progressFn may be nil when there is no test suite progress to report.
+func NewServer(delegate monitorapi.Recorder, progressFn ProgressFunc) (*Server, monitorapi.Recorder) {
+	recorder := &broadcastRecorder{
+		delegate:    delegate,
+		subscribers: map[*subscriber]bool{},
+	}
+	return &Server{
+		recorder:   recorder,
+		progressFn: progressFn,
+	}, recorder
+}
+
+// Handler returns the handler serving the endpoints.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/intervals", s.serveIntervals)
+	mux.HandleFunc("/resources", s.serveResources)
+	mux.HandleFunc("/progress", s.serveProgress)
+	return mux
+}
+
+// Start listens on address and serves until ctx is cancelled.  It returns once the server is listening.
+func (s *Server) Start(ctx context.Context, address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("failed to listen for the live interval stream on %v: %w", address, err)
+	}
+	server := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logrus.WithError(err).Error("live interval stream stopped")
+		}
+	}()
+	logrus.Infof("streaming intervals on http://%s/intervals", listener.Addr())
+	return nil
+}
+
+func (s *Server) serveIntervals(w http.ResponseWriter, req *http.Request) {
+	filter, err := parseIntervalFilter(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	sse := req.URL.Query().Get("format") == "sse" ||
+		(len(req.URL.Query().Get("format")) == 0 && strings.Contains(req.Header.Get("Accept"), "text/event-stream"))
+
+	// subscribe before reading the history so no interval is missed in between, at the cost of possible duplicates.
+	subscription := s.recorder.subscribe(filter)
+	defer s.recorder.unsubscribe(subscription)
+
+	if sse {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+	} else {
+		w.Header().Set("Content-Type", "application/jsonl")
+	}
+	w.WriteHeader(http.StatusOK)
+
+	write := func(interval monitorapi.Interval) error {
+		intervalJSON, err := monitorserialization.IntervalToOneLineJSON(interval)
+		if err != nil {
+			return err
+		}
+		if sse {
+			_, err = fmt.Fprintf(w, "data: %s\n\n", intervalJSON)
+		} else {
+			_, err = fmt.Fprintf(w, "%s\n", intervalJSON)
+		}
+		return err
+	}
+
+	if req.URL.Query().Get("history") == "true" {
+		for _, interval := range s.recorder.Intervals(time.Time{}, time.Time{}) {
+			if !filter.matches(interval) {
+				continue
+			}
+			if err := write(interval); err != nil {
+				return
+			}
+		}
+	}
+	flusher.Flush()
+
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case interval := <-subscription.intervals:
+			if dropped := subscription.takeDropped(); dropped > 0 && sse {
+				fmt.Fprintf(w, "event: dropped\ndata: %d\n\n", dropped)
+			}
+			if err := write(interval); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// resourceList is the JSON form of a monitorapi.ResourcesMap, whose keys cannot be encoded.
+type resourceList map[string][]runtime.Object
+
+func (s *Server) serveResources(w http.ResponseWriter, req *http.Request) {
+	ret := resourceList{}
+	for resourceType, instances := range s.recorder.CurrentResourceState() {
+		keys := []monitorapi.InstanceKey{}
+		for key := range instances {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i].Namespace != keys[j].Namespace {
+				return keys[i].Namespace < keys[j].Namespace
+			}
+			if keys[i].Name != keys[j].Name {
+				return keys[i].Name < keys[j].Name
+			}
+			return keys[i].UID < keys[j].UID
+		})
+		for _, key := range keys {
+			ret[resourceType] = append(ret[resourceType], instances[key])
+		}
+	}
+	writeJSON(w, ret)
+}
+
+func (s *Server) serveProgress(w http.ResponseWriter, req *http.Request) {
+	if s.progressFn == nil {
+		http.Error(w, "no test suite is running in this process", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, s.progressFn())
+}
+
+func writeJSON(w http.ResponseWriter, obj interface{}) {
+	content, err := json.Marshal(obj)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(content)
+}
+
+// intervalFilter selects intervals, an empty filter selects everything.
+type intervalFilter struct {
+	sources  sets.String
+	levels   map[monitorapi.IntervalLevel]bool
+	locators map[monitorapi.LocatorKey]string
+}
+
+func parseIntervalFilter(req *http.Request) (intervalFilter, error) {
+	query := req.URL.Query()
+	ret := intervalFilter{
+		sources:  sets.NewString(query["source"]...),
+		levels:   map[monitorapi.IntervalLevel]bool{},
+		locators: map[monitorapi.LocatorKey]string{},
+	}
+	for _, level := range query["level"] {
+		parsed, err := monitorapi.ConditionLevelFromString(level)
+		if err != nil {
+			return intervalFilter{}, err
+		}
+		ret.levels[parsed] = true
+	}
+	for _, locator := range query["locator"] {
+		key, value, ok := strings.Cut(locator, "=")
+		if !ok {
+			return intervalFilter{}, fmt.Errorf("locator %q must be of the form <key>=<value>", locator)
+		}
+		ret.locators[monitorapi.LocatorKey(key)] = value
+	}
+	return ret, nil
+}
+
+func (f intervalFilter) matches(interval monitorapi.Interval) bool {
+	if len(f.sources) > 0 && !f.sources.Has(string(interval.Source)) {
+		return false
+	}
+	if len(f.levels) > 0 && !f.levels[interval.Level] {
+		return false
+	}
+	for key, value := range f.locators {
+		if interval.Locator.Keys[key] != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,125 @@
+package livestream
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type fakeRecorder struct {
+	lock      sync.Mutex
+	intervals monitorapi.Intervals
+}
+
+func (f *fakeRecorder) Intervals(from, to time.Time) monitorapi.Intervals {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return append(monitorapi.Intervals{}, f.intervals...)
+}
+func (f *fakeRecorder) CurrentResourceState() monitorapi.ResourcesMap {
+	return monitorapi.ResourcesMap{}
+}
+func (f *fakeRecorder) RecordResource(resourceType string, obj runtime.Object)   {}
+func (f *fakeRecorder) Record(conditions ...monitorapi.Condition)                {}
+func (f *fakeRecorder) RecordAt(t time.Time, conditions ...monitorapi.Condition) {}
+func (f *fakeRecorder) AddIntervals(intervals ...monitorapi.Interval) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.intervals = append(f.intervals, intervals...)
+}
+func (f *fakeRecorder) StartInterval(interval monitorapi.Interval) int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.intervals = append(f.intervals, interval)
+	return len(f.intervals) - 1
+}
+func (f *fakeRecorder) EndInterval(startedInterval int, t time.Time) *monitorapi.Interval {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.intervals[startedInterval].To = t
+	ret := f.intervals[startedInterval]
+	return &ret
+}
+
+func TestServerStreamsFilteredIntervals(t *testing.T) {
+	server, recorder := NewServer(&fakeRecorder{}, func() interface{} {
+		return map[string]int{"started": 3}
+	})
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+
+	recorder.AddIntervals(monitorapi.NewInterval(monitorapi.SourceE2ETest, monitorapi.Info).
+		Locator(monitorapi.NewLocator().E2ETest("before")).
+		Message(monitorapi.NewMessage().HumanMessage("started")).BuildNow())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, httpServer.URL+"/intervals?format=sse&history=true&source="+string(monitorapi.SourceE2ETest), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	// once the response has started we are subscribed.
+	recorder.AddIntervals(monitorapi.NewInterval(monitorapi.SourceNodeState, monitorapi.Info).
+		Locator(monitorapi.NewLocator().NodeFromName("node")).
+		Message(monitorapi.NewMessage().HumanMessage("filtered")).BuildNow())
+	id := recorder.StartInterval(monitorapi.NewInterval(monitorapi.SourceE2ETest, monitorapi.Info).
+		Locator(monitorapi.NewLocator().E2ETest("after")).
+		Message(monitorapi.NewMessage().HumanMessage("started")).BuildNow())
+	recorder.EndInterval(id, time.Now().Add(time.Second))
+
+	messages := []string{}
+	scanner := bufio.NewScanner(resp.Body)
+	for len(messages) < 3 && scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		interval, err := monitorserialization.IntervalFromJSON([]byte(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		messages = append(messages, interval.Locator.Keys[monitorapi.LocatorE2ETestKey]+" "+interval.Message.HumanMessage)
+	}
+	expected := []string{"before started", "after started", "after started"}
+	if strings.Join(messages, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected %v, got %v", expected, messages)
+	}
+
+	progressResp, err := http.Get(httpServer.URL + "/progress")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer progressResp.Body.Close()
+	progress := map[string]int{}
+	if err := json.NewDecoder(progressResp.Body).Decode(&progress); err != nil {
+		t.Fatal(err)
+	}
+	if progress["started"] != 3 {
+		t.Errorf("unexpected progress %v", progress)
+	}
+
+	badResp, err := http.Get(httpServer.URL + "/intervals?level=Bogus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	badResp.Body.Close()
+	if badResp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected an unknown level to be rejected, got %v", badResp.StatusCode)
+	}
+}
@@ -99,6 +99,22 @@ func (m *Monitor) Stop(ctx context.Context) (ResultState, error) {
 	// set the stop time for after we finished.
 	m.stopTime = time.Now()
 
+	m.constructAndEvaluate(ctx)
+
+	fmt.Fprintf(os.Stderr, "Cleaning up.\n")
+	cleanupJunits, err := m.monitorTestRegistry.Cleanup(ctx)
+	if err != nil {
+		// these errors are represented as junit, always continue to the next step
+		fmt.Fprintf(os.Stderr, "Error cleaning up, continuing, junit will reflect this. %v\n", err)
+	}
+	m.junits = append(m.junits, cleanupJunits...)
+
+	return m.resultState(), nil
+}
+
+// constructAndEvaluate computes intervals from everything recorded so far and evaluates the monitor tests against
+// the intervals between startTime and stopTime.  The caller must hold the lock.
+func (m *Monitor) constructAndEvaluate(ctx context.Context) {
 	fmt.Fprintf(os.Stderr, "Computing intervals.\n")
 	computedIntervals, computedJunit, err := m.monitorTestRegistry.ConstructComputedIntervals(
 		ctx,
@@ -128,18 +144,12 @@ func (m *Monitor) Stop(ctx context.Context) (ResultState, error) {
 		fmt.Fprintf(os.Stderr, "Error evaluating tests, continuing, junit will reflect this. %v\n", err)
 	}
 	m.junits = append(m.junits, monitorTestJunits...)
+}
 
-	fmt.Fprintf(os.Stderr, "Cleaning up.\n")
-	cleanupJunits, err := m.monitorTestRegistry.Cleanup(ctx)
-	if err != nil {
-		// these errors are represented as junit, always continue to the next step
-		fmt.Fprintf(os.Stderr, "Error cleaning up, continuing, junit will reflect this. %v\n", err)
-	}
-	m.junits = append(m.junits, cleanupJunits...)
-
+// resultState reports Failed if any test only failed, flakes do not count.  The caller must hold the lock.
+func (m *Monitor) resultState() ResultState {
 	successfulTestNames := sets.NewString()
 	failedTestNames := sets.NewString()
-	resultState := Succeeded
 	for _, junit := range m.junits {
 		if junit.FailureOutput != nil {
 			failedTestNames.Insert(junit.Name)
@@ -149,10 +159,9 @@ func (m *Monitor) Stop(ctx context.Context) (ResultState, error) {
 	}
 	onlyFailingTests := failedTestNames.Difference(successfulTestNames)
 	if len(onlyFailingTests) > 0 {
-		resultState = Failed
+		return Failed
 	}
-
-	return resultState, nil
+	return Succeeded
 }
 
 func (m *Monitor) SerializeResults(ctx context.Context, junitSuiteName, timeSuffix string) error {
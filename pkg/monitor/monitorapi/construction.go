@@ -417,6 +417,12 @@ func (b *LocatorBuilder) ClusterOperator(name string) Locator {
 	return b.Build()
 }
 
+// TestRunner locates intervals about the test runner itself, such as how many tests it runs at once.
+func (b *LocatorBuilder) TestRunner() Locator {
+	b.targetType = LocatorTypeTestRunner
+	return b.Build()
+}
+
 func (b *LocatorBuilder) Build() Locator {
 	ret := Locator{
 		Type: b.targetType,
@@ -0,0 +1,255 @@
+package monitorapi
+
+import (
+	"sort"
+	"time"
+)
+
+// IntervalKeyFunc returns the key that set operations group intervals by.  Intervals only coalesce with, intersect
+// with, or are subtracted by intervals that share their key.
+type IntervalKeyFunc func(interval Interval) string
+
+// ByLocator keys intervals by their entire locator.
+func ByLocator(interval Interval) string {
+	return interval.Locator.OldLocator()
+}
+
+// ByLocatorKey keys intervals by the value of a single locator key, for instance LocatorNodeKey to group everything
+// that happened to a node.  Intervals without the key share the empty key.
+func ByLocatorKey(key LocatorKey) IntervalKeyFunc {
+	return func(interval Interval) string {
+		return interval.Locator.Keys[key]
+	}
+}
+
+// GroupBy splits intervals by key, preserving their order within each group.
+func (intervals Intervals) GroupBy(keyFn IntervalKeyFunc) map[string]Intervals {
+	ret := map[string]Intervals{}
+	for _, interval := range intervals {
+		key := keyFn(interval)
+		ret[key] = append(ret[key], interval)
+	}
+	return ret
+}
+
+// Coalesce merges intervals with the same key that overlap or touch into a single interval, which keeps the condition,
+// source, and display of the earliest interval it was merged from.  Intervals that have not ended, or that end before
+// they start, are treated as instants at their From.  The result is sorted.
+func (intervals Intervals) Coalesce(keyFn IntervalKeyFunc) Intervals {
+	ret := Intervals{}
+	for _, group := range intervals.GroupBy(keyFn) {
+		sorted := make(Intervals, len(group))
+		copy(sorted, group)
+		sort.Sort(sorted)
+
+		curr := sorted[0]
+		for _, next := range sorted[1:] {
+			if next.From.After(endOf(curr)) {
+				ret = append(ret, curr)
+				curr = next
+				continue
+			}
+			if nextEnd := endOf(next); nextEnd.After(endOf(curr)) {
+				curr.To = nextEnd
+			}
+		}
+		ret = append(ret, curr)
+	}
+	sort.Sort(ret)
+	return ret
+}
+
+// Union coalesces intervals together with other.
+func (intervals Intervals) Union(other Intervals, keyFn IntervalKeyFunc) Intervals {
+	all := make(Intervals, 0, len(intervals)+len(other))
+	all = append(all, intervals...)
+	all = append(all, other...)
+	return all.Coalesce(keyFn)
+}
+
+// Intersect returns the parts of intervals that are covered by windows, regardless of the key of either.  Each part
+// keeps the condition of the interval it was cut from.  An instant is kept when it is inside a window.  The result is
+// sorted.
+func (intervals Intervals) Intersect(windows Intervals) Intervals {
+	return intervals.IntersectByKey(windows, everything)
+}
+
+// IntersectByKey is like Intersect, but an interval is only cut by the windows that share its key.
+func (intervals Intervals) IntersectByKey(windows Intervals, keyFn IntervalKeyFunc) Intervals {
+	return intervals.clip(windows, keyFn, intersectSpans)
+}
+
+// Subtract returns the parts of intervals that are not covered by windows, regardless of the key of either.  This is
+// how blackout windows are removed.  Each part keeps the condition of the interval it was cut from.  An instant is
+// dropped when it is inside a window.  The result is sorted.
+func (intervals Intervals) Subtract(windows Intervals) Intervals {
+	return intervals.SubtractByKey(windows, everything)
+}
+
+// SubtractByKey is like Subtract, but an interval is only cut by the windows that share its key.  For instance, if
+// intervals has locator/foo covering 1:00-1:45 and windows has locator/foo covering 1:10-1:15 and 1:40-1:50, the
+// result has locator/foo covering 1:00-1:10 and 1:15-1:40.
+func (intervals Intervals) SubtractByKey(windows Intervals, keyFn IntervalKeyFunc) Intervals {
+	return intervals.clip(windows, keyFn, subtractSpans)
+}
+
+// CoveredDuration returns how long at least one of the intervals was in progress, so time covered by several
+// overlapping intervals is only counted once.  Instants cover nothing.
+func (intervals Intervals) CoveredDuration() time.Duration {
+	var ret time.Duration
+	for _, curr := range spansOf(intervals) {
+		ret += curr.to.Sub(curr.from)
+	}
+	return ret
+}
+
+// Overlapping returns the intervals that overlap [from, to).  An instant overlaps when it is inside [from, to), an
+// interval that touches from or to without crossing it does not overlap.
+func (intervals Intervals) Overlapping(from, to time.Time) Intervals {
+	ret := Intervals{}
+	for _, interval := range intervals {
+		end := endOf(interval)
+		if end.Equal(interval.From) {
+			if !interval.From.Before(from) && interval.From.Before(to) {
+				ret = append(ret, interval)
+			}
+			continue
+		}
+		if interval.From.Before(to) && end.After(from) {
+			ret = append(ret, interval)
+		}
+	}
+	return ret
+}
+
+// endOf returns the end of interval, which is its From when it has not ended or ends before it starts.
+func endOf(interval Interval) time.Time {
+	if interval.To.Before(interval.From) {
+		return interval.From
+	}
+	return interval.To
+}
+
+func everything(Interval) string {
+	return ""
+}
+
+// span is a stretch of time covered by one or more windows.
+type span struct {
+	from, to time.Time
+}
+
+// spansOf returns the sorted, non-overlapping spans covered by intervals.  Instants cover nothing.
+func spansOf(intervals Intervals) []span {
+	ret := []span{}
+	for _, interval := range intervals {
+		if end := endOf(interval); end.After(interval.From) {
+			ret = append(ret, span{from: interval.From, to: end})
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].from.Before(ret[j].from)
+	})
+
+	merged := []span{}
+	for _, curr := range ret {
+		if last := len(merged) - 1; last >= 0 && !curr.from.After(merged[last].to) {
+			if curr.to.After(merged[last].to) {
+				merged[last].to = curr.to
+			}
+			continue
+		}
+		merged = append(merged, curr)
+	}
+	return merged
+}
+
+// clipFunc cuts interval by spans and appends the resulting parts to ret.
+type clipFunc func(ret Intervals, interval Interval, spans []span) Intervals
+
+func (intervals Intervals) clip(windows Intervals, keyFn IntervalKeyFunc, clipFn clipFunc) Intervals {
+	spansByKey := map[string][]span{}
+	for key, group := range windows.GroupBy(keyFn) {
+		spansByKey[key] = spansOf(group)
+	}
+
+	ret := Intervals{}
+	for _, interval := range intervals {
+		ret = clipFn(ret, interval, spansByKey[keyFn(interval)])
+	}
+	sort.Sort(ret)
+	return ret
+}
+
+// containing returns the span that contains t, or nil.
+func containing(spans []span, t time.Time) *span {
+	i := sort.Search(len(spans), func(i int) bool {
+		return spans[i].to.After(t)
+	})
+	if i < len(spans) && !spans[i].from.After(t) {
+		return &spans[i]
+	}
+	return nil
+}
+
+func subtractSpans(ret Intervals, interval Interval, spans []span) Intervals {
+	end := endOf(interval)
+	if !end.After(interval.From) {
+		if containing(spans, interval.From) == nil {
+			ret = append(ret, interval)
+		}
+		return ret
+	}
+
+	curr := interval.From
+	for _, window := range spans {
+		if !window.to.After(curr) {
+			continue
+		}
+		if !window.from.Before(end) {
+			break
+		}
+		if window.from.After(curr) {
+			part := interval
+			part.From, part.To = curr, window.from
+			ret = append(ret, part)
+		}
+		curr = window.to
+		if !curr.Before(end) {
+			return ret
+		}
+	}
+	part := interval
+	part.From, part.To = curr, end
+	return append(ret, part)
+}
+
+func intersectSpans(ret Intervals, interval Interval, spans []span) Intervals {
+	end := endOf(interval)
+	if !end.After(interval.From) {
+		if containing(spans, interval.From) != nil {
+			ret = append(ret, interval)
+		}
+		return ret
+	}
+
+	for _, window := range spans {
+		if !window.to.After(interval.From) {
+			continue
+		}
+		if !window.from.Before(end) {
+			break
+		}
+		part := interval
+		if window.from.After(part.From) {
+			part.From = window.from
+		}
+		if window.to.Before(end) {
+			part.To = window.to
+		} else {
+			part.To = end
+		}
+		ret = append(ret, part)
+	}
+	return ret
+}
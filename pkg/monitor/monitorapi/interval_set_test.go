@@ -0,0 +1,377 @@
+package monitorapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var setStart = time.Date(2022, 3, 22, 19, 0, 0, 0, time.UTC)
+
+// at returns setStart plus minutes.
+func at(minutes int) time.Time {
+	return setStart.Add(time.Duration(minutes) * time.Minute)
+}
+
+func nodeInterval(node string, from, to int) Interval {
+	ret := Interval{
+		Condition: Condition{
+			Locator: NewLocator().NodeFromName(node),
+		},
+		From: at(from),
+	}
+	if to >= 0 {
+		ret.To = at(to)
+	}
+	return ret
+}
+
+func podInterval(node, pod string, from, to int) Interval {
+	return Interval{
+		Condition: Condition{
+			Locator: Locator{
+				Type: LocatorTypePod,
+				Keys: map[LocatorKey]string{
+					LocatorNodeKey: node,
+					LocatorPodKey:  pod,
+				},
+			},
+		},
+		From: at(from),
+		To:   at(to),
+	}
+}
+
+func TestIntervals_Coalesce(t *testing.T) {
+	tests := []struct {
+		name      string
+		intervals Intervals
+		keyFn     IntervalKeyFunc
+		want      Intervals
+	}{
+		{
+			name:      "empty",
+			intervals: nil,
+			keyFn:     ByLocator,
+			want:      Intervals{},
+		},
+		{
+			name:      "no-overlap",
+			intervals: Intervals{nodeInterval("foo", 0, 10), nodeInterval("foo", 15, 20)},
+			keyFn:     ByLocator,
+			want:      Intervals{nodeInterval("foo", 0, 10), nodeInterval("foo", 15, 20)},
+		},
+		{
+			name:      "fully-contained",
+			intervals: Intervals{nodeInterval("foo", 0, 10), nodeInterval("foo", -5, 20)},
+			keyFn:     ByLocator,
+			want:      Intervals{nodeInterval("foo", -5, 20)},
+		},
+		{
+			name:      "overlap-beginning",
+			intervals: Intervals{nodeInterval("foo", 0, 10), nodeInterval("foo", -5, 5)},
+			keyFn:     ByLocator,
+			want:      Intervals{nodeInterval("foo", -5, 10)},
+		},
+		{
+			name:      "overlap-end",
+			intervals: Intervals{nodeInterval("foo", 0, 10), nodeInterval("foo", 5, 20)},
+			keyFn:     ByLocator,
+			want:      Intervals{nodeInterval("foo", 0, 20)},
+		},
+		{
+			name:      "touching",
+			intervals: Intervals{nodeInterval("foo", 0, 10), nodeInterval("foo", 10, 20)},
+			keyFn:     ByLocator,
+			want:      Intervals{nodeInterval("foo", 0, 20)},
+		},
+		{
+			name:      "different-locators-are-not-merged",
+			intervals: Intervals{nodeInterval("foo", 0, 10), nodeInterval("bar", 5, 20)},
+			keyFn:     ByLocator,
+			want:      Intervals{nodeInterval("foo", 0, 10), nodeInterval("bar", 5, 20)},
+		},
+		{
+			name:      "instant-inside-is-absorbed",
+			intervals: Intervals{nodeInterval("foo", 0, 10), nodeInterval("foo", 5, -1)},
+			keyFn:     ByLocator,
+			want:      Intervals{nodeInterval("foo", 0, 10)},
+		},
+		{
+			name:      "earliest-condition-wins-by-locator-key",
+			intervals: Intervals{podInterval("foo", "b", 5, 20), podInterval("foo", "a", 0, 10), podInterval("bar", "c", 0, 10)},
+			keyFn:     ByLocatorKey(LocatorNodeKey),
+			want:      Intervals{podInterval("bar", "c", 0, 10), podInterval("foo", "a", 0, 20)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.intervals.Coalesce(tt.keyFn))
+		})
+	}
+}
+
+// TestIntervals_CoalesceBlackoutWindows covers the merging of the blackout windows of the alert analyzer.
+func TestIntervals_CoalesceBlackoutWindows(t *testing.T) {
+	window := func(from, to string) Interval {
+		return Interval{From: timeOrDie(from), To: timeOrDie(to)}
+	}
+	tests := []struct {
+		name            string
+		blackoutWindows Intervals
+		want            Intervals
+	}{
+		{
+			name: "no-overlap",
+			blackoutWindows: Intervals{
+				window("2022-03-22T19:00:00Z", "2022-03-22T19:10:00Z"),
+				window("2022-03-22T19:15:00Z", "2022-03-22T19:20:00Z"),
+			},
+			want: Intervals{
+				window("2022-03-22T19:00:00Z", "2022-03-22T19:10:00Z"),
+				window("2022-03-22T19:15:00Z", "2022-03-22T19:20:00Z"),
+			},
+		},
+		{
+			name: "fully-contained",
+			blackoutWindows: Intervals{
+				window("2022-03-22T19:00:00Z", "2022-03-22T19:10:00Z"),
+				window("2022-03-22T18:55:00Z", "2022-03-22T19:20:00Z"),
+			},
+			want: Intervals{
+				window("2022-03-22T18:55:00Z", "2022-03-22T19:20:00Z"),
+			},
+		},
+		{
+			name: "fully-contained-reverse",
+			blackoutWindows: Intervals{
+				window("2022-03-22T18:55:00Z", "2022-03-22T19:20:00Z"),
+				window("2022-03-22T19:00:00Z", "2022-03-22T19:10:00Z"),
+			},
+			want: Intervals{
+				window("2022-03-22T18:55:00Z", "2022-03-22T19:20:00Z"),
+			},
+		},
+		{
+			name: "overlap-beginning",
+			blackoutWindows: Intervals{
+				window("2022-03-22T19:00:00Z", "2022-03-22T19:10:00Z"),
+				window("2022-03-22T18:55:00Z", "2022-03-22T19:05:00Z"),
+			},
+			want: Intervals{
+				window("2022-03-22T18:55:00Z", "2022-03-22T19:10:00Z"),
+			},
+		},
+		{
+			name: "overlap-end",
+			blackoutWindows: Intervals{
+				window("2022-03-22T19:00:00Z", "2022-03-22T19:10:00Z"),
+				window("2022-03-22T19:05:00Z", "2022-03-22T19:20:00Z"),
+			},
+			want: Intervals{
+				window("2022-03-22T19:00:00Z", "2022-03-22T19:20:00Z"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.blackoutWindows.Coalesce(ByLocator))
+		})
+	}
+}
+
+func timeOrDie(in string) time.Time {
+	ret, err := time.Parse(time.RFC3339, in)
+	if err != nil {
+		panic(err)
+	}
+	return ret
+}
+
+func TestIntervals_Union(t *testing.T) {
+	got := Intervals{nodeInterval("foo", 0, 10)}.Union(Intervals{nodeInterval("foo", 5, 15), nodeInterval("bar", 0, 1)}, ByLocator)
+	assert.Equal(t, Intervals{nodeInterval("bar", 0, 1), nodeInterval("foo", 0, 15)}, got)
+}
+
+func TestIntervals_SubtractByKey(t *testing.T) {
+	tests := []struct {
+		name      string
+		intervals Intervals
+		windows   Intervals
+		want      Intervals
+	}{
+		{
+			name:      "no-blackout-for-locator",
+			intervals: Intervals{nodeInterval("foo", 0, 10), nodeInterval("foo", 5, 20)},
+			windows:   Intervals{nodeInterval("bar", 0, 10), nodeInterval("bar", 5, 20)},
+			want:      Intervals{nodeInterval("foo", 0, 10), nodeInterval("foo", 5, 20)},
+		},
+		{
+			name:      "full-blackout",
+			intervals: Intervals{nodeInterval("foo", 0, 10), nodeInterval("foo", 5, 20)},
+			windows:   Intervals{nodeInterval("foo", 0, 8), nodeInterval("foo", 5, 20)},
+			want:      Intervals{},
+		},
+		{
+			name:      "trailing-blackout",
+			intervals: Intervals{nodeInterval("foo", 0, 10), nodeInterval("foo", 5, 20)},
+			windows:   Intervals{nodeInterval("foo", 5, 20)},
+			want:      Intervals{nodeInterval("foo", 0, 5)},
+		},
+		{
+			name:      "partial-blackouts",
+			intervals: Intervals{nodeInterval("foo", 0, 10)},
+			windows:   Intervals{nodeInterval("foo", 4, 5), nodeInterval("foo", 1, 2)},
+			want:      Intervals{nodeInterval("foo", 0, 1), nodeInterval("foo", 2, 4), nodeInterval("foo", 5, 10)},
+		},
+		{
+			name:      "leading-blackout",
+			intervals: Intervals{nodeInterval("foo", 0, 10)},
+			windows:   Intervals{nodeInterval("foo", -5, 2), nodeInterval("foo", 4, 5)},
+			want:      Intervals{nodeInterval("foo", 2, 4), nodeInterval("foo", 5, 10)},
+		},
+		{
+			name:      "instants",
+			intervals: Intervals{nodeInterval("foo", 3, -1), nodeInterval("foo", 5, 5), nodeInterval("foo", 10, -1)},
+			windows:   Intervals{nodeInterval("foo", 5, 10)},
+			want:      Intervals{nodeInterval("foo", 3, -1), nodeInterval("foo", 10, -1)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.intervals.SubtractByKey(tt.windows, ByLocator))
+		})
+	}
+}
+
+func TestIntervals_Subtract(t *testing.T) {
+	got := Intervals{nodeInterval("foo", 0, 10)}.Subtract(Intervals{nodeInterval("bar", 2, 4)})
+	assert.Equal(t, Intervals{nodeInterval("foo", 0, 2), nodeInterval("foo", 4, 10)}, got)
+}
+
+func TestIntervals_Intersect(t *testing.T) {
+	tests := []struct {
+		name      string
+		intervals Intervals
+		windows   Intervals
+		keyFn     IntervalKeyFunc
+		want      Intervals
+	}{
+		{
+			name:      "any-key",
+			intervals: Intervals{nodeInterval("foo", 0, 10), nodeInterval("bar", 8, 12)},
+			windows:   Intervals{nodeInterval("baz", 2, 4), nodeInterval("baz", 3, 9)},
+			want:      Intervals{nodeInterval("foo", 2, 9), nodeInterval("bar", 8, 9)},
+		},
+		{
+			name:      "by-key",
+			intervals: Intervals{nodeInterval("foo", 0, 10), nodeInterval("bar", 0, 10)},
+			windows:   Intervals{nodeInterval("foo", 2, 4), nodeInterval("foo", 6, 20)},
+			keyFn:     ByLocator,
+			want:      Intervals{nodeInterval("foo", 2, 4), nodeInterval("foo", 6, 10)},
+		},
+		{
+			name:      "instants",
+			intervals: Intervals{nodeInterval("foo", 3, -1), nodeInterval("foo", 5, -1), nodeInterval("foo", 10, -1)},
+			windows:   Intervals{nodeInterval("foo", 5, 10)},
+			keyFn:     ByLocator,
+			want:      Intervals{nodeInterval("foo", 5, -1)},
+		},
+		{
+			name:      "no-windows",
+			intervals: Intervals{nodeInterval("foo", 0, 10)},
+			keyFn:     ByLocator,
+			want:      Intervals{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Intervals
+			if tt.keyFn == nil {
+				got = tt.intervals.Intersect(tt.windows)
+			} else {
+				got = tt.intervals.IntersectByKey(tt.windows, tt.keyFn)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIntervals_CoveredDuration(t *testing.T) {
+	tests := []struct {
+		name      string
+		intervals Intervals
+		want      time.Duration
+	}{
+		{
+			name: "empty",
+			want: 0,
+		},
+		{
+			name:      "overlap-counted-once",
+			intervals: Intervals{nodeInterval("foo", 0, 10), nodeInterval("bar", 5, 15), nodeInterval("foo", 1, 2)},
+			want:      15 * time.Minute,
+		},
+		{
+			name:      "gaps-are-not-counted",
+			intervals: Intervals{nodeInterval("foo", 20, 30), nodeInterval("foo", 0, 10)},
+			want:      20 * time.Minute,
+		},
+		{
+			name:      "instants-cover-nothing",
+			intervals: Intervals{nodeInterval("foo", 0, -1), nodeInterval("foo", 5, 5)},
+			want:      0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.intervals.CoveredDuration())
+		})
+	}
+}
+
+func TestIntervals_Overlapping(t *testing.T) {
+	intervals := Intervals{
+		nodeInterval("before", 0, 5),
+		nodeInterval("crossing-start", 3, 7),
+		nodeInterval("inside", 6, 8),
+		nodeInterval("same", 5, 10),
+		nodeInterval("instant-at-start", 5, -1),
+		nodeInterval("instant-at-end", 10, -1),
+		nodeInterval("after", 10, 15),
+	}
+	got := intervals.Overlapping(at(5), at(10))
+	assert.Equal(t, Intervals{
+		nodeInterval("crossing-start", 3, 7),
+		nodeInterval("inside", 6, 8),
+		nodeInterval("same", 5, 10),
+		nodeInterval("instant-at-start", 5, -1),
+	}, got)
+}
+
+func TestIntervals_OverlappingBoundaries(t *testing.T) {
+	intervals := Intervals{
+		nodeInterval("ends-at-from", 0, 5),
+		nodeInterval("starts-at-from-ends-after-to", 5, 15),
+		nodeInterval("across", 0, 15),
+		nodeInterval("starts-at-to", 10, 15),
+	}
+	assert.Equal(t, Intervals{
+		nodeInterval("starts-at-from-ends-after-to", 5, 15),
+		nodeInterval("across", 0, 15),
+	}, intervals.Overlapping(at(5), at(10)))
+
+	// an empty window only overlaps the intervals in progress across it
+	assert.Equal(t, Intervals{
+		nodeInterval("across", 0, 15),
+	}, intervals.Overlapping(at(5), at(5)))
+}
+
+func TestIntervals_GroupBy(t *testing.T) {
+	intervals := Intervals{podInterval("foo", "a", 0, 1), podInterval("bar", "b", 0, 1), podInterval("foo", "c", 2, 3)}
+	assert.Equal(t, map[string]Intervals{
+		"foo": {podInterval("foo", "a", 0, 1), podInterval("foo", "c", 2, 3)},
+		"bar": {podInterval("bar", "b", 0, 1)},
+	}, intervals.GroupBy(ByLocatorKey(LocatorNodeKey)))
+}
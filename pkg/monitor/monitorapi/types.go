@@ -108,6 +108,7 @@ const (
 	LocatorTypeClusterVersion  LocatorType = "ClusterVersion"
 	LocatorTypeKind            LocatorType = "Kind"
 	LocatorTypeCloudMetrics    LocatorType = "CloudMetrics"
+	LocatorTypeTestRunner      LocatorType = "TestRunner"
 )
 
 type LocatorKey string
@@ -160,6 +161,8 @@ const (
 	DisruptionSamplerOutageBeganEventReason IntervalReason = "DisruptionSamplerOutageBegan"
 	GracefulAPIServerShutdown               IntervalReason = "GracefulAPIServerShutdown"
 	IncompleteAPIServerShutdown             IntervalReason = "IncompleteAPIServerShutdown"
+	LatencyDegradedEventReason              IntervalReason = "LatencyDegraded"
+	LatencySummaryEventReason               IntervalReason = "LatencySummary"
 
 	HttpClientConnectionLost IntervalReason = "HttpClientConnectionLost"
 
@@ -190,6 +193,7 @@ const (
 
 	NodeUpdateReason   IntervalReason = "NodeUpdate"
 	NodeNotReadyReason IntervalReason = "NotReady"
+	NodeDeletedReason  IntervalReason = "Deleted"
 	NodeFailedLease    IntervalReason = "FailedToUpdateLease"
 
 	MachineConfigChangeReason  IntervalReason = "MachineConfigChange"
@@ -200,6 +204,8 @@ const (
 	E2ETestStarted  IntervalReason = "E2ETestStarted"
 	E2ETestFinished IntervalReason = "E2ETestFinished"
 
+	TestParallelismChangedReason IntervalReason = "TestParallelismChanged"
+
 	CloudMetricsExtrenuous                IntervalReason = "CloudMetricsExtrenuous"
 	FailedToDeleteCGroupsPath             IntervalReason = "FailedToDeleteCGroupsPath"
 	FailedToAuthenticateWithOpenShiftUser IntervalReason = "FailedToAuthenticateWithOpenShiftUser"
@@ -243,6 +249,14 @@ const (
 	AnnotationRoles          AnnotationKey = "roles"
 	AnnotationStatus         AnnotationKey = "status"
 	AnnotationCondition      AnnotationKey = "condition"
+	AnnotationParallelism    AnnotationKey = "parallelism"
+	AnnotationErrorCategory  AnnotationKey = "error-category"
+	AnnotationPercentile     AnnotationKey = "percentile"
+	AnnotationLatency        AnnotationKey = "latency"
+	AnnotationLatencyP50     AnnotationKey = "latency-p50"
+	AnnotationLatencyP95     AnnotationKey = "latency-p95"
+	AnnotationLatencyP99     AnnotationKey = "latency-p99"
+	AnnotationHistogram      AnnotationKey = "histogram"
 )
 
 // ConstructionOwner was originally meant to signify that an interval was derived from other intervals.
@@ -297,6 +311,8 @@ const (
 	SourcePathologicalEventMarker IntervalSource = "PathologicalEventMarker" // not sure if this is really helpful since the events all have a different origin
 	SourceClusterOperatorMonitor  IntervalSource = "ClusterOperatorMonitor"
 	SourceOperatorState           IntervalSource = "OperatorState"
+	SourceTestParallelism         IntervalSource = "TestParallelism"
+	SourceBackendLatency          IntervalSource = "BackendLatency"
 	SourceNodeState                              = "NodeState"
 	SourcePodState                               = "PodState"
 	SourceCloudMetrics                           = "CloudMetrics"
@@ -0,0 +1,446 @@
+package monitor
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	// defaultSegmentPartition is the span of From times covered by one segment file.
+	defaultSegmentPartition = time.Hour
+	// defaultMaxPendingIntervals is how many finished intervals are held in memory before they are spilled to disk.
+	defaultMaxPendingIntervals = 10000
+)
+
+// diskRecorder holds intervals in an append-only log on disk instead of in memory, so that long or repeated runs
+// do not grow without bound.  Finished intervals are buffered in memory and periodically spilled into immutable
+// segment files that are sorted and partitioned by the From of their intervals.  Intervals started with
+// StartInterval stay in memory until EndInterval is called.
+//
+// Tracked resources are still held in memory, there is one copy per resource regardless of how long the run is.
+type diskRecorder struct {
+	resources *recorder
+
+	lock                sync.Mutex
+	dir                 string
+	segmentPartition    time.Duration
+	maxPendingIntervals int
+
+	nextSegment int
+	segments    []*intervalSegment
+
+	// pending are finished intervals, and intervals started by StartInterval, that have not been spilled yet.
+	pending monitorapi.Intervals
+	// started maps the ID returned from StartInterval to the interval's index in pending while it is still in memory.
+	started       map[int]int
+	nextStartedID int
+	// open are the IDs of started intervals that have not been ended, they are never spilled.
+	open sets.Int
+}
+
+// intervalSegment is an immutable file of intervals sorted the same way as monitorapi.Intervals.
+type intervalSegment struct {
+	filename string
+	minFrom  time.Time
+	maxFrom  time.Time
+	maxTo    time.Time
+	// hasOpenEnding is true when an interval in the segment has a zero To.
+	hasOpenEnding bool
+}
+
+// NewDiskRecorder creates a recorder that spills intervals to segment files in dir.  The directory is created if it
+// does not exist, and the files are left in place so they can be inspected after the run.
+func NewDiskRecorder(dir string) (monitorapi.Recorder, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create interval directory %v: %w", dir, err)
+	}
+	return &diskRecorder{
+		resources:           NewRecorder().(*recorder),
+		dir:                 dir,
+		segmentPartition:    defaultSegmentPartition,
+		maxPendingIntervals: defaultMaxPendingIntervals,
+		started:             map[int]int{},
+		open:                sets.NewInt(),
+	}, nil
+}
+
+// NewRecorderFor returns an in-memory recorder when spillDir is empty and a disk backed recorder otherwise.
+func NewRecorderFor(spillDir string) (monitorapi.Recorder, error) {
+	if len(spillDir) == 0 {
+		return NewRecorder(), nil
+	}
+	return NewDiskRecorder(spillDir)
+}
+
+var _ monitorapi.Recorder = &diskRecorder{}
+
+func (m *diskRecorder) CurrentResourceState() monitorapi.ResourcesMap {
+	return m.resources.CurrentResourceState()
+}
+
+func (m *diskRecorder) RecordResource(resourceType string, obj runtime.Object) {
+	m.resources.RecordResource(resourceType, obj)
+}
+
+// Record captures one or more conditions at the current time. All conditions are recorded
+// in monotonic order as EventInterval objects.
+func (m *diskRecorder) Record(conditions ...monitorapi.Condition) {
+	m.RecordAt(time.Now().UTC(), conditions...)
+}
+
+// RecordAt captures one or more conditions at the provided time. All conditions are recorded
+// as EventInterval objects.
+func (m *diskRecorder) RecordAt(t time.Time, conditions ...monitorapi.Condition) {
+	if len(conditions) == 0 {
+		return
+	}
+	intervals := monitorapi.Intervals{}
+	for _, condition := range conditions {
+		intervals = append(intervals, monitorapi.Interval{
+			Condition: condition,
+			From:      t,
+			To:        t,
+		})
+	}
+	m.AddIntervals(intervals...)
+}
+
+// AddIntervals provides a mechanism to directly inject eventIntervals
+func (m *diskRecorder) AddIntervals(eventIntervals ...monitorapi.Interval) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.pending = append(m.pending, eventIntervals...)
+	m.spillIfNeededLocked()
+}
+
+// StartInterval inserts a record at time t with the provided condition and returns an opaque
+// locator to the interval. The caller may close the sample at any point by invoking EndInterval().
+func (m *diskRecorder) StartInterval(interval monitorapi.Interval) int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	id := m.nextStartedID
+	m.nextStartedID++
+	m.pending = append(m.pending, interval)
+	m.started[id] = len(m.pending) - 1
+	m.open.Insert(id)
+	return id
+}
+
+// EndInterval updates the To of the interval started by StartInterval if it is greater than
+// the from.  Once an ended interval has been spilled to disk it can no longer be updated and nil is returned.
+func (m *diskRecorder) EndInterval(startedInterval int, t time.Time) *monitorapi.Interval {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	index, ok := m.started[startedInterval]
+	if !ok {
+		return nil
+	}
+	if m.pending[index].From.Before(t) {
+		m.pending[index].To = t
+	}
+	ret := m.pending[index]
+	// the interval stays addressable until it is spilled, but it no longer holds back spilling.
+	m.open.Delete(startedInterval)
+	m.spillIfNeededLocked()
+	return &ret
+}
+
+// spillIfNeededLocked writes the finished pending intervals to new segment files once there are enough of them.
+// Intervals that were started and not yet ended stay in memory.
+func (m *diskRecorder) spillIfNeededLocked() {
+	if len(m.pending)-len(m.open) < m.maxPendingIntervals {
+		return
+	}
+	if err := m.spillLocked(); err != nil {
+		// keep the intervals in memory, we'll try again on the next spill.
+		logrus.WithError(err).Error("failed to spill intervals to disk")
+	}
+}
+
+func (m *diskRecorder) spillLocked() error {
+	openIndexes := map[int]int{}
+	for id := range m.open {
+		openIndexes[m.started[id]] = id
+	}
+
+	toSpill := monitorapi.Intervals{}
+	toKeep := monitorapi.Intervals{}
+	keptStarted := map[int]int{}
+	for i, interval := range m.pending {
+		if id, ok := openIndexes[i]; ok {
+			keptStarted[id] = len(toKeep)
+			toKeep = append(toKeep, interval)
+			continue
+		}
+		toSpill = append(toSpill, interval)
+	}
+	if len(toSpill) == 0 {
+		return nil
+	}
+
+	sort.Sort(toSpill)
+	// toSpill is sorted by From, so each partition is a contiguous run.
+	newSegments := []*intervalSegment{}
+	for len(toSpill) > 0 {
+		partition := toSpill[0].From.Truncate(m.segmentPartition)
+		end := sort.Search(len(toSpill), func(i int) bool {
+			return !toSpill[i].From.Before(partition.Add(m.segmentPartition))
+		})
+		segment, err := m.writeSegment(partition, toSpill[:end])
+		toSpill = toSpill[end:]
+		if err != nil {
+			// segments written so far are not referenced, the next attempt writes all the intervals again.
+			return err
+		}
+		newSegments = append(newSegments, segment)
+	}
+
+	m.segments = append(m.segments, newSegments...)
+	m.pending = toKeep
+	m.started = keptStarted
+	return nil
+}
+
+func (m *diskRecorder) writeSegment(partition time.Time, intervals monitorapi.Intervals) (*intervalSegment, error) {
+	segment := &intervalSegment{
+		filename: filepath.Join(m.dir, fmt.Sprintf("intervals-%s-%06d.jsonl", partition.UTC().Format("20060102-150405"), m.nextSegment)),
+		minFrom:  intervals[0].From,
+		maxFrom:  intervals[len(intervals)-1].From,
+	}
+
+	m.nextSegment++
+
+	file, err := os.Create(segment.filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	w := bufio.NewWriter(file)
+	encoder := json.NewEncoder(w)
+	for _, interval := range intervals {
+		if interval.To.IsZero() {
+			segment.hasOpenEnding = true
+		}
+		if interval.To.After(segment.maxTo) {
+			segment.maxTo = interval.To
+		}
+		if err := encoder.Encode(interval); err != nil {
+			return nil, fmt.Errorf("failed to write %v: %w", segment.filename, err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return nil, fmt.Errorf("failed to write %v: %w", segment.filename, err)
+	}
+	if err := file.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write %v: %w", segment.filename, err)
+	}
+	return segment, nil
+}
+
+// Intervals returns all events that occur between from and to, including
+// any sampled conditions that were encountered during that period.
+// Intervals are returned in order of their occurrence. The returned slice
+// is a copy of the monitor's state and is safe to update.
+//
+// Segments are merged one interval at a time, only the intervals that are returned are held in memory.
+func (m *diskRecorder) Intervals(from, to time.Time) monitorapi.Intervals {
+	m.lock.Lock()
+	inMemory := make(monitorapi.Intervals, len(m.pending))
+	copy(inMemory, m.pending)
+	segments := m.segmentsFor(from, to)
+	m.lock.Unlock()
+	sort.Sort(inMemory)
+
+	streams := []intervalStream{&sliceStream{intervals: inMemory}}
+	for _, segment := range segments {
+		stream, err := openSegmentStream(segment)
+		if err != nil {
+			logrus.WithError(err).Errorf("failed to read intervals from %v", segment.filename)
+			continue
+		}
+		defer stream.Close()
+		streams = append(streams, stream)
+	}
+
+	return sliceMerged(newMergedStream(streams), from, to)
+}
+
+// segmentsFor returns the segments that can contain intervals that monitorapi.Intervals.Slice would select for
+// from and to.  The caller must hold the lock.
+func (m *diskRecorder) segmentsFor(from, to time.Time) []*intervalSegment {
+	// Slice selects every interval after the first interval that ends after from, so a segment may only be skipped
+	// when all of its intervals start before any interval that could be that first interval.
+	var earliestPossibleFirst time.Time
+	if !from.IsZero() {
+		for _, segment := range m.segments {
+			if !segment.hasOpenEnding && segment.maxTo.Before(from) {
+				continue
+			}
+			if earliestPossibleFirst.IsZero() || segment.minFrom.Before(earliestPossibleFirst) {
+				earliestPossibleFirst = segment.minFrom
+			}
+		}
+		for _, interval := range m.pending {
+			if interval.To.IsZero() || !interval.To.Before(from) {
+				if earliestPossibleFirst.IsZero() || interval.From.Before(earliestPossibleFirst) {
+					earliestPossibleFirst = interval.From
+				}
+			}
+		}
+	}
+
+	ret := []*intervalSegment{}
+	for _, segment := range m.segments {
+		if !to.IsZero() && segment.minFrom.After(to) {
+			continue
+		}
+		if !earliestPossibleFirst.IsZero() && segment.maxFrom.Before(earliestPossibleFirst) {
+			continue
+		}
+		ret = append(ret, segment)
+	}
+	return ret
+}
+
+// sliceMerged applies the same selection as monitorapi.Intervals.Slice to sorted intervals read from stream.
+func sliceMerged(stream intervalStream, from, to time.Time) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	foundFirst := from.IsZero()
+	for {
+		curr, ok := stream.Next()
+		if !ok {
+			return ret
+		}
+		if !foundFirst {
+			if curr.To.IsZero() && (curr.From.After(from) || curr.From == from) {
+				foundFirst = true
+			}
+			if curr.To.After(from) || curr.To == from {
+				foundFirst = true
+			}
+			if !foundFirst {
+				continue
+			}
+		}
+		if !to.IsZero() && curr.From.After(to) {
+			return ret
+		}
+		ret = append(ret, curr)
+	}
+}
+
+type intervalStream interface {
+	// Next returns the next interval in sorted order, false when the stream is exhausted.
+	Next() (monitorapi.Interval, bool)
+}
+
+type sliceStream struct {
+	intervals monitorapi.Intervals
+}
+
+func (s *sliceStream) Next() (monitorapi.Interval, bool) {
+	if len(s.intervals) == 0 {
+		return monitorapi.Interval{}, false
+	}
+	ret := s.intervals[0]
+	s.intervals = s.intervals[1:]
+	return ret, true
+}
+
+type segmentStream struct {
+	filename string
+	file     *os.File
+	decoder  *json.Decoder
+}
+
+func openSegmentStream(segment *intervalSegment) (*segmentStream, error) {
+	file, err := os.Open(segment.filename)
+	if err != nil {
+		return nil, err
+	}
+	return &segmentStream{
+		filename: segment.filename,
+		file:     file,
+		decoder:  json.NewDecoder(bufio.NewReader(file)),
+	}, nil
+}
+
+func (s *segmentStream) Next() (monitorapi.Interval, bool) {
+	if !s.decoder.More() {
+		return monitorapi.Interval{}, false
+	}
+	ret := monitorapi.Interval{}
+	if err := s.decoder.Decode(&ret); err != nil {
+		logrus.WithError(err).Errorf("failed to read intervals from %v", s.filename)
+		return monitorapi.Interval{}, false
+	}
+	return ret, true
+}
+
+func (s *segmentStream) Close() error {
+	return s.file.Close()
+}
+
+// mergedStream is a k-way merge of sorted streams.
+type mergedStream struct {
+	heads mergeHeap
+}
+
+func newMergedStream(streams []intervalStream) *mergedStream {
+	ret := &mergedStream{}
+	for _, stream := range streams {
+		if curr, ok := stream.Next(); ok {
+			ret.heads = append(ret.heads, mergeHead{interval: curr, stream: stream})
+		}
+	}
+	heap.Init(&ret.heads)
+	return ret
+}
+
+func (s *mergedStream) Next() (monitorapi.Interval, bool) {
+	if len(s.heads) == 0 {
+		return monitorapi.Interval{}, false
+	}
+	ret := s.heads[0].interval
+	if next, ok := s.heads[0].stream.Next(); ok {
+		s.heads[0].interval = next
+		heap.Fix(&s.heads, 0)
+	} else {
+		heap.Pop(&s.heads)
+	}
+	return ret, true
+}
+
+type mergeHead struct {
+	interval monitorapi.Interval
+	stream   intervalStream
+}
+
+type mergeHeap []mergeHead
+
+func (h mergeHeap) Len() int { return len(h) }
+func (h mergeHeap) Less(i, j int) bool {
+	return monitorapi.Intervals{h[i].interval, h[j].interval}.Less(0, 1)
+}
+func (h mergeHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *mergeHeap) Push(x interface{}) {
+	*h = append(*h, x.(mergeHead))
+}
+func (h *mergeHeap) Pop() interface{} {
+	old := *h
+	ret := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return ret
+}
@@ -0,0 +1,93 @@
+package monitor
+
+import (
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestDiskRecorderMatchesRecorder(t *testing.T) {
+	dir := t.TempDir()
+	diskRecorderInterface, err := NewDiskRecorder(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	disk := diskRecorderInterface.(*diskRecorder)
+	disk.segmentPartition = 10 * time.Second
+	disk.maxPendingIntervals = 7
+	memory := NewRecorder()
+
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	random := rand.New(rand.NewSource(1))
+	condition := func(i int) monitorapi.Condition {
+		return monitorapi.NewInterval(monitorapi.SourceTestData, monitorapi.Info).
+			Locator(monitorapi.NewLocator().NodeFromName(fmt.Sprintf("node-%d", i%5))).
+			Message(monitorapi.NewMessage().HumanMessage(fmt.Sprintf("%d", i))).
+			BuildCondition()
+	}
+
+	diskStarted := map[int]int{}
+	memoryStarted := map[int]int{}
+	for i := 0; i < 200; i++ {
+		from := start.Add(time.Duration(random.Intn(60000)) * time.Millisecond)
+		interval := monitorapi.Interval{Condition: condition(i), From: from, To: from.Add(time.Duration(random.Intn(20000)) * time.Millisecond)}
+		switch random.Intn(4) {
+		case 0:
+			interval.To = time.Time{}
+			diskStarted[i] = disk.StartInterval(interval)
+			memoryStarted[i] = memory.StartInterval(interval)
+		case 1:
+			disk.RecordAt(from, interval.Condition)
+			memory.RecordAt(from, interval.Condition)
+		default:
+			disk.AddIntervals(interval)
+			memory.AddIntervals(interval)
+		}
+
+		// end about half of the started intervals, the rest remain open.
+		for j := range diskStarted {
+			if random.Intn(4) != 0 {
+				continue
+			}
+			end := start.Add(time.Duration(random.Intn(80000)) * time.Millisecond)
+			diskEnded := disk.EndInterval(diskStarted[j], end)
+			memoryEnded := memory.EndInterval(memoryStarted[j], end)
+			if !reflect.DeepEqual(diskEnded, memoryEnded) {
+				t.Fatalf("mismatched ended interval: %v %v", diskEnded, memoryEnded)
+			}
+			delete(diskStarted, j)
+			delete(memoryStarted, j)
+		}
+	}
+
+	segments, err := filepath.Glob(filepath.Join(dir, "intervals-*.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(segments) < 2 {
+		t.Fatalf("expected intervals to be spilled to multiple segments, got %v", segments)
+	}
+
+	bounds := []time.Time{
+		{},
+		start,
+		start.Add(15 * time.Second),
+		start.Add(30 * time.Second),
+		start.Add(45 * time.Second),
+		start.Add(2 * time.Minute),
+	}
+	for _, from := range bounds {
+		for _, to := range bounds {
+			expected := memory.Intervals(from, to).Strings()
+			actual := disk.Intervals(from, to).Strings()
+			if !reflect.DeepEqual(expected, actual) {
+				t.Errorf("from %v to %v: expected %d intervals, got %d", from, to, len(expected), len(actual))
+			}
+		}
+	}
+}
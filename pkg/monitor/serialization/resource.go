@@ -4,13 +4,19 @@ import (
 	"archive/zip"
 	"bytes"
 	"encoding/json"
+	"fmt"
+	"io"
 	"io/ioutil"
+	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
 	"k8s.io/kube-openapi/pkg/util/sets"
 )
 
@@ -73,3 +79,82 @@ func InstanceMapToFile(filename string, resourceType string, instances monitorap
 
 	return ioutil.WriteFile(filename, byteBuffer.Bytes(), 0644)
 }
+
+// typedResources lists the tracked resource types that monitor tests type-assert on.  Resources of these types
+// are decoded into their typed form when read back, all others are left as unstructured.
+var typedResources = map[string]func() runtime.Object{
+	"pods":   func() runtime.Object { return &corev1.Pod{} },
+	"events": func() runtime.Object { return &corev1.Event{} },
+}
+
+// InstanceMapFromFile reads a file written by InstanceMapToFile and returns the resources it contains, keyed by
+// resource type.
+func InstanceMapFromFile(filename string) (monitorapi.ResourcesMap, error) {
+	zipReader, err := zip.OpenReader(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer zipReader.Close()
+
+	ret := monitorapi.ResourcesMap{}
+	for _, file := range zipReader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		resourceType := strings.TrimSuffix(path.Base(file.Name), ".json")
+
+		content, err := readZipFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading %q from %q: %w", file.Name, filename, err)
+		}
+		// items are written without TypeMeta, so decode them directly rather than through the unstructured scheme.
+		nsItems := struct {
+			Items []map[string]interface{} `json:"items"`
+		}{}
+		if err := utiljson.Unmarshal(content, &nsItems); err != nil {
+			return nil, fmt.Errorf("failed decoding %q from %q: %w", file.Name, filename, err)
+		}
+
+		instances, ok := ret[resourceType]
+		if !ok {
+			instances = monitorapi.InstanceMap{}
+			ret[resourceType] = instances
+		}
+		for i := range nsItems.Items {
+			item := &unstructured.Unstructured{Object: nsItems.Items[i]}
+			obj, err := toTypedResource(resourceType, item)
+			if err != nil {
+				return nil, fmt.Errorf("failed converting %s %s/%s: %w", resourceType, item.GetNamespace(), item.GetName(), err)
+			}
+			key := monitorapi.InstanceKey{
+				Namespace: item.GetNamespace(),
+				Name:      item.GetName(),
+				UID:       fmt.Sprintf("%v", item.GetUID()),
+			}
+			instances[key] = obj
+		}
+	}
+
+	return ret, nil
+}
+
+func readZipFile(file *zip.File) ([]byte, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+func toTypedResource(resourceType string, item *unstructured.Unstructured) (runtime.Object, error) {
+	newObj, ok := typedResources[resourceType]
+	if !ok {
+		return item, nil
+	}
+	obj := newObj()
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.UnstructuredContent(), obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
@@ -0,0 +1,70 @@
+package monitorserialization
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestInstanceMapRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns-a",
+			Name:      "pod-a",
+			UID:       "uid-a",
+			Annotations: map[string]string{
+				monitorapi.ObservedUpdateCountAnnotation: "4",
+			},
+		},
+		Spec: corev1.PodSpec{NodeName: "node-a"},
+	}
+	podFile := filepath.Join(dir, "resource-pods_suffix.zip")
+	if err := InstanceMapToFile(podFile, "pods", monitorapi.InstanceMap{
+		{Namespace: "ns-a", Name: "pod-a", UID: "uid-a"}: pod,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	resources, err := InstanceMapFromFile(podFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj, ok := resources["pods"][monitorapi.InstanceKey{Namespace: "ns-a", Name: "pod-a", UID: "uid-a"}]
+	if !ok {
+		t.Fatalf("missing pod, got %v", resources)
+	}
+	actualPod, ok := obj.(*corev1.Pod)
+	if !ok {
+		t.Fatalf("expected *corev1.Pod, got %T", obj)
+	}
+	if actualPod.Spec.NodeName != "node-a" {
+		t.Errorf("expected node-a, got %q", actualPod.Spec.NodeName)
+	}
+	if actualPod.Annotations[monitorapi.ObservedUpdateCountAnnotation] != "4" {
+		t.Errorf("expected observed update count to be preserved, got %v", actualPod.Annotations)
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-b", Name: "cm-b", UID: "uid-b"},
+	}
+	configMapFile := filepath.Join(dir, "resource-configmaps_suffix.zip")
+	if err := InstanceMapToFile(configMapFile, "configmaps", monitorapi.InstanceMap{
+		{Namespace: "ns-b", Name: "cm-b", UID: "uid-b"}: configMap,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	resources, err = InstanceMapFromFile(configMapFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj = resources["configmaps"][monitorapi.InstanceKey{Namespace: "ns-b", Name: "cm-b", UID: "uid-b"}]
+	if _, ok := obj.(*unstructured.Unstructured); !ok {
+		t.Fatalf("expected untyped resources to be unstructured, got %T", obj)
+	}
+}
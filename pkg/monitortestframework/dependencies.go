@@ -0,0 +1,122 @@
+package monitortestframework
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// dependencyOrder sorts the monitorTests so that every test comes after the tests it depends on.  Ties are broken
+// by name so the order is stable.  Tests that cannot be ordered, because a dependency is not registered, because
+// they are part of a cycle, or because they depend on a test that cannot be ordered, are returned with the reason.
+func dependencyOrder(monitorTests map[string]*monitorTesttItem) ([]string, map[string]error) {
+	unorderable := map[string]error{}
+	for _, name := range sets.StringKeySet(monitorTests).List() {
+		missing := []string{}
+		for _, dependency := range monitorTests[name].dependencies {
+			if _, ok := monitorTests[dependency]; !ok {
+				missing = append(missing, dependency)
+			}
+		}
+		if len(missing) > 0 {
+			unorderable[name] = fmt.Errorf("%q depends on monitor tests that are not registered: %s", name, strings.Join(missing, ", "))
+		}
+	}
+
+	remainingDependencies := map[string]sets.String{}
+	dependents := map[string][]string{}
+	for name, monitorTest := range monitorTests {
+		remainingDependencies[name] = sets.NewString(monitorTest.dependencies...)
+		for _, dependency := range monitorTest.dependencies {
+			dependents[dependency] = append(dependents[dependency], name)
+		}
+	}
+
+	ready := []string{}
+	for name, dependencies := range remainingDependencies {
+		if len(dependencies) == 0 {
+			ready = append(ready, name)
+		}
+	}
+
+	order := []string{}
+	for len(ready) > 0 {
+		sort.Strings(ready)
+		curr := ready[0]
+		ready = ready[1:]
+		order = append(order, curr)
+
+		for _, dependent := range dependents[curr] {
+			remainingDependencies[dependent].Delete(curr)
+			if len(remainingDependencies[dependent]) == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	// anything not yet ordered is either part of a cycle or waiting on something that is.
+	ordered := sets.NewString(order...)
+	for _, name := range sets.StringKeySet(monitorTests).Difference(ordered).List() {
+		if _, ok := unorderable[name]; ok {
+			continue
+		}
+		if cycle := findCycle(monitorTests, name); len(cycle) > 0 {
+			unorderable[name] = fmt.Errorf("%q is part of a dependency cycle: %s", name, strings.Join(cycle, " -> "))
+			continue
+		}
+		unorderable[name] = fmt.Errorf("%q depends on monitor tests that cannot be run: %s",
+			name, strings.Join(sets.NewString(monitorTests[name].dependencies...).Difference(ordered).List(), ", "))
+	}
+
+	return order, unorderable
+}
+
+// findCycle returns the path from start back to itself if start is part of a dependency cycle.
+func findCycle(monitorTests map[string]*monitorTesttItem, start string) []string {
+	visited := sets.NewString()
+	var visit func(curr string, path []string) []string
+	visit = func(curr string, path []string) []string {
+		monitorTest, ok := monitorTests[curr]
+		if !ok {
+			return nil
+		}
+		for _, dependency := range monitorTest.dependencies {
+			if dependency == start {
+				return append(append([]string{}, path...), dependency)
+			}
+			if visited.Has(dependency) {
+				continue
+			}
+			visited.Insert(dependency)
+			if cycle := visit(dependency, append(path, dependency)); len(cycle) > 0 {
+				return cycle
+			}
+		}
+		return nil
+	}
+	return visit(start, []string{start})
+}
+
+// transitiveDependencies returns every test that name depends on, directly or indirectly.
+func transitiveDependencies(monitorTests map[string]*monitorTesttItem, name string) sets.String {
+	ret := sets.NewString()
+	toVisit := []string{name}
+	for len(toVisit) > 0 {
+		curr := toVisit[0]
+		toVisit = toVisit[1:]
+		monitorTest, ok := monitorTests[curr]
+		if !ok {
+			continue
+		}
+		for _, dependency := range monitorTest.dependencies {
+			if ret.Has(dependency) {
+				continue
+			}
+			ret.Insert(dependency)
+			toVisit = append(toVisit, dependency)
+		}
+	}
+	return ret
+}
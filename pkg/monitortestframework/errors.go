@@ -1,6 +1,9 @@
 package monitortestframework
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // NotSupportedError represents an error when a monitor test is unsupported for the given environment.
 type NotSupportedError struct {
@@ -19,3 +22,16 @@ type FlakeError struct {
 func (e *FlakeError) Error() string {
 	return fmt.Sprintf("test flake with error: %v", e.Err)
 }
+
+// StuckPhaseError represents a monitor test that did not finish a phase within its timeout.
+type StuckPhaseError struct {
+	MonitorTest string
+	Phase       MonitorTestPhase
+	Timeout     time.Duration
+	// Goroutines is a dump of the goroutines started on behalf of the monitor test.
+	Goroutines string
+}
+
+func (e *StuckPhaseError) Error() string {
+	return fmt.Sprintf("monitor test %q was stuck in %s for longer than %v, goroutines of the monitor test:\n%s", e.MonitorTest, e.Phase, e.Timeout, e.Goroutines)
+}
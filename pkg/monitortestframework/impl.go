@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"reflect"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -19,7 +21,9 @@ import (
 )
 
 type monitorTestRegistry struct {
-	monitorTests map[string]*monitorTesttItem
+	monitorTests  map[string]*monitorTesttItem
+	phaseTimeouts map[phaseTimeoutKey]time.Duration
+	performance   *performanceTracker
 }
 
 type monitorTesttItem struct {
@@ -27,29 +31,41 @@ type monitorTesttItem struct {
 	jiraComponent string
 
 	monitorTest MonitorTest
+	// dependencies are the names of the monitor tests whose constructed intervals this test consumes.
+	dependencies []string
 }
 
 func NewMonitorTestRegistry() MonitorTestRegistry {
 	return &monitorTestRegistry{
-		monitorTests: map[string]*monitorTesttItem{},
+		monitorTests:  map[string]*monitorTesttItem{},
+		phaseTimeouts: map[phaseTimeoutKey]time.Duration{},
+		performance:   newPerformanceTracker(),
 	}
 }
 
-func (r *monitorTestRegistry) AddMonitorTest(name, jiraComponent string, monitorTest MonitorTest) error {
+func (r *monitorTestRegistry) AddMonitorTest(name, jiraComponent string, monitorTest MonitorTest, dependencies ...string) error {
 	if _, ok := r.monitorTests[name]; ok {
 		return fmt.Errorf("%q is already registered", name)
 	}
+	if len(phasesFor(monitorTest)) == 0 {
+		return fmt.Errorf("%q (%T) does not participate in any monitor test phase", name, monitorTest)
+	}
+	if err := checkPhaseMethods(monitorTest); err != nil {
+		return fmt.Errorf("%q (%T) %w", name, monitorTest, err)
+	}
 	r.monitorTests[name] = &monitorTesttItem{
 		name:          name,
 		jiraComponent: jiraComponent,
 		monitorTest:   monitorTest,
+		// a dependency named twice is still only waited on once.
+		dependencies: sets.NewString(dependencies...).List(),
 	}
 
 	return nil
 }
 
-func (r *monitorTestRegistry) AddMonitorTestOrDie(name, jiraComponent string, monitorTest MonitorTest) {
-	err := r.AddMonitorTest(name, jiraComponent, monitorTest)
+func (r *monitorTestRegistry) AddMonitorTestOrDie(name, jiraComponent string, monitorTest MonitorTest, dependencies ...string) {
+	err := r.AddMonitorTest(name, jiraComponent, monitorTest, dependencies...)
 	if err != nil {
 		panic(err)
 	}
@@ -66,6 +82,15 @@ func (r *monitorTestRegistry) GetRegistryFor(names ...string) (MonitorTestRegist
 			continue
 		}
 		ret.monitorTests[name] = monitorTestItem
+		// the tests a named test depends on come along with it, their constructed intervals are its input.
+		for _, dependency := range transitiveDependencies(r.monitorTests, name).List() {
+			if dependencyItem, ok := r.monitorTests[dependency]; ok {
+				ret.monitorTests[dependency] = dependencyItem
+			}
+		}
+	}
+	for key, timeout := range r.phaseTimeouts {
+		ret.phaseTimeouts[key] = timeout
 	}
 	if len(missingNames) > 0 {
 		return nil, fmt.Errorf("monitorTests named %v were missing", strings.Join(missingNames, ", "))
@@ -74,25 +99,130 @@ func (r *monitorTestRegistry) GetRegistryFor(names ...string) (MonitorTestRegist
 	return ret, nil
 }
 
+func (r *monitorTestRegistry) GetRegistryWithout(names ...string) (MonitorTestRegistry, error) {
+	removed := sets.NewString(names...)
+	remaining := sets.StringKeySet(r.monitorTests).Difference(removed)
+
+	// a removed test would be brought straight back by the tests that depend on it, so they must be removed as well.
+	neededBy := []string{}
+	for _, name := range remaining.List() {
+		if needed := transitiveDependencies(r.monitorTests, name).Intersection(removed); len(needed) > 0 {
+			neededBy = append(neededBy, fmt.Sprintf("%s (needs %s)", name, strings.Join(needed.List(), ", ")))
+		}
+	}
+	if len(neededBy) > 0 {
+		return nil, fmt.Errorf("monitorTests named %v cannot be removed, they are needed by %v", strings.Join(removed.List(), ", "), strings.Join(neededBy, ", "))
+	}
+
+	return r.GetRegistryFor(remaining.List()...)
+}
+
 func (r *monitorTestRegistry) ListMonitorTests() sets.String {
 	return sets.StringKeySet(r.monitorTests)
 }
 
+func (r *monitorTestRegistry) ListMonitorTestsRequiringCluster() sets.String {
+	ret := sets.NewString()
+	for name, monitorTest := range r.monitorTests {
+		if requiresCluster(monitorTest.monitorTest) {
+			ret.Insert(name)
+		}
+	}
+	return ret
+}
+
+func (r *monitorTestRegistry) ListOfflineMonitorTests() sets.String {
+	return r.ListMonitorTests().Difference(r.ListMonitorTestsRequiringCluster())
+}
+
+func (r *monitorTestRegistry) ListMonitorTestPhases() map[string][]MonitorTestPhase {
+	ret := map[string][]MonitorTestPhase{}
+	for name, monitorTest := range r.monitorTests {
+		ret[name] = phasesFor(monitorTest.monitorTest)
+	}
+	return ret
+}
+
+// phasesFor returns the phases monitorTest participates in, in execution order.
+func phasesFor(monitorTest MonitorTest) []MonitorTestPhase {
+	ret := []MonitorTestPhase{}
+	if _, ok := monitorTest.(ClusterCollector); ok {
+		ret = append(ret, StartCollectionPhase, CollectDataPhase)
+	}
+	if _, ok := monitorTest.(IntervalConstructor); ok {
+		ret = append(ret, ConstructComputedIntervalsPhase)
+	}
+	if _, ok := monitorTest.(IntervalEvaluator); ok {
+		ret = append(ret, EvaluateTestsFromConstructedIntervalsPhase)
+	}
+	if _, ok := monitorTest.(StorageWriter); ok {
+		ret = append(ret, WriteContentToStoragePhase)
+	}
+	if _, ok := monitorTest.(Cleaner); ok {
+		ret = append(ret, CleanupPhase)
+	}
+	return ret
+}
+
+// phaseMethods maps the methods of every phase to the interface that must be implemented to take part in that phase.
+var phaseMethods = []struct {
+	method        string
+	interfaceType reflect.Type
+}{
+	{"StartCollection", reflect.TypeOf((*ClusterCollector)(nil)).Elem()},
+	{"CollectData", reflect.TypeOf((*ClusterCollector)(nil)).Elem()},
+	{"ConstructComputedIntervals", reflect.TypeOf((*IntervalConstructor)(nil)).Elem()},
+	{"EvaluateTestsFromConstructedIntervals", reflect.TypeOf((*IntervalEvaluator)(nil)).Elem()},
+	{"WriteContentToStorage", reflect.TypeOf((*StorageWriter)(nil)).Elem()},
+	{"Cleanup", reflect.TypeOf((*Cleaner)(nil)).Elem()},
+}
+
+// checkPhaseMethods fails for a phase method that does not implement its phase interface, such as a method with the
+// wrong signature, because the MonitorTest would otherwise silently be left out of that phase.
+func checkPhaseMethods(monitorTest MonitorTest) error {
+	monitorTestType := reflect.TypeOf(monitorTest)
+	errs := []error{}
+	for _, phaseMethod := range phaseMethods {
+		if _, ok := monitorTestType.MethodByName(phaseMethod.method); !ok || monitorTestType.Implements(phaseMethod.interfaceType) {
+			continue
+		}
+		errs = append(errs, fmt.Errorf("has a %s method but does not implement %s", phaseMethod.method, phaseMethod.interfaceType.Name()))
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func requiresCluster(monitorTest MonitorTest) bool {
+	if _, ok := monitorTest.(ClusterCollector); ok {
+		return true
+	}
+	if _, ok := monitorTest.(Cleaner); ok {
+		return true
+	}
+	return false
+}
+
 func (r *monitorTestRegistry) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) ([]*junitapi.JUnitTestCase, error) {
+	defer r.performance.endPhase(r.performance.beginPhase(StartCollectionPhase))
+
 	wg := sync.WaitGroup{}
 	junitCh := make(chan *junitapi.JUnitTestCase, 2*len(r.monitorTests))
 	errCh := make(chan error, len(r.monitorTests))
 
 	for i := range r.monitorTests {
+		collector, ok := r.monitorTests[i].monitorTest.(ClusterCollector)
+		if !ok {
+			continue
+		}
+
 		wg.Add(1)
-		go func(ctx context.Context, invariant *monitorTesttItem) {
+		go func(ctx context.Context, invariant *monitorTesttItem, collector ClusterCollector) {
 			defer wg.Done()
 
 			testName := fmt.Sprintf("[Jira:%q] monitor test %v setup", invariant.jiraComponent, invariant.name)
 			logrus.Infof("  Starting %v for %v", invariant.name, invariant.jiraComponent)
 
 			start := time.Now()
-			err := startCollectionWithPanicProtection(ctx, invariant.monitorTest, adminRESTConfig, recorder)
+			err := r.startCollection(ctx, invariant, collector, adminRESTConfig, recorder)
 			end := time.Now()
 			duration := end.Sub(start)
 			if err != nil {
@@ -126,7 +256,7 @@ func (r *monitorTestRegistry) StartCollection(ctx context.Context, adminRESTConf
 				Name:     testName,
 				Duration: duration.Seconds(),
 			}
-		}(ctx, r.monitorTests[i])
+		}(ctx, r.monitorTests[i], collector)
 
 	}
 
@@ -147,6 +277,8 @@ func (r *monitorTestRegistry) StartCollection(ctx context.Context, adminRESTConf
 }
 
 func (r *monitorTestRegistry) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	defer r.performance.endPhase(r.performance.beginPhase(CollectDataPhase))
+
 	wg := sync.WaitGroup{}
 	intervalsCh := make(chan monitorapi.Intervals, len(r.monitorTests))
 	junitCh := make(chan []*junitapi.JUnitTestCase, 3*len(r.monitorTests))
@@ -154,14 +286,19 @@ func (r *monitorTestRegistry) CollectData(ctx context.Context, storageDir string
 
 	logrus.Infof("Starting CollectData for all monitor tests")
 	for i := range r.monitorTests {
+		collector, ok := r.monitorTests[i].monitorTest.(ClusterCollector)
+		if !ok {
+			continue
+		}
+
 		wg.Add(1)
-		go func(ctx context.Context, monitorTest *monitorTesttItem) {
+		go func(ctx context.Context, monitorTest *monitorTesttItem, collector ClusterCollector) {
 			defer wg.Done()
 			testName := fmt.Sprintf("[Jira:%q] monitor test %v collection", monitorTest.jiraComponent, monitorTest.name)
 
 			start := time.Now()
 			logrus.Infof("  Starting CollectData for %s", testName)
-			localIntervals, localJunits, err := collectDataWithPanicProtection(ctx, monitorTest.monitorTest, storageDir, beginning, end)
+			localIntervals, localJunits, err := r.collectData(ctx, monitorTest, collector, storageDir, beginning, end)
 			intervalsCh <- localIntervals
 			junitCh <- localJunits
 			end := time.Now()
@@ -205,7 +342,7 @@ func (r *monitorTestRegistry) CollectData(ctx context.Context, storageDir string
 				},
 			}
 			logrus.Infof("  Finished CollectData for %s", testName)
-		}(ctx, r.monitorTests[i])
+		}(ctx, r.monitorTests[i], collector)
 	}
 
 	wg.Wait()
@@ -230,65 +367,158 @@ func (r *monitorTestRegistry) CollectData(ctx context.Context, storageDir string
 	return intervals, junits, utilerrors.NewAggregate(errs)
 }
 
+// maxParallelIntervalConstructors bounds how many MonitorTests construct intervals at the same time, each of them may
+// hold a large share of the intervals in memory.
+const maxParallelIntervalConstructors = 4
+
 func (r *monitorTestRegistry) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	defer r.performance.endPhase(r.performance.beginPhase(ConstructComputedIntervalsPhase))
+
 	intervals := monitorapi.Intervals{}
 	junits := []*junitapi.JUnitTestCase{}
 	errs := []error{}
 
-	for _, monitorTest := range r.monitorTests {
+	order, unorderable := dependencyOrder(r.monitorTests)
+	for _, name := range sets.StringKeySet(unorderable).List() {
+		monitorTest := r.monitorTests[name]
 		testName := fmt.Sprintf("[Jira:%q] monitor test %v interval construction", monitorTest.jiraComponent, monitorTest.name)
+		err := unorderable[name]
 
-		start := time.Now()
-		localIntervals, err := constructComputedIntervalsWithPanicProtection(ctx, monitorTest.monitorTest, startingIntervals, recordedResources, beginning, end)
-		intervals = append(intervals, localIntervals...)
-		end := time.Now()
-		duration := end.Sub(start)
-		if err != nil {
-			var nsErr *NotSupportedError
-			if errors.As(err, &nsErr) {
-				junits = append(junits, &junitapi.JUnitTestCase{
+		errs = append(errs, err)
+		junits = append(junits, &junitapi.JUnitTestCase{
+			Name: testName,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: fmt.Sprintf("failed to resolve monitor test dependencies\n%v", err),
+			},
+			SystemOut: fmt.Sprintf("failed to resolve monitor test dependencies\n%v", err),
+		})
+	}
+
+	// every test waits for the tests it depends on and then starts, so independent tests run in parallel, at most
+	// maxParallelIntervalConstructors at a time.
+	results := map[string]*constructionResult{}
+	for _, name := range order {
+		results[name] = &constructionResult{done: make(chan struct{})}
+	}
+	running := make(chan struct{}, maxParallelIntervalConstructors)
+	wg := sync.WaitGroup{}
+	for _, name := range order {
+		constructor, ok := r.monitorTests[name].monitorTest.(IntervalConstructor)
+		if !ok {
+			close(results[name].done)
+			continue
+		}
+
+		wg.Add(1)
+		go func(ctx context.Context, monitorTest *monitorTesttItem, constructor IntervalConstructor, result *constructionResult) {
+			defer wg.Done()
+			defer close(result.done)
+
+			// every test gets its own copy of the starting intervals, tests running in parallel may sort theirs in place.
+			// a test with dependencies also gets the intervals constructed by its dependencies.
+			dependencies := transitiveDependencies(r.monitorTests, monitorTest.name)
+			dependencyIntervals := monitorapi.Intervals{}
+			for _, dependency := range dependencies.List() {
+				<-results[dependency].done
+				dependencyIntervals = append(dependencyIntervals, results[dependency].intervals...)
+			}
+			localStartingIntervals := make(monitorapi.Intervals, 0, len(startingIntervals)+len(dependencyIntervals))
+			localStartingIntervals = append(localStartingIntervals, startingIntervals...)
+			if len(dependencyIntervals) > 0 {
+				localStartingIntervals = append(localStartingIntervals, dependencyIntervals...)
+				sort.Sort(localStartingIntervals)
+			}
+
+			// a slot is only held while constructing, never while waiting on a dependency.
+			running <- struct{}{}
+			defer func() { <-running }()
+			result.intervals, result.junits, result.err = r.constructComputedIntervalsFor(ctx, monitorTest, constructor, localStartingIntervals, recordedResources, beginning, end)
+		}(ctx, r.monitorTests[name], constructor, results[name])
+	}
+	wg.Wait()
+
+	for _, name := range order {
+		intervals = append(intervals, results[name].intervals...)
+		junits = append(junits, results[name].junits...)
+		if results[name].err != nil {
+			errs = append(errs, results[name].err)
+		}
+	}
+
+	return intervals, junits, utilerrors.NewAggregate(errs)
+}
+
+type constructionResult struct {
+	done chan struct{}
+
+	intervals monitorapi.Intervals
+	junits    []*junitapi.JUnitTestCase
+	err       error
+}
+
+func (r *monitorTestRegistry) constructComputedIntervalsFor(ctx context.Context, monitorTest *monitorTesttItem, constructor IntervalConstructor, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	testName := fmt.Sprintf("[Jira:%q] monitor test %v interval construction", monitorTest.jiraComponent, monitorTest.name)
+
+	start := time.Now()
+	intervals, err := r.constructComputedIntervals(ctx, monitorTest, constructor, startingIntervals, recordedResources, beginning, end)
+	duration := time.Now().Sub(start)
+	if err != nil {
+		var nsErr *NotSupportedError
+		if errors.As(err, &nsErr) {
+			return intervals, []*junitapi.JUnitTestCase{
+				{
 					Name:     testName,
 					Duration: duration.Seconds(),
 					SkipMessage: &junitapi.SkipMessage{
 						Message: nsErr.Reason,
 					},
-				})
-				continue
-			}
+				},
+			}, nil
+		}
 
-			errs = append(errs, err)
-			junits = append(junits, &junitapi.JUnitTestCase{
+		junits := []*junitapi.JUnitTestCase{
+			{
 				Name:     testName,
 				Duration: duration.Seconds(),
 				FailureOutput: &junitapi.FailureOutput{
 					Output: fmt.Sprintf("failed during interval construction\n%v", err),
 				},
 				SystemOut: fmt.Sprintf("failed during interval construction\n%v", err),
-			})
-			var flakeErr *FlakeError
-			if !errors.As(err, &flakeErr) {
-				continue
-			}
+			},
 		}
-
-		junits = append(junits, &junitapi.JUnitTestCase{
+		var flakeErr *FlakeError
+		if !errors.As(err, &flakeErr) {
+			return intervals, junits, err
+		}
+		return intervals, append(junits, &junitapi.JUnitTestCase{
 			Name:     testName,
 			Duration: duration.Seconds(),
-		})
+		}), err
 	}
 
-	return intervals, junits, utilerrors.NewAggregate(errs)
+	return intervals, []*junitapi.JUnitTestCase{
+		{
+			Name:     testName,
+			Duration: duration.Seconds(),
+		},
+	}, nil
 }
 
 func (r *monitorTestRegistry) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	defer r.performance.endPhase(r.performance.beginPhase(EvaluateTestsFromConstructedIntervalsPhase))
+
 	junits := []*junitapi.JUnitTestCase{}
 	errs := []error{}
 
 	for _, monitorTest := range r.monitorTests {
+		evaluator, ok := monitorTest.monitorTest.(IntervalEvaluator)
+		if !ok {
+			continue
+		}
 		testName := fmt.Sprintf("[Jira:%q] monitor test %v test evaluation", monitorTest.jiraComponent, monitorTest.name)
 
 		start := time.Now()
-		localJunits, err := evaluateTestsFromConstructedIntervalsWithPanicProtection(ctx, monitorTest.monitorTest, finalIntervals)
+		localJunits, err := r.evaluateTestsFromConstructedIntervals(ctx, monitorTest, evaluator, finalIntervals)
 		junits = append(junits, localJunits...)
 		end := time.Now()
 		duration := end.Sub(start)
@@ -330,10 +560,15 @@ func (r *monitorTestRegistry) EvaluateTestsFromConstructedIntervals(ctx context.
 }
 
 func (r *monitorTestRegistry) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) ([]*junitapi.JUnitTestCase, error) {
+	phaseMeasurement := r.performance.beginPhase(WriteContentToStoragePhase)
 	junits := []*junitapi.JUnitTestCase{}
 	errs := []error{}
 
 	for _, monitorTest := range r.monitorTests {
+		writer, ok := monitorTest.monitorTest.(StorageWriter)
+		if !ok {
+			continue
+		}
 		testName := fmt.Sprintf("[Jira:%q] monitor test %v writing to storage", monitorTest.jiraComponent, monitorTest.name)
 
 		start := time.Now()
@@ -346,7 +581,7 @@ func (r *monitorTestRegistry) WriteContentToStorage(ctx context.Context, storage
 			fmt.Fprintf(os.Stderr, "  last interval time: From = %s; To = %s\n", finalIntervals[finalIntervalLength-1].From, finalIntervals[finalIntervalLength-1].To)
 		}
 
-		err := writeContentToStorageWithPanicProtection(ctx, monitorTest.monitorTest, storageDir, timeSuffix, finalIntervals, finalResourceState)
+		err := r.writeContentToStorage(ctx, monitorTest, writer, storageDir, timeSuffix, finalIntervals, finalResourceState)
 		end := time.Now()
 		duration := end.Sub(start)
 		if err != nil {
@@ -383,20 +618,32 @@ func (r *monitorTestRegistry) WriteContentToStorage(ctx context.Context, storage
 		})
 	}
 
+	r.performance.endPhase(phaseMeasurement)
+	// the performance of the monitor tests is informational, losing it must not fail the run.
+	if err := r.performance.write(storageDir, timeSuffix); err != nil {
+		logrus.WithError(err).Warn("unable to write monitor test performance")
+	}
+
 	return junits, utilerrors.NewAggregate(errs)
 }
 
 func (r *monitorTestRegistry) Cleanup(ctx context.Context) ([]*junitapi.JUnitTestCase, error) {
+	defer r.performance.endPhase(r.performance.beginPhase(CleanupPhase))
+
 	junits := []*junitapi.JUnitTestCase{}
 	errs := []error{}
 
 	for _, monitorTest := range r.monitorTests {
+		cleaner, ok := monitorTest.monitorTest.(Cleaner)
+		if !ok {
+			continue
+		}
 		testName := fmt.Sprintf("[Jira:%q] monitor test %v cleanup", monitorTest.jiraComponent, monitorTest.name)
 		log := logrus.WithField("monitorTest", monitorTest.name)
 
 		start := time.Now()
 		log.Info("beginning cleanup")
-		err := cleanupWithPanicProtection(ctx, monitorTest.monitorTest)
+		err := r.cleanup(ctx, monitorTest, cleaner)
 		end := time.Now()
 		duration := end.Sub(start)
 		if err != nil {
@@ -439,7 +686,7 @@ func (r *monitorTestRegistry) Cleanup(ctx context.Context) ([]*junitapi.JUnitTes
 
 func (r *monitorTestRegistry) AddRegistryOrDie(registry MonitorTestRegistry) {
 	for _, v := range registry.getMonitorTests() {
-		r.AddMonitorTestOrDie(v.name, v.jiraComponent, v.monitorTest)
+		r.AddMonitorTestOrDie(v.name, v.jiraComponent, v.monitorTest, v.dependencies...)
 	}
 }
 
@@ -0,0 +1,339 @@
+package monitortestframework
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime/pprof"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/dataloader"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	"k8s.io/client-go/rest"
+)
+
+type fakeCollector struct {
+	started bool
+}
+
+func (f *fakeCollector) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	f.started = true
+	return nil
+}
+
+func (f *fakeCollector) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	return nil, nil, nil
+}
+
+func (f *fakeCollector) Cleanup(ctx context.Context) error {
+	return nil
+}
+
+type fakeEvaluator struct{}
+
+func (fakeEvaluator) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	return []*junitapi.JUnitTestCase{{Name: "evaluated"}}, nil
+}
+
+// fakeMismatchedEvaluator cleans up, but its EvaluateTestsFromConstructedIntervals
+// does not match IntervalEvaluator so it would never be evaluated.
+type fakeMismatchedEvaluator struct{}
+
+func (*fakeMismatchedEvaluator) Cleanup(ctx context.Context) error {
+	return nil
+}
+
+func (*fakeMismatchedEvaluator) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) []*junitapi.JUnitTestCase {
+	return nil
+}
+
+func TestRegistryCapabilities(t *testing.T) {
+	collector := &fakeCollector{}
+	registry := NewMonitorTestRegistry()
+	registry.AddMonitorTestOrDie("collector", "Test Framework", collector)
+	registry.AddMonitorTestOrDie("evaluator", "Test Framework", fakeEvaluator{})
+
+	if err := registry.AddMonitorTest("nothing", "Test Framework", struct{}{}); err == nil {
+		t.Errorf("expected a monitor test without phases to be rejected")
+	}
+	if err := registry.AddMonitorTest("mismatched", "Test Framework", &fakeMismatchedEvaluator{}); err == nil {
+		t.Errorf("expected a monitor test with a phase method of the wrong signature to be rejected")
+	}
+
+	if actual := registry.ListMonitorTestsRequiringCluster().List(); !reflect.DeepEqual(actual, []string{"collector"}) {
+		t.Errorf("unexpected tests requiring a cluster: %v", actual)
+	}
+	if actual := registry.ListOfflineMonitorTests().List(); !reflect.DeepEqual(actual, []string{"evaluator"}) {
+		t.Errorf("unexpected offline tests: %v", actual)
+	}
+
+	expectedPhases := map[string][]MonitorTestPhase{
+		"collector": {StartCollectionPhase, CollectDataPhase, CleanupPhase},
+		"evaluator": {EvaluateTestsFromConstructedIntervalsPhase},
+	}
+	if actual := registry.ListMonitorTestPhases(); !reflect.DeepEqual(actual, expectedPhases) {
+		t.Errorf("unexpected phases: %v", actual)
+	}
+
+	junits, err := registry.StartCollection(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !collector.started || len(junits) != 1 {
+		t.Errorf("expected only the collector to start, got %d junits", len(junits))
+	}
+
+	junits, err = registry.EvaluateTestsFromConstructedIntervals(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(junits) != 2 || junits[0].Name != "evaluated" {
+		t.Errorf("expected the evaluator junit and its pass, got %v", junits)
+	}
+}
+
+type fakeConstructor struct {
+	source monitorapi.IntervalSource
+	seen   monitorapi.Intervals
+}
+
+func (f *fakeConstructor) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	f.seen = startingIntervals
+	return monitorapi.Intervals{
+		{Source: f.source, From: beginning, To: end},
+	}, nil
+}
+
+func TestRegistryConstructionDependencies(t *testing.T) {
+	pods := &fakeConstructor{source: monitorapi.SourcePodState}
+	nodes := &fakeConstructor{source: monitorapi.SourceNodeState}
+	consumer := &fakeConstructor{source: monitorapi.SourceTestData}
+	cycleA := &fakeConstructor{source: monitorapi.SourceTestData}
+	cycleB := &fakeConstructor{source: monitorapi.SourceTestData}
+	orphan := &fakeConstructor{source: monitorapi.SourceTestData}
+
+	registry := NewMonitorTestRegistry()
+	// register the consumer first to show that dependencies may be registered in any order.
+	registry.AddMonitorTestOrDie("consumer", "Test Framework", consumer, "pods")
+	registry.AddMonitorTestOrDie("pods", "Test Framework", pods)
+	registry.AddMonitorTestOrDie("nodes", "Test Framework", nodes)
+	registry.AddMonitorTestOrDie("cycle-a", "Test Framework", cycleA, "cycle-b")
+	registry.AddMonitorTestOrDie("cycle-b", "Test Framework", cycleB, "cycle-a")
+	registry.AddMonitorTestOrDie("orphan", "Test Framework", orphan, "missing")
+
+	start := time.Unix(1, 0)
+	end := time.Unix(2, 0)
+	raw := monitorapi.Intervals{{Source: monitorapi.SourceE2ETest, From: start, To: end}}
+	intervals, junits, err := registry.ConstructComputedIntervals(context.Background(), raw, nil, start, end)
+	if err == nil {
+		t.Fatalf("expected the cycle and missing dependency to be reported")
+	}
+
+	if len(intervals) != 3 {
+		t.Errorf("expected one interval from each runnable test, got %v", intervals)
+	}
+	if len(consumer.seen) != 2 || len(consumer.seen.Filter(func(interval monitorapi.Interval) bool {
+		return interval.Source == monitorapi.SourcePodState
+	})) != 1 {
+		t.Errorf("expected the consumer to see the raw and the pod intervals, got %v", consumer.seen)
+	}
+	if len(nodes.seen) != 1 || nodes.seen[0].Source != monitorapi.SourceE2ETest || &nodes.seen[0] == &raw[0] {
+		t.Errorf("expected independent tests to be passed their own copy of the raw intervals, got %v", nodes.seen)
+	}
+	if cycleA.seen != nil || cycleB.seen != nil || orphan.seen != nil {
+		t.Errorf("expected tests with unresolvable dependencies not to run")
+	}
+
+	failed := []string{}
+	for _, junit := range junits {
+		if junit.FailureOutput != nil {
+			failed = append(failed, junit.Name)
+		}
+	}
+	expectedFailed := []string{
+		`[Jira:"Test Framework"] monitor test cycle-a interval construction`,
+		`[Jira:"Test Framework"] monitor test cycle-b interval construction`,
+		`[Jira:"Test Framework"] monitor test orphan interval construction`,
+	}
+	if !reflect.DeepEqual(failed, expectedFailed) {
+		t.Errorf("unexpected failures: %v", failed)
+	}
+
+	subset, err := registry.GetRegistryFor("consumer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := subset.ListMonitorTests().List(); !reflect.DeepEqual(actual, []string{"consumer", "pods"}) {
+		t.Errorf("expected the dependencies to come along with the consumer, got %v", actual)
+	}
+
+	if _, err := registry.GetRegistryWithout("pods"); err == nil || !strings.Contains(err.Error(), "consumer (needs pods)") {
+		t.Errorf("expected removing a dependency to name its dependents, got %v", err)
+	}
+	subset, err = registry.GetRegistryWithout("consumer", "pods", "cycle-a", "cycle-b", "orphan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := subset.ListMonitorTests().List(); !reflect.DeepEqual(actual, []string{"nodes"}) {
+		t.Errorf("expected only the remaining tests, got %v", actual)
+	}
+}
+
+func TestRegistryDuplicateDependencies(t *testing.T) {
+	pods := &fakeConstructor{source: monitorapi.SourcePodState}
+	consumer := &fakeConstructor{source: monitorapi.SourceTestData}
+
+	registry := NewMonitorTestRegistry()
+	registry.AddMonitorTestOrDie("pods", "Test Framework", pods)
+	registry.AddMonitorTestOrDie("consumer", "Test Framework", consumer, "pods", "pods")
+
+	intervals, junits, err := registry.ConstructComputedIntervals(context.Background(), nil, nil, time.Unix(1, 0), time.Unix(2, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(intervals) != 2 || len(junits) != 2 {
+		t.Errorf("expected the consumer to be constructed once, got %d intervals and %d junits", len(intervals), len(junits))
+	}
+	if len(consumer.seen) != 1 {
+		t.Errorf("expected the consumer to see the pod intervals once, got %v", consumer.seen)
+	}
+}
+
+type hangingCollector struct {
+	fakeCollector
+	released chan struct{}
+}
+
+func (f *hangingCollector) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	<-ctx.Done()
+	close(f.released)
+	return monitorapi.Intervals{{Source: monitorapi.SourceTestData}}, nil, ctx.Err()
+}
+
+func TestRegistryPhaseTimeouts(t *testing.T) {
+	hanging := &hangingCollector{released: make(chan struct{})}
+	registry := NewMonitorTestRegistry()
+	registry.AddMonitorTestOrDie("hanging", "Test Framework", hanging)
+	registry.AddMonitorTestOrDie("healthy", "Test Framework", &fakeCollector{})
+
+	if err := registry.SetPhaseTimeouts(PhaseTimeout{Phase: "Bogus", Timeout: time.Second}); err == nil {
+		t.Errorf("expected an unknown phase to be rejected")
+	}
+	timeouts, err := ParsePhaseTimeouts([]string{"CollectData=1h", "hanging/CollectData=100ms"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.SetPhaseTimeouts(timeouts...); err != nil {
+		t.Fatal(err)
+	}
+
+	intervals, junits, err := registry.CollectData(context.Background(), "", time.Unix(1, 0), time.Unix(2, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-hanging.released:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("expected the stuck monitor test's context to be cancelled")
+	}
+	if len(intervals) != 0 {
+		t.Errorf("expected no intervals from the stuck monitor test, got %v", intervals)
+	}
+
+	results := map[string]bool{}
+	for _, junit := range junits {
+		results[junit.Name] = junit.FailureOutput == nil
+		if junit.FailureOutput != nil && !strings.Contains(junit.FailureOutput.Output, "CollectData") {
+			t.Errorf("expected the failure to name the stuck phase, got %q", junit.FailureOutput.Output)
+		}
+	}
+	expected := map[string]bool{
+		`[Jira:"Test Framework"] monitor test hanging collection`: false,
+		`[Jira:"Test Framework"] monitor test healthy collection`: true,
+	}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("unexpected results: %v", results)
+	}
+}
+
+type fakeWriter struct{}
+
+func (fakeWriter) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return nil
+}
+
+func TestRegistryPerformance(t *testing.T) {
+	registry := NewMonitorTestRegistry()
+	registry.AddMonitorTestOrDie("pods", "Test Framework", &fakeConstructor{source: monitorapi.SourcePodState})
+	registry.AddMonitorTestOrDie("writer", "Test Framework", fakeWriter{})
+
+	start := time.Unix(1, 0)
+	end := time.Unix(2, 0)
+	if _, _, err := registry.ConstructComputedIntervals(context.Background(), nil, nil, start, end); err != nil {
+		t.Fatal(err)
+	}
+	storageDir := t.TempDir()
+	if _, err := registry.WriteContentToStorage(context.Background(), storageDir, "_suffix", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(storageDir, "monitor-test-performance_suffix-"+dataloader.AutoDataLoaderSuffix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dataFile := dataloader.DataFile{}
+	if err := json.Unmarshal(content, &dataFile); err != nil {
+		t.Fatal(err)
+	}
+	actual := []string{}
+	for _, row := range dataFile.Rows {
+		_, allocated := row["AllocatedBytes"]
+		actual = append(actual, fmt.Sprintf("%q %s %s %v", row["MonitorTest"], row["Phase"], row["Intervals"], allocated))
+	}
+	// the heap is shared by the monitor tests constructing intervals in parallel, it is only reported for the process.
+	expected := []string{
+		`"pods" ConstructComputedIntervals 1 false`,
+		`"writer" WriteContentToStorage 0 true`,
+		`"" ConstructComputedIntervals  true`,
+		`"" WriteContentToStorage  true`,
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected performance rows: %v", actual)
+	}
+	if dataFile.Schema["AllocatedBytes"] != dataloader.DataTypeInteger {
+		t.Errorf("expected an integer allocation column, got %v", dataFile.Schema)
+	}
+
+	if _, err := os.Stat(filepath.Join(storageDir, "monitor-test-performance_suffix.txt")); err != nil {
+		t.Errorf("expected the performance table to be written: %v", err)
+	}
+}
+
+func TestGoroutinesByMonitorTest(t *testing.T) {
+	stop := make(chan struct{})
+	started := sync.WaitGroup{}
+	for i := 0; i < 2; i++ {
+		started.Add(1)
+		go pprof.Do(context.Background(), pprof.Labels("monitortest", "busy", "phase", string(StartCollectionPhase)), func(context.Context) {
+			started.Done()
+			<-stop
+		})
+	}
+	started.Wait()
+	defer close(stop)
+
+	goroutines := goroutinesByMonitorTest()
+	if goroutines["busy"] != 2 {
+		t.Errorf("expected 2 goroutines for the busy monitor test, got %v", goroutines)
+	}
+	if count, _ := goroutinesFor("busy"); count != 2 {
+		t.Errorf("expected goroutinesFor to agree, got %d", count)
+	}
+}
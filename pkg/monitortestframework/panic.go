@@ -13,7 +13,7 @@ import (
 	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
 )
 
-func startCollectionWithPanicProtection(ctx context.Context, monitortest MonitorTest, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) (err error) {
+func startCollectionWithPanicProtection(ctx context.Context, monitortest ClusterCollector, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			err = fmt.Errorf("caught panic: %v", r)
@@ -26,7 +26,7 @@ func startCollectionWithPanicProtection(ctx context.Context, monitortest Monitor
 	return
 }
 
-func collectDataWithPanicProtection(ctx context.Context, monitortest MonitorTest, storageDir string, beginning, end time.Time) (intervals monitorapi.Intervals, junit []*junitapi.JUnitTestCase, err error) {
+func collectDataWithPanicProtection(ctx context.Context, monitortest ClusterCollector, storageDir string, beginning, end time.Time) (intervals monitorapi.Intervals, junit []*junitapi.JUnitTestCase, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			err = fmt.Errorf("caught panic: %v", r)
@@ -39,7 +39,7 @@ func collectDataWithPanicProtection(ctx context.Context, monitortest MonitorTest
 	return
 }
 
-func constructComputedIntervalsWithPanicProtection(ctx context.Context, monitortest MonitorTest, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (intervals monitorapi.Intervals, err error) {
+func constructComputedIntervalsWithPanicProtection(ctx context.Context, monitortest IntervalConstructor, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (intervals monitorapi.Intervals, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			err = fmt.Errorf("caught panic: %v", r)
@@ -52,7 +52,7 @@ func constructComputedIntervalsWithPanicProtection(ctx context.Context, monitort
 	return
 }
 
-func evaluateTestsFromConstructedIntervalsWithPanicProtection(ctx context.Context, monitortest MonitorTest, finalIntervals monitorapi.Intervals) (junits []*junitapi.JUnitTestCase, err error) {
+func evaluateTestsFromConstructedIntervalsWithPanicProtection(ctx context.Context, monitortest IntervalEvaluator, finalIntervals monitorapi.Intervals) (junits []*junitapi.JUnitTestCase, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			err = fmt.Errorf("caught panic: %v", r)
@@ -65,7 +65,7 @@ func evaluateTestsFromConstructedIntervalsWithPanicProtection(ctx context.Contex
 	return
 }
 
-func writeContentToStorageWithPanicProtection(ctx context.Context, monitortest MonitorTest, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) (err error) {
+func writeContentToStorageWithPanicProtection(ctx context.Context, monitortest StorageWriter, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			err = fmt.Errorf("caught panic: %v", r)
@@ -78,7 +78,7 @@ func writeContentToStorageWithPanicProtection(ctx context.Context, monitortest M
 	return
 }
 
-func cleanupWithPanicProtection(ctx context.Context, monitortest MonitorTest) (err error) {
+func cleanupWithPanicProtection(ctx context.Context, monitortest Cleaner) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			err = fmt.Errorf("caught panic: %v", r)
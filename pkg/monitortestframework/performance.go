@@ -0,0 +1,313 @@
+package monitortestframework
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/metrics"
+	"sort"
+	"strconv"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/openshift/origin/pkg/dataloader"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+const allocatedBytesMetric = "/gc/heap/allocs:bytes"
+
+// phasePerformance is the cost of running one MonitorTest through one phase.
+type phasePerformance struct {
+	MonitorTest   string
+	JiraComponent string
+	Phase         MonitorTestPhase
+	Duration      time.Duration
+	// Goroutines is the number of goroutines the monitor test left running when the phase finished for every monitor
+	// test.  For StartCollection these are the goroutines collecting for the rest of the run.
+	Goroutines int
+	// Intervals is the number of intervals the monitor test contributed in the phase.
+	Intervals int
+	// AllocatedBytes is the heap allocated while the monitor test ran the phase.  It is only measured for the phases
+	// that run one monitor test at a time, see measuresAllocations.
+	AllocatedBytes      uint64
+	allocationsMeasured bool
+}
+
+// registryPhasePerformance is the cost of running every MonitorTest through one phase.
+type registryPhasePerformance struct {
+	Phase    MonitorTestPhase
+	Duration time.Duration
+	// AllocatedBytes is the heap allocated by the whole process while the phase ran.
+	AllocatedBytes uint64
+}
+
+type phaseMeasurement struct {
+	key            phaseTimeoutKey
+	jiraComponent  string
+	start          time.Time
+	allocatedBytes uint64
+}
+
+type registryPhaseMeasurement struct {
+	phase          MonitorTestPhase
+	start          time.Time
+	allocatedBytes uint64
+}
+
+// performanceTracker records the phasePerformance of every MonitorTest in a registry.
+type performanceTracker struct {
+	lock          sync.Mutex
+	performance   map[phaseTimeoutKey]*phasePerformance
+	registryPhase map[MonitorTestPhase]*registryPhasePerformance
+}
+
+func newPerformanceTracker() *performanceTracker {
+	return &performanceTracker{
+		performance:   map[phaseTimeoutKey]*phasePerformance{},
+		registryPhase: map[MonitorTestPhase]*registryPhasePerformance{},
+	}
+}
+
+// measuresAllocations is true for the phases in which the monitor tests run one at a time, so the heap allocated while
+// one of them runs can be attributed to it.  The other phases run the monitor tests in parallel.
+func measuresAllocations(phase MonitorTestPhase) bool {
+	switch phase {
+	case EvaluateTestsFromConstructedIntervalsPhase, WriteContentToStoragePhase, CleanupPhase:
+		return true
+	}
+	return false
+}
+
+func (t *performanceTracker) begin(monitorTest *monitorTesttItem, phase MonitorTestPhase) phaseMeasurement {
+	measurement := phaseMeasurement{
+		key:           phaseTimeoutKey{monitorTest: monitorTest.name, phase: phase},
+		jiraComponent: monitorTest.jiraComponent,
+		start:         time.Now(),
+	}
+	if measuresAllocations(phase) {
+		measurement.allocatedBytes = allocatedBytes()
+	}
+	return measurement
+}
+
+func (t *performanceTracker) end(measurement phaseMeasurement) {
+	duration := time.Since(measurement.start)
+	measureAllocations := measuresAllocations(measurement.key.phase)
+	var allocated uint64
+	if measureAllocations {
+		allocated = allocatedBytes() - measurement.allocatedBytes
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	performance := t.getLocked(measurement.key)
+	performance.JiraComponent = measurement.jiraComponent
+	performance.Duration = duration
+	if measureAllocations {
+		performance.AllocatedBytes = allocated
+		performance.allocationsMeasured = true
+	}
+}
+
+// beginPhase is called before any MonitorTest runs the phase.
+func (t *performanceTracker) beginPhase(phase MonitorTestPhase) registryPhaseMeasurement {
+	return registryPhaseMeasurement{
+		phase:          phase,
+		start:          time.Now(),
+		allocatedBytes: allocatedBytes(),
+	}
+}
+
+// endPhase is called once every MonitorTest has run the phase.  A single goroutine dump is taken for all of them, so
+// the cost does not grow with the number of monitor tests.
+func (t *performanceTracker) endPhase(measurement registryPhaseMeasurement) {
+	duration := time.Since(measurement.start)
+	allocated := allocatedBytes() - measurement.allocatedBytes
+	goroutines := goroutinesByMonitorTest()
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.registryPhase[measurement.phase] = &registryPhasePerformance{
+		Phase:          measurement.phase,
+		Duration:       duration,
+		AllocatedBytes: allocated,
+	}
+	for key, performance := range t.performance {
+		if key.phase == measurement.phase {
+			performance.Goroutines = goroutines[key.monitorTest]
+		}
+	}
+}
+
+func (t *performanceTracker) addIntervals(monitorTest *monitorTesttItem, phase MonitorTestPhase, count int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.getLocked(phaseTimeoutKey{monitorTest: monitorTest.name, phase: phase}).Intervals += count
+}
+
+func (t *performanceTracker) getLocked(key phaseTimeoutKey) *phasePerformance {
+	performance, ok := t.performance[key]
+	if !ok {
+		performance = &phasePerformance{MonitorTest: key.monitorTest, Phase: key.phase}
+		t.performance[key] = performance
+	}
+	return performance
+}
+
+// list returns the performance ordered by monitor test, then by phase in the order the phases run.
+func (t *performanceTracker) list() []phasePerformance {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	phaseOrder := map[MonitorTestPhase]int{}
+	for i, phase := range allPhases {
+		phaseOrder[phase] = i
+	}
+	ret := []phasePerformance{}
+	for _, performance := range t.performance {
+		ret = append(ret, *performance)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].MonitorTest != ret[j].MonitorTest {
+			return ret[i].MonitorTest < ret[j].MonitorTest
+		}
+		return phaseOrder[ret[i].Phase] < phaseOrder[ret[j].Phase]
+	})
+	return ret
+}
+
+// listRegistryPhases returns the performance of the registry in the order the phases run.
+func (t *performanceTracker) listRegistryPhases() []registryPhasePerformance {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	ret := []registryPhasePerformance{}
+	for _, phase := range allPhases {
+		if performance, ok := t.registryPhase[phase]; ok {
+			ret = append(ret, *performance)
+		}
+	}
+	return ret
+}
+
+// write stores the performance as a data file for ci-data-loader and as a table for humans.  The data file has a row
+// per monitor test per phase, followed by a row per phase without a MonitorTest for the whole process.
+func (t *performanceTracker) write(storageDir, timeSuffix string) error {
+	performance := t.list()
+	registryPhases := t.listRegistryPhases()
+
+	rows := []map[string]string{}
+	for _, curr := range performance {
+		row := map[string]string{
+			"MonitorTest":     curr.MonitorTest,
+			"JiraComponent":   curr.JiraComponent,
+			"Phase":           string(curr.Phase),
+			"DurationSeconds": strconv.FormatFloat(curr.Duration.Seconds(), 'f', -1, 64),
+			"Goroutines":      strconv.Itoa(curr.Goroutines),
+			"Intervals":       strconv.Itoa(curr.Intervals),
+		}
+		if curr.allocationsMeasured {
+			row["AllocatedBytes"] = strconv.FormatUint(curr.AllocatedBytes, 10)
+		}
+		rows = append(rows, row)
+	}
+	for _, curr := range registryPhases {
+		rows = append(rows, map[string]string{
+			"Phase":           string(curr.Phase),
+			"DurationSeconds": strconv.FormatFloat(curr.Duration.Seconds(), 'f', -1, 64),
+			"AllocatedBytes":  strconv.FormatUint(curr.AllocatedBytes, 10),
+		})
+	}
+	dataFile := dataloader.DataFile{
+		TableName: "monitor_test_performance",
+		Schema: map[string]dataloader.DataType{
+			"MonitorTest":     dataloader.DataTypeString,
+			"JiraComponent":   dataloader.DataTypeString,
+			"Phase":           dataloader.DataTypeString,
+			"DurationSeconds": dataloader.DataTypeFloat64,
+			"Goroutines":      dataloader.DataTypeInteger,
+			"Intervals":       dataloader.DataTypeInteger,
+			"AllocatedBytes":  dataloader.DataTypeInteger,
+		},
+		Rows: rows,
+	}
+	dataFileName := filepath.Join(storageDir, fmt.Sprintf("monitor-test-performance%s-%s", timeSuffix, dataloader.AutoDataLoaderSuffix))
+	if err := dataloader.WriteDataFile(dataFileName, dataFile); err != nil {
+		return err
+	}
+
+	tableFileName := filepath.Join(storageDir, fmt.Sprintf("monitor-test-performance%s.txt", timeSuffix))
+	tableFile, err := os.Create(tableFileName)
+	if err != nil {
+		return err
+	}
+	defer tableFile.Close()
+	w := tabwriter.NewWriter(tableFile, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "MONITOR TEST\tPHASE\tDURATION\tGOROUTINES\tINTERVALS\tALLOCATED MB\t")
+	for _, curr := range performance {
+		allocated := "-"
+		if curr.allocationsMeasured {
+			allocated = fmt.Sprintf("%.1f", float64(curr.AllocatedBytes)/(1024*1024))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%v\t%d\t%d\t%s\t\n",
+			curr.MonitorTest, curr.Phase, curr.Duration.Round(time.Millisecond), curr.Goroutines, curr.Intervals, allocated)
+	}
+	fmt.Fprintln(w, "\t\t\t\t\t\t")
+	fmt.Fprintln(w, "ALL MONITOR TESTS\tPHASE\tDURATION\t\t\tPROCESS ALLOCATED MB\t")
+	for _, curr := range registryPhases {
+		fmt.Fprintf(w, "\t%s\t%v\t\t\t%.1f\t\n",
+			curr.Phase, curr.Duration.Round(time.Millisecond), float64(curr.AllocatedBytes)/(1024*1024))
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write %v: %w", tableFileName, err)
+	}
+	return tableFile.Close()
+}
+
+func allocatedBytes() uint64 {
+	sample := []metrics.Sample{{Name: allocatedBytesMetric}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
+
+// countingRecorder attributes the intervals recorded by a MonitorTest during collection to its StartCollection phase.
+func (t *performanceTracker) countingRecorder(monitorTest *monitorTesttItem, recorder monitorapi.RecorderWriter) monitorapi.RecorderWriter {
+	if recorder == nil {
+		return nil
+	}
+	return &intervalCountingRecorder{
+		RecorderWriter: recorder,
+		count: func(count int) {
+			t.addIntervals(monitorTest, StartCollectionPhase, count)
+		},
+	}
+}
+
+type intervalCountingRecorder struct {
+	monitorapi.RecorderWriter
+	count func(count int)
+}
+
+func (r *intervalCountingRecorder) Record(conditions ...monitorapi.Condition) {
+	r.count(len(conditions))
+	r.RecorderWriter.Record(conditions...)
+}
+
+func (r *intervalCountingRecorder) RecordAt(t time.Time, conditions ...monitorapi.Condition) {
+	r.count(len(conditions))
+	r.RecorderWriter.RecordAt(t, conditions...)
+}
+
+func (r *intervalCountingRecorder) AddIntervals(eventIntervals ...monitorapi.Interval) {
+	r.count(len(eventIntervals))
+	r.RecorderWriter.AddIntervals(eventIntervals...)
+}
+
+func (r *intervalCountingRecorder) StartInterval(interval monitorapi.Interval) int {
+	r.count(1)
+	return r.RecorderWriter.StartInterval(interval)
+}
@@ -0,0 +1,305 @@
+package monitortestframework
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// PhaseTimeout bounds how long a MonitorTest may spend in one phase.  When MonitorTest is empty, the timeout applies
+// to every MonitorTest that does not have its own timeout for the phase.  A zero Timeout disables the timeout.
+type PhaseTimeout struct {
+	MonitorTest string
+	Phase       MonitorTestPhase
+	Timeout     time.Duration
+}
+
+var allPhases = []MonitorTestPhase{
+	StartCollectionPhase,
+	CollectDataPhase,
+	ConstructComputedIntervalsPhase,
+	EvaluateTestsFromConstructedIntervalsPhase,
+	WriteContentToStoragePhase,
+	CleanupPhase,
+}
+
+// ParsePhaseTimeouts parses timeouts of the form [<monitor test>/]<phase>=<duration>, for instance
+// CollectData=30m or kubelet-log-collector/CollectData=45m.
+func ParsePhaseTimeouts(values []string) ([]PhaseTimeout, error) {
+	ret := []PhaseTimeout{}
+	for _, value := range values {
+		target, durationString, ok := strings.Cut(value, "=")
+		if !ok {
+			return nil, fmt.Errorf("monitor phase timeout %q must be of the form [<monitor test>/]<phase>=<duration>", value)
+		}
+		timeout := PhaseTimeout{}
+		if monitorTest, phase, ok := strings.Cut(target, "/"); ok {
+			timeout.MonitorTest = monitorTest
+			timeout.Phase = MonitorTestPhase(phase)
+		} else {
+			timeout.Phase = MonitorTestPhase(target)
+		}
+		duration, err := time.ParseDuration(durationString)
+		if err != nil {
+			return nil, fmt.Errorf("monitor phase timeout %q has an invalid duration: %w", value, err)
+		}
+		timeout.Timeout = duration
+		ret = append(ret, timeout)
+	}
+	return ret, nil
+}
+
+type phaseTimeoutKey struct {
+	monitorTest string
+	phase       MonitorTestPhase
+}
+
+func (r *monitorTestRegistry) SetPhaseTimeouts(timeouts ...PhaseTimeout) error {
+	for _, timeout := range timeouts {
+		if !isKnownPhase(timeout.Phase) {
+			return fmt.Errorf("unknown monitor test phase %q, expected one of %v", timeout.Phase, allPhases)
+		}
+		r.phaseTimeouts[phaseTimeoutKey{monitorTest: timeout.MonitorTest, phase: timeout.Phase}] = timeout.Timeout
+	}
+	return nil
+}
+
+func isKnownPhase(phase MonitorTestPhase) bool {
+	for _, curr := range allPhases {
+		if curr == phase {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *monitorTestRegistry) phaseTimeoutFor(monitorTest string, phase MonitorTestPhase) time.Duration {
+	if timeout, ok := r.phaseTimeouts[phaseTimeoutKey{monitorTest: monitorTest, phase: phase}]; ok {
+		return timeout
+	}
+	return r.phaseTimeouts[phaseTimeoutKey{phase: phase}]
+}
+
+// runPhase runs fn for a single MonitorTest and records what the phase cost.
+func (r *monitorTestRegistry) runPhase(ctx context.Context, monitorTest *monitorTesttItem, phase MonitorTestPhase, fn func(ctx context.Context) error) error {
+	measurement := r.performance.begin(monitorTest, phase)
+	err := r.runPhaseWithTimeout(ctx, monitorTest, phase, fn)
+	r.performance.end(measurement)
+	return err
+}
+
+// runPhaseWithTimeout runs fn for a single MonitorTest.  If fn does not return within the configured timeout, its
+// context is cancelled and a StuckPhaseError is returned while fn is left to finish in the background.  Goroutines
+// are labeled with the MonitorTest so the goroutine dump can be narrowed to it.
+func (r *monitorTestRegistry) runPhaseWithTimeout(ctx context.Context, monitorTest *monitorTesttItem, phase MonitorTestPhase, fn func(ctx context.Context) error) error {
+	labels := pprof.Labels("monitortest", monitorTest.name, "phase", string(phase))
+	timeout := r.phaseTimeoutFor(monitorTest.name, phase)
+	if timeout <= 0 {
+		var err error
+		pprof.Do(ctx, labels, func(ctx context.Context) {
+			err = fn(ctx)
+		})
+		return err
+	}
+
+	phaseCtx, cancel := context.WithCancel(ctx)
+	defer func() {
+		// collection keeps running on the context passed to StartCollection after it returns, it is released
+		// when the caller's context is cancelled.
+		if phase != StartCollectionPhase {
+			cancel()
+		}
+	}()
+
+	errCh := make(chan error, 1)
+	go pprof.Do(phaseCtx, labels, func(ctx context.Context) {
+		errCh <- fn(ctx)
+	})
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case err := <-errCh:
+		return err
+	case <-timer.C:
+	}
+
+	cancel()
+	stuckErr := &StuckPhaseError{
+		MonitorTest: monitorTest.name,
+		Phase:       phase,
+		Timeout:     timeout,
+	}
+	_, stuckErr.Goroutines = goroutinesFor(monitorTest.name)
+	logrus.WithFields(logrus.Fields{
+		"monitorTest": monitorTest.name,
+		"phase":       phase,
+		"timeout":     timeout,
+	}).Errorf("monitor test is stuck, continuing without it\n%s", stuckErr.Goroutines)
+	return stuckErr
+}
+
+func isStuck(err error) bool {
+	var stuckErr *StuckPhaseError
+	return errors.As(err, &stuckErr)
+}
+
+// The helpers below only read the results of a phase when it finished, a stuck phase may still write them later.
+
+func (r *monitorTestRegistry) startCollection(ctx context.Context, monitorTest *monitorTesttItem, collector ClusterCollector, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	// collection records intervals for as long as the monitor runs, count them as they arrive.
+	recorder = r.performance.countingRecorder(monitorTest, recorder)
+	return r.runPhase(ctx, monitorTest, StartCollectionPhase, func(ctx context.Context) error {
+		return startCollectionWithPanicProtection(ctx, collector, adminRESTConfig, recorder)
+	})
+}
+
+func (r *monitorTestRegistry) collectData(ctx context.Context, monitorTest *monitorTesttItem, collector ClusterCollector, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	var intervals monitorapi.Intervals
+	var junits []*junitapi.JUnitTestCase
+	err := r.runPhase(ctx, monitorTest, CollectDataPhase, func(ctx context.Context) error {
+		var err error
+		intervals, junits, err = collectDataWithPanicProtection(ctx, collector, storageDir, beginning, end)
+		return err
+	})
+	if isStuck(err) {
+		return nil, nil, err
+	}
+	r.performance.addIntervals(monitorTest, CollectDataPhase, len(intervals))
+	return intervals, junits, err
+}
+
+func (r *monitorTestRegistry) constructComputedIntervals(ctx context.Context, monitorTest *monitorTesttItem, constructor IntervalConstructor, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	var intervals monitorapi.Intervals
+	err := r.runPhase(ctx, monitorTest, ConstructComputedIntervalsPhase, func(ctx context.Context) error {
+		var err error
+		intervals, err = constructComputedIntervalsWithPanicProtection(ctx, constructor, startingIntervals, recordedResources, beginning, end)
+		return err
+	})
+	if isStuck(err) {
+		return nil, err
+	}
+	r.performance.addIntervals(monitorTest, ConstructComputedIntervalsPhase, len(intervals))
+	return intervals, err
+}
+
+func (r *monitorTestRegistry) evaluateTestsFromConstructedIntervals(ctx context.Context, monitorTest *monitorTesttItem, evaluator IntervalEvaluator, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	var junits []*junitapi.JUnitTestCase
+	err := r.runPhase(ctx, monitorTest, EvaluateTestsFromConstructedIntervalsPhase, func(ctx context.Context) error {
+		var err error
+		junits, err = evaluateTestsFromConstructedIntervalsWithPanicProtection(ctx, evaluator, finalIntervals)
+		return err
+	})
+	if isStuck(err) {
+		return nil, err
+	}
+	return junits, err
+}
+
+func (r *monitorTestRegistry) writeContentToStorage(ctx context.Context, monitorTest *monitorTesttItem, writer StorageWriter, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return r.runPhase(ctx, monitorTest, WriteContentToStoragePhase, func(ctx context.Context) error {
+		return writeContentToStorageWithPanicProtection(ctx, writer, storageDir, timeSuffix, finalIntervals, finalResourceState)
+	})
+}
+
+func (r *monitorTestRegistry) cleanup(ctx context.Context, monitorTest *monitorTesttItem, cleaner Cleaner) error {
+	return r.runPhase(ctx, monitorTest, CleanupPhase, func(ctx context.Context) error {
+		return cleanupWithPanicProtection(ctx, cleaner)
+	})
+}
+
+// goroutinesFor returns how many goroutines are labeled with the MonitorTest and their stacks.
+func goroutinesFor(monitorTest string) (int, string) {
+	count := 0
+	ret := &strings.Builder{}
+	err := forEachGoroutineBlock(func(blockCount int, labeledMonitorTest, block string) {
+		if labeledMonitorTest != monitorTest {
+			return
+		}
+		count += blockCount
+		ret.WriteString(block)
+		ret.WriteString("\n")
+	})
+	if err != nil {
+		return 0, fmt.Sprintf("unable to dump goroutines: %v", err)
+	}
+
+	if ret.Len() == 0 {
+		return 0, "no goroutines found for the monitor test"
+	}
+	return count, ret.String()
+}
+
+// goroutinesByMonitorTest returns how many goroutines are labeled with each MonitorTest, from a single goroutine dump.
+func goroutinesByMonitorTest() map[string]int {
+	ret := map[string]int{}
+	err := forEachGoroutineBlock(func(blockCount int, labeledMonitorTest, block string) {
+		if len(labeledMonitorTest) > 0 {
+			ret[labeledMonitorTest] += blockCount
+		}
+	})
+	if err != nil {
+		logrus.WithError(err).Warn("unable to dump goroutines")
+	}
+	return ret
+}
+
+var monitorTestLabelRe = regexp.MustCompile(`"monitortest":("(?:[^"\\]|\\.)*")`)
+
+// forEachGoroutineBlock dumps the goroutines and calls fn for every group of goroutines with the same stack and labels,
+// with the MonitorTest the group is labeled with, if any.
+func forEachGoroutineBlock(fn func(count int, monitorTest, block string)) error {
+	buf := &bytes.Buffer{}
+	if err := pprof.Lookup("goroutine").WriteTo(buf, 1); err != nil {
+		return err
+	}
+
+	// with debug=1, goroutines with the same stack and labels are grouped into blank line separated blocks that
+	// start with the number of goroutines in the block.
+	block := &strings.Builder{}
+	blockCount := 0
+	monitorTest := ""
+	flush := func() {
+		if block.Len() > 0 {
+			fn(blockCount, monitorTest, block.String())
+		}
+		block.Reset()
+		blockCount = 0
+		monitorTest = ""
+	}
+	scanner := bufio.NewScanner(buf)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(line) == 0 {
+			flush()
+			continue
+		}
+		// the first block follows the "goroutine profile: total N" header without a blank line.
+		if countString, _, ok := strings.Cut(line, " @ "); ok && !strings.HasPrefix(line, "#") {
+			blockCount, _ = strconv.Atoi(countString)
+		}
+		if strings.HasPrefix(line, "# labels:") {
+			if match := monitorTestLabelRe.FindStringSubmatch(line); match != nil {
+				monitorTest, _ = strconv.Unquote(match[1])
+			}
+		}
+		block.WriteString(line)
+		block.WriteString("\n")
+	}
+	flush()
+	return scanner.Err()
+}
@@ -34,11 +34,33 @@ type MonitorTestInitializationInfo struct {
 
 	// DisableMonitorTests will remove any monitor tests contained in the provided list
 	DisableMonitorTests []string
+
+	// PhaseTimeouts bound the time a monitor test may spend in each phase, phases without one are not bounded
+	PhaseTimeouts []PhaseTimeout
 }
 
 type OpenshiftTestImageGetterFunc func(ctx context.Context, adminRESTConfig *rest.Config) (imagePullSpec string, notSupportedReason string, err error)
 
-type MonitorTest interface {
+// MonitorTest is anything that can be registered in a MonitorTestRegistry.  A MonitorTest participates in a phase
+// by implementing the matching optional interface: ClusterCollector, IntervalConstructor, IntervalEvaluator,
+// StorageWriter and Cleaner.  It must implement at least one of them.
+type MonitorTest interface{}
+
+// MonitorTestPhase is one of the phases a MonitorTestRegistry runs its MonitorTests through.
+type MonitorTestPhase string
+
+const (
+	StartCollectionPhase                       MonitorTestPhase = "StartCollection"
+	CollectDataPhase                           MonitorTestPhase = "CollectData"
+	ConstructComputedIntervalsPhase            MonitorTestPhase = "ConstructComputedIntervals"
+	EvaluateTestsFromConstructedIntervalsPhase MonitorTestPhase = "EvaluateTestsFromConstructedIntervals"
+	WriteContentToStoragePhase                 MonitorTestPhase = "WriteContentToStorage"
+	CleanupPhase                               MonitorTestPhase = "Cleanup"
+)
+
+// ClusterCollector is implemented by MonitorTests that gather data from a live cluster.  MonitorTests that implement
+// ClusterCollector or Cleaner need a cluster, all others can run against saved intervals and resources.
+type ClusterCollector interface {
 	// StartCollection is responsible for setting up all resources required for collection of data on the cluster.
 	// An error will not stop execution, but will cause a junit failure that will cause the job run to fail.
 	// This allows us to know when setups fail.
@@ -48,18 +70,29 @@ type MonitorTest interface {
 	// Errors reported will be indicated as junit test failure and will cause job runs to fail.
 	// storageDir is for gathering data only, not for writing in this stage.  To store data, use WriteContentToStorage
 	CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error)
+}
 
+// IntervalConstructor is implemented by MonitorTests that compute intervals from the raw intervals.
+type IntervalConstructor interface {
 	// ConstructComputedIntervals is called after all InvariantTests have produced raw Intervals.
-	// Order of ConstructComputedIntervals across different InvariantTests is not guaranteed.
+	// Order of ConstructComputedIntervals across different InvariantTests is only guaranteed for declared dependencies,
+	// startingIntervals then also holds the intervals constructed by the tests this one depends on.
+	// startingIntervals may be shared with other tests constructing intervals at the same time, do not modify it.
 	// Return *only* the constructed intervals.
 	// Errors reported will be indicated as junit test failure and will cause job runs to fail.
 	ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (constructedIntervals monitorapi.Intervals, err error)
+}
 
+// IntervalEvaluator is implemented by MonitorTests that produce junit results from the final intervals.
+type IntervalEvaluator interface {
 	// EvaluateTestsFromConstructedIntervals is called after all Intervals are known and can produce
 	// junit tests for reporting purposes.
 	// Errors reported will be indicated as junit test failure and will cause job runs to fail.
 	EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error)
+}
 
+// StorageWriter is implemented by MonitorTests that write artifacts.
+type StorageWriter interface {
 	// WriteContentToStorage writes content to the storage directory that is collected by openshift CI.
 	// Do not write junits, intervals, or tracked resources.
 	// 1. junits.  Those should be returned from EvaluateTestsFromConstructedIntervals
@@ -69,7 +102,10 @@ type MonitorTest interface {
 	// code that scans audit logs and reports summaries of top actors.
 	// Errors reported will be indicated as junit test failure and will cause job runs to fail.
 	WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error
+}
 
+// Cleaner is implemented by MonitorTests that must release what they created on the cluster.
+type Cleaner interface {
 	// Cleanup must be idempotent and it may be called multiple times in any scenario.  Multiple defers, multi-registered
 	// abort handlers, abort handler running concurrent to planned shutdown.  Make your cleanup callable multiple times.
 	// Errors reported will cause job runs to fail to ensure cleanup functions work reliably.
@@ -81,13 +117,31 @@ type MonitorTestRegistry interface {
 
 	// AddMonitorTest adds an invariant test with a particular name, the name will be used to create a testsuite.
 	// The jira component will be forced into every JunitTestCase.
-	AddMonitorTest(name, jiraComponent string, monitorTest MonitorTest) error
+	// dependencies name the monitor tests whose constructed intervals this test needs, they are resolved when
+	// intervals are constructed so they may be registered in any order.
+	AddMonitorTest(name, jiraComponent string, monitorTest MonitorTest, dependencies ...string) error
 
-	AddMonitorTestOrDie(name, jiraComponent string, monitorTest MonitorTest)
+	AddMonitorTestOrDie(name, jiraComponent string, monitorTest MonitorTest, dependencies ...string)
 
+	// SetPhaseTimeouts bounds how long each MonitorTest may spend in a phase.  A MonitorTest that exceeds its timeout
+	// has its context cancelled and is reported as a junit failure naming the phase, the remaining MonitorTests continue.
+	SetPhaseTimeouts(timeouts ...PhaseTimeout) error
+
+	// GetRegistryFor returns a registry with only the named MonitorTests, it keeps the phase timeouts.
 	GetRegistryFor(names ...string) (MonitorTestRegistry, error)
+	// GetRegistryWithout returns a registry without the named MonitorTests, it keeps the phase timeouts.  It fails if
+	// a remaining MonitorTest depends on one of the named MonitorTests.
+	GetRegistryWithout(names ...string) (MonitorTestRegistry, error)
 	ListMonitorTests() sets.String
 
+	// ListMonitorTestsRequiringCluster returns the MonitorTests that collect data from or clean up a cluster.
+	ListMonitorTestsRequiringCluster() sets.String
+	// ListOfflineMonitorTests returns the MonitorTests that only operate on intervals and resources, so they can be
+	// run against data saved by a previous run.
+	ListOfflineMonitorTests() sets.String
+	// ListMonitorTestPhases returns the phases each MonitorTest participates in, in execution order.
+	ListMonitorTestPhases() map[string][]MonitorTestPhase
+
 	// StartCollection is responsible for setting up all resources required for collection of data on the cluster.
 	// An error will not stop execution, but will cause a junit failure that will cause the job run to fail.
 	// This allows us to know when setups fail.
@@ -98,8 +152,10 @@ type MonitorTestRegistry interface {
 	CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error)
 
 	// ConstructComputedIntervals is called after all InvariantTests have produced raw Intervals.
-	// Order of ConstructComputedIntervals across different InvariantTests is not guaranteed.
+	// InvariantTests run after the tests they depend on and independent tests run in parallel.  Each test receives the
+	// raw Intervals plus the intervals constructed by the tests it depends on.
 	// Return *only* the constructed intervals.
+	// Missing dependencies and dependency cycles are reported as junit failures and those tests are not run.
 	// Errors reported will be indicated as junit test failure and will cause job runs to fail.
 	ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error)
 
@@ -115,6 +171,8 @@ type MonitorTestRegistry interface {
 	// 3. tracked resources.  Those are written by some default monitorTests.
 	// You *may* choose to store state in CollectData that you later persist via this method. An example might be
 	// code that scans audit logs and reports summaries of top actors.
+	// The registry also writes the time, allocations, goroutines, and intervals of every monitor test in every phase
+	// to monitor-test-performance<timeSuffix>.
 	WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) ([]*junitapi.JUnitTestCase, error)
 
 	// Cleanup must be idempotent and it may be called multiple times in any scenario.  Multiple defers, multi-registered
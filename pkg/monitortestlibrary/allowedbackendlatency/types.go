@@ -0,0 +1,43 @@
+package allowedbackendlatency
+
+import (
+	_ "embed"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/historicaldata"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+)
+
+// queryResults has the same shape as the historical disruption data: for each backend and job type, the P95 and P99,
+// in seconds, of the P99Seconds that job runs wrote to the BackendLatency table.  It is empty on purpose: no job run has
+// uploaded its latency yet, so there is nothing to seed it with, and the latency check of backendlatencyanalyzer is
+// inactive.  Every latency test is skipped until the file is populated from BigQuery the way the historical disruption
+// data of allowedbackenddisruption is.
+//
+//go:embed query_results.json
+var queryResults []byte
+
+var (
+	readResults    sync.Once
+	historicalData *historicaldata.DisruptionBestMatcher
+)
+
+func GetCurrentResults() *historicaldata.DisruptionBestMatcher {
+	readResults.Do(
+		func() {
+			var err error
+			historicalData, err = historicaldata.NewDisruptionMatcher(queryResults)
+			if err != nil {
+				panic(err)
+			}
+		})
+
+	return historicalData
+}
+
+// GetAllowedLatency uses the backend and information about the cluster to choose the best historical P99 of the P99
+// latency to operate against.  It returns nil when there is not enough historical data.
+func GetAllowedLatency(backendName string, jobType platformidentification.JobType) (*time.Duration, string, error) {
+	return GetCurrentResults().BestMatchP99(backendName, jobType)
+}
@@ -0,0 +1,230 @@
+// Package disruptioncorrelation finds the intervals from other sources that happened around a disruption, ranked by how
+// likely they are to explain it.  It automates the first thing anyone does with a disruption: look at the chart for
+// nodes going NotReady, etcd changing leaders, the apiserver shutting down, or operators going Degraded at that time.
+package disruptioncorrelation
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	// DefaultWindow is how long before a disruption begins and after it ends other intervals are still considered.
+	DefaultWindow = 1 * time.Minute
+
+	// DefaultMaxCandidates is how many candidates are kept for each disruption.
+	DefaultMaxCandidates = 5
+)
+
+// sourceWeights is how likely an interval from each source is to explain a disruption.  Sources that are not listed
+// are weighed at defaultSourceWeight.
+var sourceWeights = map[monitorapi.IntervalSource]float64{
+	monitorapi.SourceNodeState:                 3,
+	monitorapi.SourceEtcdLeadership:            3,
+	monitorapi.APIServerGracefulShutdown:       3,
+	monitorapi.SourceAPIServerShutdown:         3,
+	monitorapi.SourceNodeMonitor:               2.5,
+	monitorapi.SourceOperatorState:             2,
+	monitorapi.SourceClusterOperatorMonitor:    1.5,
+	monitorapi.APIServerClusterOperatorWatcher: 1.5,
+	monitorapi.SourceAlert:                     1.5,
+	monitorapi.SourceKubeEvent:                 1,
+	monitorapi.SourceCloudMetrics:              1,
+}
+
+const defaultSourceWeight = 0.5
+
+// ignoredSources never explain a disruption.  Other disruption is a symptom of the same cause, and tests and test data
+// run throughout the job.
+var ignoredSources = sets.NewString(
+	string(monitorapi.SourceDisruption),
+	string(monitorapi.SourceE2ETest),
+	string(monitorapi.SourceTestData),
+)
+
+// levelWeights favors the intervals their sources considered worse.
+var levelWeights = map[monitorapi.IntervalLevel]float64{
+	monitorapi.Info:    1,
+	monitorapi.Warning: 1.5,
+	monitorapi.Error:   2,
+}
+
+// precedingBonus favors intervals that began no later than the disruption, since a cause comes before its effect.
+const precedingBonus = 1.25
+
+// Correlation holds the intervals that happened around one disruption interval, the most likely cause first.
+type Correlation struct {
+	Backend    string      `json:"backend"`
+	Locator    string      `json:"locator"`
+	Message    string      `json:"message"`
+	From       time.Time   `json:"from"`
+	To         time.Time   `json:"to"`
+	Candidates []Candidate `json:"candidates"`
+}
+
+// Candidate is an interval that may explain a disruption.
+type Candidate struct {
+	// Score is sourceWeight * levelWeight * proximity, times precedingBonus when the candidate began first.  Proximity
+	// is 1 when the candidate overlaps the disruption and falls linearly to 0 at the edge of the window.
+	Score float64 `json:"score"`
+	// Why explains the score.
+	Why string `json:"why"`
+
+	Source  string    `json:"source"`
+	Level   string    `json:"level"`
+	Locator string    `json:"locator"`
+	Reason  string    `json:"reason"`
+	Message string    `json:"message"`
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+}
+
+// IsDisruptionBegan returns true for the intervals recorded while a backend was unreachable.
+func IsDisruptionBegan(interval monitorapi.Interval) bool {
+	return interval.Source == monitorapi.SourceDisruption && interval.Message.Reason == monitorapi.DisruptionBeganEventReason
+}
+
+// Correlate finds every disruption in intervals and correlates it with the other intervals.
+func Correlate(intervals monitorapi.Intervals, window time.Duration, maxCandidates int) []Correlation {
+	return CorrelateDisruptions(intervals.Filter(IsDisruptionBegan), intervals, window, maxCandidates)
+}
+
+// CorrelateDisruptions ranks the intervals that overlap each disruption, or are within window of it, and keeps the
+// maxCandidates highest scoring.
+func CorrelateDisruptions(disruptions, intervals monitorapi.Intervals, window time.Duration, maxCandidates int) []Correlation {
+	possibleCauses := intervals.Filter(func(interval monitorapi.Interval) bool {
+		return !ignoredSources.Has(string(interval.Source))
+	})
+
+	ret := []Correlation{}
+	for _, disruption := range disruptions {
+		disruptionEnd := endOf(disruption)
+		candidates := []Candidate{}
+		for _, interval := range possibleCauses.Overlapping(disruption.From.Add(-window), disruptionEnd.Add(window)) {
+			candidates = append(candidates, score(disruption, interval, window))
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			if candidates[i].Score != candidates[j].Score {
+				return candidates[i].Score > candidates[j].Score
+			}
+			return candidates[i].From.Before(candidates[j].From)
+		})
+		if len(candidates) > maxCandidates {
+			candidates = candidates[:maxCandidates]
+		}
+
+		ret = append(ret, Correlation{
+			Backend:    monitorapi.BackendDisruptionNameFromLocator(disruption.Locator),
+			Locator:    disruption.Locator.OldLocator(),
+			Message:    disruption.Message.HumanMessage,
+			From:       disruption.From,
+			To:         disruption.To,
+			Candidates: candidates,
+		})
+	}
+	return ret
+}
+
+func score(disruption, interval monitorapi.Interval, window time.Duration) Candidate {
+	disruptionEnd, intervalEnd := endOf(disruption), endOf(interval)
+
+	sourceWeight, ok := sourceWeights[interval.Source]
+	if !ok {
+		sourceWeight = defaultSourceWeight
+	}
+	levelWeight, ok := levelWeights[interval.Level]
+	if !ok {
+		levelWeight = 1
+	}
+
+	why := []string{fmt.Sprintf("%s from %s", interval.Level, interval.Source)}
+	var gap time.Duration
+	switch {
+	case intervalEnd.Before(disruption.From):
+		gap = disruption.From.Sub(intervalEnd)
+		why = append(why, fmt.Sprintf("ended %s before", gap))
+	case interval.From.After(disruptionEnd):
+		gap = interval.From.Sub(disruptionEnd)
+		why = append(why, fmt.Sprintf("began %s after", gap))
+	default:
+		why = append(why, "overlapped")
+	}
+	proximity := 1.0
+	if window > 0 {
+		proximity = math.Max(0, 1-float64(gap)/float64(window))
+	}
+
+	value := sourceWeight * levelWeight * proximity
+	if !interval.From.After(disruption.From) {
+		value *= precedingBonus
+		if gap == 0 {
+			why = append(why, fmt.Sprintf("began %s before", disruption.From.Sub(interval.From)))
+		}
+	}
+
+	return Candidate{
+		Score:   math.Round(value*100) / 100,
+		Why:     strings.Join(why, ", "),
+		Source:  string(interval.Source),
+		Level:   interval.Level.String(),
+		Locator: interval.Locator.OldLocator(),
+		Reason:  string(interval.Message.Reason),
+		Message: interval.Message.HumanMessage,
+		From:    interval.From,
+		To:      interval.To,
+	}
+}
+
+// endOf returns the end of interval, which is its From when it has not ended.
+func endOf(interval monitorapi.Interval) time.Time {
+	if interval.To.Before(interval.From) {
+		return interval.From
+	}
+	return interval.To
+}
+
+// Describe renders correlations for a junit failure, showing at most maxCorrelations disruptions.
+func Describe(correlations []Correlation, maxCorrelations int) string {
+	lines := []string{"Possible causes, most likely first:"}
+	described := 0
+	for _, correlation := range correlations {
+		if len(correlation.Candidates) == 0 {
+			continue
+		}
+		if described == maxCorrelations {
+			lines = append(lines, fmt.Sprintf("... and %d more disruptions, see disruption-correlations*.json", countWithCandidates(correlations)-described))
+			break
+		}
+		described++
+		lines = append(lines, fmt.Sprintf("%s - %s %s:",
+			correlation.From.UTC().Format(time.RFC3339), correlation.To.UTC().Format(time.RFC3339), correlation.Locator))
+		for _, candidate := range correlation.Candidates {
+			message := candidate.Message
+			if len(message) == 0 {
+				message = candidate.Reason
+			}
+			lines = append(lines, fmt.Sprintf("  %.2f %s %s (%s)", candidate.Score, candidate.Locator,
+				strings.ReplaceAll(message, "\n", `\n`), candidate.Why))
+		}
+	}
+	if described == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n")
+}
+
+func countWithCandidates(correlations []Correlation) int {
+	ret := 0
+	for _, correlation := range correlations {
+		if len(correlation.Candidates) > 0 {
+			ret++
+		}
+	}
+	return ret
+}
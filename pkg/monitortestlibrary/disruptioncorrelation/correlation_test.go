@@ -0,0 +1,58 @@
+package disruptioncorrelation
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrelate(t *testing.T) {
+	start := time.Date(2023, 2, 14, 20, 30, 0, 0, time.UTC)
+	disruption := monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Error).
+		Locator(monitorapi.NewLocator().Disruption("ingress-to-console-new-connections", "ingress-to-console", "new", "GET", "", "")).
+		Message(monitorapi.NewMessage().Reason(monitorapi.DisruptionBeganEventReason).HumanMessage("backend unreachable")).
+		Build(start, start.Add(10*time.Second))
+
+	interval := func(source monitorapi.IntervalSource, level monitorapi.IntervalLevel, node string, from, to time.Time) monitorapi.Interval {
+		return monitorapi.NewInterval(source, level).
+			Locator(monitorapi.NewLocator().NodeFromName(node)).
+			Message(monitorapi.NewMessage().HumanMessage(node)).
+			Build(from, to)
+	}
+	notReady := interval(monitorapi.SourceNodeState, monitorapi.Warning, "not-ready", start.Add(-5*time.Second), start.Add(20*time.Second))
+	kubeEvent := interval(monitorapi.SourceKubeEvent, monitorapi.Info, "kube-event", start.Add(5*time.Second), start.Add(5*time.Second))
+	lateLeaderChange := interval(monitorapi.SourceEtcdLeadership, monitorapi.Info, "late-leader-change", start.Add(40*time.Second), start.Add(41*time.Second))
+	tooLate := interval(monitorapi.SourceEtcdLeadership, monitorapi.Error, "too-late", start.Add(2*time.Minute), start.Add(3*time.Minute))
+	test := interval(monitorapi.SourceE2ETest, monitorapi.Error, "e2e-test", start, start.Add(time.Minute))
+	otherDisruption := interval(monitorapi.SourceDisruption, monitorapi.Error, "other-disruption", start, start.Add(time.Minute))
+
+	correlations := Correlate(monitorapi.Intervals{test, otherDisruption, tooLate, lateLeaderChange, kubeEvent, notReady, disruption}, time.Minute, DefaultMaxCandidates)
+	if !assert.Len(t, correlations, 1) {
+		return
+	}
+	correlation := correlations[0]
+	assert.Equal(t, "ingress-to-console-new-connections", correlation.Backend)
+
+	ranked := []string{}
+	for _, candidate := range correlation.Candidates {
+		ranked = append(ranked, candidate.Message)
+	}
+	// not-ready: 3 * 1.5 * 1 * 1.25, kube-event: 1 * 1 * 1, late-leader-change: 3 * 1 * (1 - 30s/1m)
+	assert.Equal(t, []string{"not-ready", "late-leader-change", "kube-event"}, ranked)
+	assert.Equal(t, 5.63, correlation.Candidates[0].Score)
+	assert.Equal(t, "Warning from NodeState, overlapped, began 5s before", correlation.Candidates[0].Why)
+	assert.Equal(t, 1.5, correlation.Candidates[1].Score)
+	assert.Equal(t, "Info from EtcdLeadership, began 30s after", correlation.Candidates[1].Why)
+
+	limited := CorrelateDisruptions(monitorapi.Intervals{disruption}, monitorapi.Intervals{kubeEvent, notReady}, time.Minute, 1)
+	assert.Len(t, limited[0].Candidates, 1)
+
+	described := Describe(correlations, 10)
+	assert.True(t, strings.HasPrefix(described, "Possible causes, most likely first:\n2023-02-14T20:30:00Z - 2023-02-14T20:30:10Z"), described)
+	assert.Contains(t, described, "  5.63 node/not-ready not-ready (Warning from NodeState, overlapped, began 5s before)")
+
+	assert.Empty(t, Describe(Correlate(monitorapi.Intervals{disruption}, time.Minute, DefaultMaxCandidates), 10))
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/openshift/origin/pkg/monitortestlibrary/allowedbackenddisruption"
+	"github.com/openshift/origin/pkg/monitortestlibrary/disruptioncorrelation"
 	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
 
 	"github.com/openshift/origin/pkg/monitor/backenddisruption"
@@ -28,13 +29,17 @@ type Availability struct {
 	// which will include any upgrade versions
 	adminRESTConfig *rest.Config
 
-	newConnectionDisruptionSampler    *backenddisruption.BackendSampler
-	reusedConnectionDisruptionSampler *backenddisruption.BackendSampler
+	newConnectionDisruptionSampler    backenddisruption.Sampler
+	reusedConnectionDisruptionSampler backenddisruption.Sampler
 }
 
+// NewAvailabilityInvariant checks the availability of a backend with new and reused connections.  A sampler that is a
+// backenddisruption.ParitySampler also gets a junit for where its candidate disagrees with it.
+// A backend that has no reused connections, like DNS over UDP, passes a nil reusedConnectionDisruptionSampler and
+// reusedConnectionTestName is not reported.
 func NewAvailabilityInvariant(
 	newConnectionTestName, reusedConnectionTestName string,
-	newConnectionDisruptionSampler, reusedConnectionDisruptionSampler *backenddisruption.BackendSampler) *Availability {
+	newConnectionDisruptionSampler, reusedConnectionDisruptionSampler backenddisruption.Sampler) *Availability {
 	return &Availability{
 		newConnectionTestName:             newConnectionTestName,
 		reusedConnectionTestName:          reusedConnectionTestName,
@@ -53,6 +58,9 @@ func (w *Availability) StartCollection(ctx context.Context, adminRESTConfig *res
 	if err := w.newConnectionDisruptionSampler.StartEndpointMonitoring(ctx, recorder, nil); err != nil {
 		return err
 	}
+	if w.reusedConnectionDisruptionSampler == nil {
+		return nil
+	}
 	if err := w.reusedConnectionDisruptionSampler.StartEndpointMonitoring(ctx, recorder, nil); err != nil {
 		return err
 	}
@@ -82,29 +90,39 @@ func (w *Availability) CollectData(ctx context.Context) (monitorapi.Intervals, [
 	}()
 
 	var reusedRecoverErr error
-	wg.Add(1)
-	go func() {
-		defer func() {
-			if r := recover(); r != nil {
-				reusedRecoverErr = fmt.Errorf("panic in stop: %v", r)
-			}
+	if w.reusedConnectionDisruptionSampler != nil {
+		wg.Add(1)
+		go func() {
+			defer func() {
+				if r := recover(); r != nil {
+					reusedRecoverErr = fmt.Errorf("panic in stop: %v", r)
+				}
+			}()
+
+			defer wg.Done()
+			w.reusedConnectionDisruptionSampler.Stop()
 		}()
-
-		defer wg.Done()
-		w.reusedConnectionDisruptionSampler.Stop()
-	}()
+	}
 
 	wg.Wait()
 
 	return nil, nil, utilerrors.NewAggregate([]error{newRecoverErr, reusedRecoverErr})
 }
 
+const (
+	// maxCorrelationCandidates and maxCorrelatedDisruptions keep the possible causes in a failure readable, the full
+	// list is in disruption-correlations*.json.
+	maxCorrelationCandidates = 3
+	maxCorrelatedDisruptions = 10
+)
+
 func createDisruptionJunit(
 	testName string,
 	allowedDisruption *time.Duration,
 	disruptionDetails string,
 	locator monitorapi.Locator,
 	disruptedIntervals monitorapi.Intervals,
+	finalIntervals monitorapi.Intervals,
 	jobType *platformidentification.JobType) *junitapi.JUnitTestCase {
 
 	// Not sure what these are, but this will help find them, and we don't get any value from testing these:
@@ -171,6 +189,11 @@ func createDisruptionJunit(
 		roundedDisruptionDuration, finalAllowedDisruption,
 		strings.Join(allowedDetails, "\n"),
 		strings.Join(describe, "\n"))
+	correlations := disruptioncorrelation.CorrelateDisruptions(disruptedIntervals, finalIntervals,
+		disruptioncorrelation.DefaultWindow, maxCorrelationCandidates)
+	if possibleCauses := disruptioncorrelation.Describe(correlations, maxCorrelatedDisruptions); len(possibleCauses) > 0 {
+		failureMessage = fmt.Sprintf("%s\n\n%s", failureMessage, possibleCauses)
+	}
 
 	return &junitapi.JUnitTestCase{
 		Name: testName,
@@ -194,6 +217,7 @@ func (w *Availability) junitForNewConnections(ctx context.Context, finalInterval
 					monitorapi.IsErrorEvent,
 				),
 			),
+			finalIntervals,
 			jobType,
 		),
 		nil
@@ -212,12 +236,13 @@ func (w *Availability) junitForReusedConnections(ctx context.Context, finalInter
 					monitorapi.IsErrorEvent,
 				),
 			),
+			finalIntervals,
 			jobType,
 		),
 		nil
 }
 
-func historicalAllowedDisruption(ctx context.Context, backend *backenddisruption.BackendSampler, jobType *platformidentification.JobType) (*time.Duration, string, error) {
+func historicalAllowedDisruption(ctx context.Context, backend backenddisruption.Sampler, jobType *platformidentification.JobType) (*time.Duration, string, error) {
 	return allowedbackenddisruption.GetAllowedDisruption(backend.GetDisruptionBackendName(), *jobType)
 }
 
@@ -237,10 +262,40 @@ func (w *Availability) EvaluateTestsFromConstructedIntervals(ctx context.Context
 		return nil, err
 	}
 
-	reusedConnectionJunit, err := w.junitForReusedConnections(ctx, finalIntervals, jobType)
-	if err != nil {
-		return nil, err
+	junits := []*junitapi.JUnitTestCase{newConnectionJunit}
+	samplers := []backenddisruption.Sampler{w.newConnectionDisruptionSampler}
+	if w.reusedConnectionDisruptionSampler != nil {
+		reusedConnectionJunit, err := w.junitForReusedConnections(ctx, finalIntervals, jobType)
+		if err != nil {
+			return nil, err
+		}
+		junits = append(junits, reusedConnectionJunit)
+		samplers = append(samplers, w.reusedConnectionDisruptionSampler)
 	}
 
-	return []*junitapi.JUnitTestCase{newConnectionJunit, reusedConnectionJunit}, nil
+	for _, sampler := range samplers {
+		if paritySampler, ok := sampler.(*backenddisruption.ParitySampler); ok {
+			junits = append(junits, createSamplerParityJunit(paritySampler, paritySampler.Parity(finalIntervals))...)
+		}
+	}
+	return junits, nil
+}
+
+func createSamplerParityJunit(sampler backenddisruption.Sampler, parity backenddisruption.DisruptionParity) []*junitapi.JUnitTestCase {
+	testName := fmt.Sprintf("[sig-trt] disruption/%s connection/%s should see the same disruption with both disruption samplers",
+		sampler.GetDisruptionBackendName(), sampler.GetConnectionType())
+	if parity.Matches() {
+		return []*junitapi.JUnitTestCase{{Name: testName}}
+	}
+
+	failureMessage := fmt.Sprintf("the disruption samplers disagree:\n%s", parity)
+	return []*junitapi.JUnitTestCase{
+		{
+			Name: testName,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: failureMessage,
+			},
+			SystemOut: failureMessage,
+		},
+	}
 }
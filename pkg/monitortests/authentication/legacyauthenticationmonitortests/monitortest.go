@@ -11,6 +11,11 @@ import (
 	"k8s.io/client-go/rest"
 )
 
+var (
+	_ monitortestframework.ClusterCollector  = &legacyMonitorTests{}
+	_ monitortestframework.IntervalEvaluator = &legacyMonitorTests{}
+)
+
 type legacyMonitorTests struct {
 	adminRESTConfig *rest.Config
 }
@@ -28,10 +33,6 @@ func (w *legacyMonitorTests) CollectData(ctx context.Context, storageDir string,
 	return nil, nil, nil
 }
 
-func (*legacyMonitorTests) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
-	return nil, nil
-}
-
 func (w *legacyMonitorTests) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
 	junits := []*junitapi.JUnitTestCase{}
 	junits = append(junits, testOauthApiserverProbeErrorReadiness(finalIntervals)...)
@@ -40,11 +41,3 @@ func (w *legacyMonitorTests) EvaluateTestsFromConstructedIntervals(ctx context.C
 
 	return junits, nil
 }
-
-func (*legacyMonitorTests) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
-	return nil
-}
-
-func (*legacyMonitorTests) Cleanup(ctx context.Context) error {
-	return nil
-}
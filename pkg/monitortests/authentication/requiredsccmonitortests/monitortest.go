@@ -31,6 +31,8 @@ var defaultSCCs = sets.NewString(
 	"restricted-v2",
 )
 
+var _ monitortestframework.ClusterCollector = &requiredSCCAnnotationChecker{}
+
 type requiredSCCAnnotationChecker struct {
 	kubeClient kubernetes.Interface
 }
@@ -108,22 +110,6 @@ func (w *requiredSCCAnnotationChecker) CollectData(ctx context.Context, storageD
 	return nil, junits, nil
 }
 
-func (w *requiredSCCAnnotationChecker) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
-	return nil, nil
-}
-
-func (w *requiredSCCAnnotationChecker) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
-	return nil, nil
-}
-
-func (w *requiredSCCAnnotationChecker) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
-	return nil
-}
-
-func (w *requiredSCCAnnotationChecker) Cleanup(ctx context.Context) error {
-	return nil
-}
-
 // suggestSCC suggests the assigned SCC only if it belongs to the default set of SCCs
 // pods in runlevel 0/1 namespaces won't have any assigned SCC as SCC admission is disabled
 func suggestSCC(pod *v1.Pod) string {
@@ -31,6 +31,8 @@ const (
 	avgOSDiskQueueDepthThreshold = 3.0
 )
 
+var _ monitortestframework.ClusterCollector = &azureMetricsCollector{}
+
 type azureMetricsCollector struct {
 	adminRESTConfig    *rest.Config
 	flakeErr           error
@@ -213,19 +215,3 @@ func (w *azureMetricsCollector) CollectData(ctx context.Context, storageDir stri
 
 	return ret, nil, nil
 }
-
-func (*azureMetricsCollector) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
-	return nil, nil
-}
-
-func (*azureMetricsCollector) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
-	return nil, nil
-}
-
-func (*azureMetricsCollector) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
-	return nil
-}
-
-func (*azureMetricsCollector) Cleanup(ctx context.Context) error {
-	return nil
-}
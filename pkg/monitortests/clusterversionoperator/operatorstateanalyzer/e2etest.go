@@ -22,28 +22,9 @@ func E2ETestEventIntervals(events monitorapi.Intervals) monitorapi.Intervals {
 	return e2eEventIntervals
 }
 
-// FindOverlap finds intervals that overlap with the time between start and end.
+// FindOverlap finds intervals that overlap with the time between start and end.  An interval that starts exactly at
+// start overlaps, one that ends exactly at start or starts exactly at end does not.  When start and end are the same
+// instant, only the intervals in progress across it overlap.
 func FindOverlap(intervals monitorapi.Intervals, start, end time.Time) monitorapi.Intervals {
-	overlappingIntervals := monitorapi.Intervals{}
-	for i := range intervals {
-		interval := intervals[i]
-		switch {
-		case interval.From.After(start) && interval.From.Before(end):
-			// if the interval started during the window, we overlapped
-			overlappingIntervals = append(overlappingIntervals, interval)
-		case interval.To.After(start) && interval.To.Before(end):
-			// if the interval ended during the window, we overlapped
-			overlappingIntervals = append(overlappingIntervals, interval)
-
-		case interval.From.Before(start) && interval.To.After(end):
-			// if the interval started before the window and ended after the window, we overlapped
-			overlappingIntervals = append(overlappingIntervals, interval)
-
-		default:
-			// the other two cases are starting and ending before the window (no overlap) and
-			// starting and ending after the window (no overlap)
-		}
-	}
-
-	return overlappingIntervals
+	return intervals.Overlapping(start, end)
 }
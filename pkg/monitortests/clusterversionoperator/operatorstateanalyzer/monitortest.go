@@ -7,10 +7,10 @@ import (
 	"github.com/openshift/origin/pkg/monitortestframework"
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
-	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
-	"k8s.io/client-go/rest"
 )
 
+var _ monitortestframework.IntervalConstructor = &operatorStateChecker{}
+
 type operatorStateChecker struct {
 }
 
@@ -18,14 +18,6 @@ func NewAnalyzer() monitortestframework.MonitorTest {
 	return &operatorStateChecker{}
 }
 
-func (w *operatorStateChecker) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
-	return nil
-}
-
-func (w *operatorStateChecker) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
-	return nil, nil, nil
-}
-
 func (*operatorStateChecker) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
 	ret := monitorapi.Intervals{}
 	ret = append(ret, intervalsFromEvents_OperatorAvailable(startingIntervals, nil, beginning, end)...)
@@ -34,16 +26,3 @@ func (*operatorStateChecker) ConstructComputedIntervals(ctx context.Context, sta
 
 	return ret, nil
 }
-
-func (*operatorStateChecker) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
-	return nil, nil
-}
-
-func (*operatorStateChecker) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
-	return nil
-}
-
-func (*operatorStateChecker) Cleanup(ctx context.Context) error {
-	// TODO wire up the start to a context we can kill here
-	return nil
-}
@@ -125,3 +125,33 @@ func TestIntervalsFromEvents_OperatorProgressing2(t *testing.T) {
 	actual := intervalsFromEvents_OperatorProgressing(intervals, nil, time.Time{}, time.Time{})
 	assert.Equal(t, 1, len(actual))
 }
+
+func TestFindOverlap(t *testing.T) {
+	interval := func(name, from, to string) monitorapi.Interval {
+		return monitorapi.Interval{
+			Condition: monitorapi.Condition{
+				Locator: monitorapi.NewLocator().NodeFromName(name),
+			},
+			From: timeFor(from),
+			To:   timeFor(to),
+		}
+	}
+	intervals := monitorapi.Intervals{
+		interval("ends-at-start", "2022-03-22T18:50:00Z", "2022-03-22T19:00:00Z"),
+		interval("same-as-window", "2022-03-22T19:00:00Z", "2022-03-22T19:10:00Z"),
+		interval("starts-at-start-ends-after", "2022-03-22T19:00:00Z", "2022-03-22T19:20:00Z"),
+		interval("across-window", "2022-03-22T18:50:00Z", "2022-03-22T19:20:00Z"),
+		interval("starts-at-end", "2022-03-22T19:10:00Z", "2022-03-22T19:20:00Z"),
+	}
+
+	assert.Equal(t, monitorapi.Intervals{
+		interval("same-as-window", "2022-03-22T19:00:00Z", "2022-03-22T19:10:00Z"),
+		interval("starts-at-start-ends-after", "2022-03-22T19:00:00Z", "2022-03-22T19:20:00Z"),
+		interval("across-window", "2022-03-22T18:50:00Z", "2022-03-22T19:20:00Z"),
+	}, FindOverlap(intervals, timeFor("2022-03-22T19:00:00Z"), timeFor("2022-03-22T19:10:00Z")))
+
+	// the operator checks look for the e2e tests running at the instant of a condition change
+	assert.Equal(t, monitorapi.Intervals{
+		interval("across-window", "2022-03-22T18:50:00Z", "2022-03-22T19:20:00Z"),
+	}, FindOverlap(intervals, timeFor("2022-03-22T19:00:00Z"), timeFor("2022-03-22T19:00:00Z")))
+}
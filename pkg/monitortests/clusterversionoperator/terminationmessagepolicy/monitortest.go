@@ -28,6 +28,8 @@ func init() {
 	}
 }
 
+var _ monitortestframework.ClusterCollector = &terminationMessagePolicyChecker{}
+
 type terminationMessagePolicyChecker struct {
 	kubeClient    kubernetes.Interface
 	hasOldVersion bool
@@ -217,19 +219,3 @@ func (w *terminationMessagePolicyChecker) CollectData(ctx context.Context, stora
 
 	return nil, junits, nil
 }
-
-func (*terminationMessagePolicyChecker) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
-	return nil, nil
-}
-
-func (*terminationMessagePolicyChecker) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
-	return nil, nil
-}
-
-func (*terminationMessagePolicyChecker) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
-	return nil
-}
-
-func (*terminationMessagePolicyChecker) Cleanup(ctx context.Context) error {
-	return nil
-}
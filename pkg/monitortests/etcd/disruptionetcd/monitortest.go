@@ -0,0 +1,198 @@
+package disruptionetcd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/openshift/origin/pkg/monitortestframework"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	backendsampler "github.com/openshift/origin/pkg/disruption/backend/sampler"
+	disruptionci "github.com/openshift/origin/pkg/disruption/ci"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/disruptionlibrary"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	etcdNamespace      = "openshift-etcd"
+	etcdServiceName    = "etcd"
+	etcdClientPortName = "etcd"
+	// etcdServerName is in the serving certificate of every etcd member, the cluster IP of the service is not.
+	etcdServerName = "etcd.openshift-etcd.svc"
+
+	probeTimeout = 10 * time.Second
+)
+
+var (
+	_ monitortestframework.ClusterCollector    = &availability{}
+	_ monitortestframework.IntervalConstructor = &availability{}
+	_ monitortestframework.IntervalEvaluator   = &availability{}
+	_ monitortestframework.StorageWriter       = &availability{}
+	_ monitortestframework.Cleaner             = &availability{}
+)
+
+type availability struct {
+	disruptionChecker  *disruptionlibrary.Availability
+	notSupportedReason error
+}
+
+// NewAvailabilityInvariant samples the etcd client port through the etcd service.  New connections are sampled by
+// opening a TCP connection, reused connections by calling the gRPC health service of etcd over one client connection.
+// The service network is only reachable when openshift-tests runs inside the cluster, elsewhere it is not supported.
+func NewAvailabilityInvariant() monitortestframework.MonitorTest {
+	return &availability{}
+}
+
+func newProbeSampler(protocol backend.ProtocolType, connectionType monitorapi.BackendConnectionType, prober backendsampler.Prober) (disruptionci.Sampler, error) {
+	return disruptionci.NewProbeSampler(disruptionci.TestConfiguration{
+		TestDescriptor: disruptionci.TestDescriptor{
+			TargetServer:     disruptionci.Etcd,
+			LoadBalancerType: backend.ServiceNetworkType,
+			ConnectionType:   connectionType,
+			Protocol:         protocol,
+		},
+		Timeout:        probeTimeout,
+		SampleInterval: time.Second,
+	}, prober)
+}
+
+// etcdClientConfig returns the TLS configuration of the client certificate the kube-apiserver talks to etcd with.
+func etcdClientConfig(ctx context.Context, kubeClient kubernetes.Interface) (*rest.Config, error) {
+	caBundle, err := kubeClient.CoreV1().ConfigMaps("openshift-config").Get(ctx, "etcd-ca-bundle", metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	clientCert, err := kubeClient.CoreV1().Secrets("openshift-config").Get(ctx, "etcd-client", metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return &rest.Config{
+		TLSClientConfig: rest.TLSClientConfig{
+			ServerName: etcdServerName,
+			CertData:   clientCert.Data[corev1.TLSCertKey],
+			KeyData:    clientCert.Data[corev1.TLSPrivateKeyKey],
+			CAData:     []byte(caBundle.Data["ca-bundle.crt"]),
+		},
+	}, nil
+}
+
+func (w *availability) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	kubeClient, err := kubernetes.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+
+	service, err := kubeClient.CoreV1().Services(etcdNamespace).Get(ctx, etcdServiceName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{
+			Reason: fmt.Sprintf("service %s/%s not present", etcdNamespace, etcdServiceName),
+		}
+		return w.notSupportedReason
+	}
+	if err != nil {
+		return err
+	}
+	var port int32
+	for _, servicePort := range service.Spec.Ports {
+		if servicePort.Name == etcdClientPortName {
+			port = servicePort.Port
+		}
+	}
+	if port == 0 {
+		return fmt.Errorf("service %s/%s has no port named %q", etcdNamespace, etcdServiceName, etcdClientPortName)
+	}
+	address := net.JoinHostPort(service.Spec.ClusterIP, strconv.Itoa(int(port)))
+
+	tcpProber := backendsampler.NewTCPProber(address)
+	preflightCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+	if _, err := tcpProber.Probe(preflightCtx, 0); err != nil {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{
+			Reason: fmt.Sprintf("etcd client port %s is not reachable from the test process: %v", address, err),
+		}
+		return w.notSupportedReason
+	}
+
+	clientConfig, err := etcdClientConfig(ctx, kubeClient)
+	if err != nil {
+		return err
+	}
+	tlsConfig, err := rest.TLSConfigFor(clientConfig)
+	if err != nil {
+		return err
+	}
+	// etcd reports the health of the server as a whole, it does not name a service
+	grpcProber := backendsampler.NewGRPCHealthProber(address, "", true, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+
+	newConnections, err := newProbeSampler(backend.ProtocolTCP, monitorapi.NewConnectionType, tcpProber)
+	if err != nil {
+		return err
+	}
+	reusedConnections, err := newProbeSampler(backend.ProtocolGRPC, monitorapi.ReusedConnectionType, grpcProber)
+	if err != nil {
+		return err
+	}
+
+	disruptionBackedName := "etcd"
+	newConnectionTestName := fmt.Sprintf("[sig-etcd] disruption/%s connection/new should be available throughout the test", disruptionBackedName)
+	reusedConnectionTestName := fmt.Sprintf("[sig-etcd] disruption/%s connection/reused should be available throughout the test", disruptionBackedName)
+	w.disruptionChecker = disruptionlibrary.NewAvailabilityInvariant(
+		newConnectionTestName, reusedConnectionTestName,
+		newConnections, reusedConnections,
+	)
+
+	if err := w.disruptionChecker.StartCollection(ctx, adminRESTConfig, recorder); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (w *availability) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, nil, w.notSupportedReason
+	}
+	// we failed and indicated it during setup.
+	if w.disruptionChecker == nil {
+		return nil, nil, nil
+	}
+
+	return w.disruptionChecker.CollectData(ctx)
+}
+
+func (w *availability) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, w.notSupportedReason
+}
+
+func (w *availability) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	// we failed and indicated it during setup.
+	if w.disruptionChecker == nil {
+		return nil, nil
+	}
+
+	return w.disruptionChecker.EvaluateTestsFromConstructedIntervals(ctx, finalIntervals)
+}
+
+func (w *availability) WriteContentToStorage(ctx context.Context, storageDir string, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return w.notSupportedReason
+}
+
+func (w *availability) Cleanup(ctx context.Context) error {
+	return w.notSupportedReason
+}
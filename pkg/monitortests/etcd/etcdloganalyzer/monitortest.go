@@ -24,6 +24,11 @@ import (
 	"k8s.io/client-go/rest"
 )
 
+var (
+	_ monitortestframework.ClusterCollector    = &etcdLogAnalyzer{}
+	_ monitortestframework.IntervalConstructor = &etcdLogAnalyzer{}
+)
+
 type etcdLogAnalyzer struct {
 	adminRESTConfig *rest.Config
 
@@ -141,19 +146,6 @@ func (*etcdLogAnalyzer) ConstructComputedIntervals(ctx context.Context, starting
 	return ret, nil
 }
 
-func (*etcdLogAnalyzer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
-	return nil, nil
-}
-
-func (w *etcdLogAnalyzer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
-	return nil
-}
-
-func (*etcdLogAnalyzer) Cleanup(ctx context.Context) error {
-	// TODO wire up the start to a context we can kill here
-	return nil
-}
-
 type etcdRecorder struct {
 	recorder monitorapi.RecorderWriter
 	// TODO this limits our ability to have custom messages, we probably want something better
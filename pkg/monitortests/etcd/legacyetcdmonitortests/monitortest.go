@@ -15,6 +15,12 @@ import (
 	"k8s.io/client-go/rest"
 )
 
+var (
+	_ monitortestframework.ClusterCollector    = &legacyMonitorTests{}
+	_ monitortestframework.IntervalConstructor = &legacyMonitorTests{}
+	_ monitortestframework.IntervalEvaluator   = &legacyMonitorTests{}
+)
+
 type legacyMonitorTests struct {
 	adminRESTConfig    *rest.Config
 	jobType            *platformidentification.JobType
@@ -79,11 +85,3 @@ func (w *legacyMonitorTests) EvaluateTestsFromConstructedIntervals(ctx context.C
 
 	return junits, nil
 }
-
-func (*legacyMonitorTests) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
-	return nil
-}
-
-func (*legacyMonitorTests) Cleanup(ctx context.Context) error {
-	return nil
-}
@@ -31,6 +31,13 @@ const (
 	reusedConnectionTestName = "[sig-imageregistry] disruption/image-registry connection/reused should be available throughout the test"
 )
 
+var (
+	_ monitortestframework.ClusterCollector  = &availability{}
+	_ monitortestframework.IntervalEvaluator = &availability{}
+	_ monitortestframework.StorageWriter     = &availability{}
+	_ monitortestframework.Cleaner           = &availability{}
+)
+
 type availability struct {
 	kubeClient         kubernetes.Interface
 	routeClient        routeclient.Interface
@@ -129,10 +136,6 @@ func (w *availability) CollectData(ctx context.Context, storageDir string, begin
 	return w.disruptionChecker.CollectData(ctx)
 }
 
-func (*availability) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
-	return nil, nil
-}
-
 func (w *availability) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
 	if w.notSupportedReason != nil {
 		return nil, w.notSupportedReason
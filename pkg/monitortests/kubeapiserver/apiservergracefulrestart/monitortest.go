@@ -8,10 +8,10 @@ import (
 	"github.com/openshift/origin/pkg/monitortestframework"
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
-	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
-	"k8s.io/client-go/rest"
 )
 
+var _ monitortestframework.IntervalConstructor = &apiserverGracefulShutdownAnalyzer{}
+
 type apiserverGracefulShutdownAnalyzer struct {
 }
 
@@ -27,14 +27,6 @@ func NewGracefulShutdownAnalyzer() monitortestframework.MonitorTest {
 	return &apiserverGracefulShutdownAnalyzer{}
 }
 
-func (w *apiserverGracefulShutdownAnalyzer) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
-	return nil
-}
-
-func (w *apiserverGracefulShutdownAnalyzer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
-	return nil, nil, nil
-}
-
 func (*apiserverGracefulShutdownAnalyzer) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
 	computedIntervals := monitorapi.Intervals{}
 
@@ -144,19 +136,6 @@ func podFrom(locator string) monitorapi.PodReference {
 	}
 }
 
-func (*apiserverGracefulShutdownAnalyzer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
-	return nil, nil
-}
-
-func (w *apiserverGracefulShutdownAnalyzer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
-	return nil
-}
-
-func (*apiserverGracefulShutdownAnalyzer) Cleanup(ctx context.Context) error {
-	// TODO wire up the start to a context we can kill here
-	return nil
-}
-
 func interesting(interval monitorapi.Interval) (monitorapi.IntervalReason, bool) {
 	reason := interval.Message.Reason
 	switch reason {
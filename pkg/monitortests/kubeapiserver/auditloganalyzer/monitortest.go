@@ -12,6 +12,11 @@ import (
 	"k8s.io/client-go/rest"
 )
 
+var (
+	_ monitortestframework.ClusterCollector = &auditLogAnalyzer{}
+	_ monitortestframework.StorageWriter    = &auditLogAnalyzer{}
+)
+
 type auditLogAnalyzer struct {
 	adminRESTConfig *rest.Config
 
@@ -40,14 +45,6 @@ func (w *auditLogAnalyzer) CollectData(ctx context.Context, storageDir string, b
 	return auditEvents, nil, err
 }
 
-func (*auditLogAnalyzer) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
-	return nil, nil
-}
-
-func (*auditLogAnalyzer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
-	return nil, nil
-}
-
 func (w *auditLogAnalyzer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
 	if w.auditLogSummary != nil {
 		if currErr := WriteAuditLogSummary(storageDir, timeSuffix, w.auditLogSummary); currErr != nil {
@@ -57,11 +54,6 @@ func (w *auditLogAnalyzer) WriteContentToStorage(ctx context.Context, storageDir
 	return nil
 }
 
-func (*auditLogAnalyzer) Cleanup(ctx context.Context) error {
-	// TODO wire up the start to a context we can kill here
-	return nil
-}
-
 func intervalsFromAuditLogs(ctx context.Context, kubeClient kubernetes.Interface, beginning, end time.Time) (*AuditLogSummary, monitorapi.Intervals, error) {
 	ret := monitorapi.Intervals{}
 	auditLogSummary, err := GetKubeAuditLogSummary(ctx, kubeClient, &beginning, &end)
@@ -60,6 +60,11 @@ var (
 	rbacMonitorCRBName      string
 )
 
+var (
+	_ monitortestframework.ClusterCollector = &InvariantInClusterDisruption{}
+	_ monitortestframework.Cleaner          = &InvariantInClusterDisruption{}
+)
+
 type InvariantInClusterDisruption struct {
 	namespaceName               string
 	openshiftTestsImagePullSpec string
@@ -365,18 +370,6 @@ func (i *InvariantInClusterDisruption) CollectData(ctx context.Context, storageD
 	return intervals, junits, utilerrors.NewAggregate(errs)
 }
 
-func (i *InvariantInClusterDisruption) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, _ monitorapi.ResourcesMap, beginning time.Time, end time.Time) (constructedIntervals monitorapi.Intervals, err error) {
-	return nil, nil
-}
-
-func (i *InvariantInClusterDisruption) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
-	return nil, nil
-}
-
-func (i *InvariantInClusterDisruption) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
-	return nil
-}
-
 func (i *InvariantInClusterDisruption) Cleanup(ctx context.Context) error {
 	if len(i.notSupportedReason) > 0 {
 		return nil
@@ -2,19 +2,44 @@ package disruptionlegacyapiservers
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/openshift/origin/pkg/disruption/backend"
+	disruptionci "github.com/openshift/origin/pkg/disruption/ci"
 	"github.com/openshift/origin/pkg/monitor/backenddisruption"
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	"k8s.io/client-go/rest"
 )
 
-func createAPIServerBackendSampler(clusterConfig *rest.Config, disruptionBackendName, url string, connectionType monitorapi.BackendConnectionType) (*backenddisruption.BackendSampler, error) {
+// createAPIServerBackendSampler returns the sampler of an apiserver backend.  If a factory is given, the backend is also
+// sampled by a sampler of the factory, and the two are compared, see backenddisruption.ParitySampler.
+func createAPIServerBackendSampler(clusterConfig *rest.Config, factory disruptionci.Factory, targetServer disruptionci.ServerNameType, disruptionBackendName, url string, connectionType monitorapi.BackendConnectionType) (backenddisruption.Sampler, error) {
 	// default gets auto-created, so this should always exist
 	backendSampler, err := backenddisruption.NewAPIServerBackend(clusterConfig, disruptionBackendName, url, connectionType)
 	if err != nil {
 		return nil, err
 	}
 	backendSampler = backendSampler.WithUserAgent(fmt.Sprintf("openshift-external-backend-sampler-%s-%s", connectionType, disruptionBackendName))
+	if factory == nil {
+		return backendSampler, nil
+	}
 
-	return backendSampler, nil
+	// the candidate samples the way the backend sampler does: http/1.1 through
+	// the external load balancer, with the same timeout.
+	candidate, err := factory.New(disruptionci.TestConfiguration{
+		TestDescriptor: disruptionci.TestDescriptor{
+			TargetServer:     targetServer,
+			LoadBalancerType: backend.ExternalLoadBalancerType,
+			ConnectionType:   connectionType,
+			Protocol:         backend.ProtocolHTTP1,
+		},
+		Path:                         url,
+		Timeout:                      20 * time.Second,
+		SampleInterval:               time.Second,
+		EnableShutdownResponseHeader: targetServer == disruptionci.KubeAPIServer,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return backenddisruption.NewParitySampler(backendSampler, candidate, backenddisruption.DefaultParityTolerance), nil
 }
@@ -14,16 +14,27 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 
+	disruptionci "github.com/openshift/origin/pkg/disruption/ci"
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	"github.com/openshift/origin/pkg/monitortestlibrary/disruptionlibrary"
 	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
 )
 
+var (
+	_ monitortestframework.ClusterCollector    = &availability{}
+	_ monitortestframework.IntervalConstructor = &availability{}
+	_ monitortestframework.IntervalEvaluator   = &availability{}
+	_ monitortestframework.StorageWriter       = &availability{}
+	_ monitortestframework.Cleaner             = &availability{}
+)
+
 type availability struct {
 	disruptionCheckers []*disruptionlibrary.Availability
 
 	notSupportedReason error
 	suppressJunit      bool
+	// samplerParity samples every backend with both disruption sampler stacks, and reports where they disagree.
+	samplerParity bool
 }
 
 func NewAvailabilityInvariant() monitortestframework.MonitorTest {
@@ -35,19 +46,28 @@ func NewRecordAvailabilityOnly() monitortestframework.MonitorTest {
 		suppressJunit: true,
 	}
 }
+
+// NewAvailabilityInvariantWithSamplerParity is NewAvailabilityInvariant with every backend also sampled by the
+// samplers of pkg/disruption/ci, it reports where their disruption differs from that of the backend samplers.
+func NewAvailabilityInvariantWithSamplerParity() monitortestframework.MonitorTest {
+	return &availability{
+		samplerParity: true,
+	}
+}
+
 func testNames(owner, disruptionBackendName string) (string, string) {
 	return fmt.Sprintf("[%s] disruption/%s connection/new should be available throughout the test", owner, disruptionBackendName),
 		fmt.Sprintf("[%s] disruption/%s connection/reused should be available throughout the test", owner, disruptionBackendName)
 }
 
-func newDisruptionCheckerForKubeAPI(adminRESTConfig *rest.Config) (*disruptionlibrary.Availability, error) {
+func newDisruptionCheckerForKubeAPI(adminRESTConfig *rest.Config, factory disruptionci.Factory) (*disruptionlibrary.Availability, error) {
 	disruptionBackedName := "kube-api"
 	newConnectionTestName, reusedConnectionTestName := testNames("sig-api-machinery", disruptionBackedName)
-	newConnections, err := createAPIServerBackendSampler(adminRESTConfig, disruptionBackedName, "/api/v1/namespaces/default", monitorapi.NewConnectionType)
+	newConnections, err := createAPIServerBackendSampler(adminRESTConfig, factory, disruptionci.KubeAPIServer, disruptionBackedName, "/api/v1/namespaces/default", monitorapi.NewConnectionType)
 	if err != nil {
 		return nil, err
 	}
-	reusedConnections, err := createAPIServerBackendSampler(adminRESTConfig, disruptionBackedName, "/api/v1/namespaces/default", monitorapi.ReusedConnectionType)
+	reusedConnections, err := createAPIServerBackendSampler(adminRESTConfig, factory, disruptionci.KubeAPIServer, disruptionBackedName, "/api/v1/namespaces/default", monitorapi.ReusedConnectionType)
 	if err != nil {
 		return nil, err
 	}
@@ -57,16 +77,16 @@ func newDisruptionCheckerForKubeAPI(adminRESTConfig *rest.Config) (*disruptionli
 	), nil
 }
 
-func newDisruptionCheckerForKubeAPICached(adminRESTConfig *rest.Config) (*disruptionlibrary.Availability, error) {
+func newDisruptionCheckerForKubeAPICached(adminRESTConfig *rest.Config, factory disruptionci.Factory) (*disruptionlibrary.Availability, error) {
 	// by setting resourceVersion="0" we instruct the server to get the data from the memory cache and avoid contacting with the etcd.
 
 	disruptionBackedName := "cache-kube-api"
 	newConnectionTestName, reusedConnectionTestName := testNames("sig-api-machinery", disruptionBackedName)
-	newConnections, err := createAPIServerBackendSampler(adminRESTConfig, disruptionBackedName, "/api/v1/namespaces/default?resourceVersion=0", monitorapi.NewConnectionType)
+	newConnections, err := createAPIServerBackendSampler(adminRESTConfig, factory, disruptionci.KubeAPIServer, disruptionBackedName, "/api/v1/namespaces/default?resourceVersion=0", monitorapi.NewConnectionType)
 	if err != nil {
 		return nil, err
 	}
-	reusedConnections, err := createAPIServerBackendSampler(adminRESTConfig, disruptionBackedName, "/api/v1/namespaces/default?resourceVersion=0", monitorapi.ReusedConnectionType)
+	reusedConnections, err := createAPIServerBackendSampler(adminRESTConfig, factory, disruptionci.KubeAPIServer, disruptionBackedName, "/api/v1/namespaces/default?resourceVersion=0", monitorapi.ReusedConnectionType)
 	if err != nil {
 		return nil, err
 	}
@@ -76,14 +96,14 @@ func newDisruptionCheckerForKubeAPICached(adminRESTConfig *rest.Config) (*disrup
 	), nil
 }
 
-func newDisruptionCheckerForOpenshiftAPI(adminRESTConfig *rest.Config) (*disruptionlibrary.Availability, error) {
+func newDisruptionCheckerForOpenshiftAPI(adminRESTConfig *rest.Config, factory disruptionci.Factory) (*disruptionlibrary.Availability, error) {
 	disruptionBackedName := "openshift-api"
 	newConnectionTestName, reusedConnectionTestName := testNames("sig-api-machinery", disruptionBackedName)
-	newConnections, err := createAPIServerBackendSampler(adminRESTConfig, disruptionBackedName, "/apis/image.openshift.io/v1/namespaces/default/imagestreams", monitorapi.NewConnectionType)
+	newConnections, err := createAPIServerBackendSampler(adminRESTConfig, factory, disruptionci.OpenShiftAPIServer, disruptionBackedName, "/apis/image.openshift.io/v1/namespaces/default/imagestreams", monitorapi.NewConnectionType)
 	if err != nil {
 		return nil, err
 	}
-	reusedConnections, err := createAPIServerBackendSampler(adminRESTConfig, disruptionBackedName, "/apis/image.openshift.io/v1/namespaces/default/imagestreams", monitorapi.ReusedConnectionType)
+	reusedConnections, err := createAPIServerBackendSampler(adminRESTConfig, factory, disruptionci.OpenShiftAPIServer, disruptionBackedName, "/apis/image.openshift.io/v1/namespaces/default/imagestreams", monitorapi.ReusedConnectionType)
 	if err != nil {
 		return nil, err
 	}
@@ -93,16 +113,16 @@ func newDisruptionCheckerForOpenshiftAPI(adminRESTConfig *rest.Config) (*disrupt
 	), nil
 }
 
-func newDisruptionCheckerForOpenshiftAPICached(adminRESTConfig *rest.Config) (*disruptionlibrary.Availability, error) {
+func newDisruptionCheckerForOpenshiftAPICached(adminRESTConfig *rest.Config, factory disruptionci.Factory) (*disruptionlibrary.Availability, error) {
 	// by setting resourceVersion="0" we instruct the server to get the data from the memory cache and avoid contacting with the etcd.
 
 	disruptionBackedName := "cache-openshift-api"
 	newConnectionTestName, reusedConnectionTestName := testNames("sig-api-machinery", disruptionBackedName)
-	newConnections, err := createAPIServerBackendSampler(adminRESTConfig, disruptionBackedName, "/apis/image.openshift.io/v1/namespaces/default/imagestreams?resourceVersion=0", monitorapi.NewConnectionType)
+	newConnections, err := createAPIServerBackendSampler(adminRESTConfig, factory, disruptionci.OpenShiftAPIServer, disruptionBackedName, "/apis/image.openshift.io/v1/namespaces/default/imagestreams?resourceVersion=0", monitorapi.NewConnectionType)
 	if err != nil {
 		return nil, err
 	}
-	reusedConnections, err := createAPIServerBackendSampler(adminRESTConfig, disruptionBackedName, "/apis/image.openshift.io/v1/namespaces/default/imagestreams?resourceVersion=0", monitorapi.ReusedConnectionType)
+	reusedConnections, err := createAPIServerBackendSampler(adminRESTConfig, factory, disruptionci.OpenShiftAPIServer, disruptionBackedName, "/apis/image.openshift.io/v1/namespaces/default/imagestreams?resourceVersion=0", monitorapi.ReusedConnectionType)
 	if err != nil {
 		return nil, err
 	}
@@ -112,14 +132,14 @@ func newDisruptionCheckerForOpenshiftAPICached(adminRESTConfig *rest.Config) (*d
 	), nil
 }
 
-func newDisruptionCheckerForOAuthAPI(adminRESTConfig *rest.Config) (*disruptionlibrary.Availability, error) {
+func newDisruptionCheckerForOAuthAPI(adminRESTConfig *rest.Config, factory disruptionci.Factory) (*disruptionlibrary.Availability, error) {
 	disruptionBackedName := "oauth-api"
 	newConnectionTestName, reusedConnectionTestName := testNames("sig-api-machinery", disruptionBackedName)
-	newConnections, err := createAPIServerBackendSampler(adminRESTConfig, disruptionBackedName, "/apis/oauth.openshift.io/v1/oauthclients", monitorapi.NewConnectionType)
+	newConnections, err := createAPIServerBackendSampler(adminRESTConfig, factory, disruptionci.OAuthAPIServer, disruptionBackedName, "/apis/oauth.openshift.io/v1/oauthclients", monitorapi.NewConnectionType)
 	if err != nil {
 		return nil, err
 	}
-	reusedConnections, err := createAPIServerBackendSampler(adminRESTConfig, disruptionBackedName, "/apis/oauth.openshift.io/v1/oauthclients", monitorapi.ReusedConnectionType)
+	reusedConnections, err := createAPIServerBackendSampler(adminRESTConfig, factory, disruptionci.OAuthAPIServer, disruptionBackedName, "/apis/oauth.openshift.io/v1/oauthclients", monitorapi.ReusedConnectionType)
 	if err != nil {
 		return nil, err
 	}
@@ -129,16 +149,16 @@ func newDisruptionCheckerForOAuthAPI(adminRESTConfig *rest.Config) (*disruptionl
 	), nil
 }
 
-func newDisruptionCheckerForOAuthCached(adminRESTConfig *rest.Config) (*disruptionlibrary.Availability, error) {
+func newDisruptionCheckerForOAuthCached(adminRESTConfig *rest.Config, factory disruptionci.Factory) (*disruptionlibrary.Availability, error) {
 	// by setting resourceVersion="0" we instruct the server to get the data from the memory cache and avoid contacting with the etcd.
 
 	disruptionBackedName := "cache-oauth-api"
 	newConnectionTestName, reusedConnectionTestName := testNames("sig-api-machinery", disruptionBackedName)
-	newConnections, err := createAPIServerBackendSampler(adminRESTConfig, disruptionBackedName, "/apis/oauth.openshift.io/v1/oauthclients?resourceVersion=0", monitorapi.NewConnectionType)
+	newConnections, err := createAPIServerBackendSampler(adminRESTConfig, factory, disruptionci.OAuthAPIServer, disruptionBackedName, "/apis/oauth.openshift.io/v1/oauthclients?resourceVersion=0", monitorapi.NewConnectionType)
 	if err != nil {
 		return nil, err
 	}
-	reusedConnections, err := createAPIServerBackendSampler(adminRESTConfig, disruptionBackedName, "/apis/oauth.openshift.io/v1/oauthclients?resourceVersion=0", monitorapi.ReusedConnectionType)
+	reusedConnections, err := createAPIServerBackendSampler(adminRESTConfig, factory, disruptionci.OAuthAPIServer, disruptionBackedName, "/apis/oauth.openshift.io/v1/oauthclients?resourceVersion=0", monitorapi.ReusedConnectionType)
 	if err != nil {
 		return nil, err
 	}
@@ -177,36 +197,41 @@ func (w *availability) StartCollection(ctx context.Context, adminRESTConfig *res
 		return err
 	}
 
+	var factory disruptionci.Factory
+	if w.samplerParity {
+		factory = disruptionci.NewDisruptionTestFactory(adminRESTConfig, kubeClient)
+	}
+
 	var curr *disruptionlibrary.Availability
 
-	curr, err = newDisruptionCheckerForKubeAPI(adminRESTConfig)
+	curr, err = newDisruptionCheckerForKubeAPI(adminRESTConfig, factory)
 	if err != nil {
 		return err
 	}
 	w.disruptionCheckers = append(w.disruptionCheckers, curr)
-	curr, err = newDisruptionCheckerForKubeAPICached(adminRESTConfig)
+	curr, err = newDisruptionCheckerForKubeAPICached(adminRESTConfig, factory)
 	if err != nil {
 		return err
 	}
 	w.disruptionCheckers = append(w.disruptionCheckers, curr)
 
-	curr, err = newDisruptionCheckerForOpenshiftAPI(adminRESTConfig)
+	curr, err = newDisruptionCheckerForOpenshiftAPI(adminRESTConfig, factory)
 	if err != nil {
 		return err
 	}
 	w.disruptionCheckers = append(w.disruptionCheckers, curr)
-	curr, err = newDisruptionCheckerForOpenshiftAPICached(adminRESTConfig)
+	curr, err = newDisruptionCheckerForOpenshiftAPICached(adminRESTConfig, factory)
 	if err != nil {
 		return err
 	}
 	w.disruptionCheckers = append(w.disruptionCheckers, curr)
 
-	curr, err = newDisruptionCheckerForOAuthAPI(adminRESTConfig)
+	curr, err = newDisruptionCheckerForOAuthAPI(adminRESTConfig, factory)
 	if err != nil {
 		return err
 	}
 	w.disruptionCheckers = append(w.disruptionCheckers, curr)
-	curr, err = newDisruptionCheckerForOAuthCached(adminRESTConfig)
+	curr, err = newDisruptionCheckerForOAuthCached(adminRESTConfig, factory)
 	if err != nil {
 		return err
 	}
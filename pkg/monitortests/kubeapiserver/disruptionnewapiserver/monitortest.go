@@ -16,6 +16,13 @@ import (
 	exutil "github.com/openshift/origin/test/extended/util"
 )
 
+var (
+	_ monitortestframework.ClusterCollector    = &newAPIServerDisruptionChecker{}
+	_ monitortestframework.IntervalConstructor = &newAPIServerDisruptionChecker{}
+	_ monitortestframework.IntervalEvaluator   = &newAPIServerDisruptionChecker{}
+	_ monitortestframework.StorageWriter       = &newAPIServerDisruptionChecker{}
+)
+
 type newAPIServerDisruptionChecker struct {
 	adminRESTConfig    *rest.Config
 	notSupportedReason error
@@ -69,7 +76,3 @@ func (w *newAPIServerDisruptionChecker) EvaluateTestsFromConstructedIntervals(ct
 func (w *newAPIServerDisruptionChecker) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
 	return w.notSupportedReason
 }
-
-func (w *newAPIServerDisruptionChecker) Cleanup(ctx context.Context) error {
-	return nil
-}
@@ -33,6 +33,11 @@ const (
 	configMapTestName = "[sig-trt] ConfigMap count should not have grown significantly during upgrade"
 )
 
+var (
+	_ monitortestframework.ClusterCollector  = &resourceGrowthTests{}
+	_ monitortestframework.IntervalEvaluator = &resourceGrowthTests{}
+)
+
 type resourceGrowthTests struct {
 	adminRESTConfig *rest.Config
 	// PreUpgradeResourceCounts stores a map of resource type to a count of the number of
@@ -73,10 +78,6 @@ func (w *resourceGrowthTests) CollectData(ctx context.Context, storageDir string
 	return nil, nil, nil
 }
 
-func (*resourceGrowthTests) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
-	return nil, nil
-}
-
 func (w *resourceGrowthTests) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
 	junits := []*junitapi.JUnitTestCase{}
 	errs := []error{}
@@ -92,14 +93,6 @@ func (w *resourceGrowthTests) EvaluateTestsFromConstructedIntervals(ctx context.
 	return junits, utilerrors.NewAggregate(errs)
 }
 
-func (*resourceGrowthTests) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
-	return nil
-}
-
-func (*resourceGrowthTests) Cleanup(ctx context.Context) error {
-	return nil
-}
-
 // comparePostUpgradeResourceCountFromMetrics tests that some counts for certain resources we're most interested
 // in potentially leaking do not increase substantially during upgrade.
 // This is in response to a bug discovered where operators were leaking Secrets and ultimately taking down clusters.
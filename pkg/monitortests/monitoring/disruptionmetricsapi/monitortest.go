@@ -26,6 +26,14 @@ const (
 	metricsServerDeploymentName    = "metrics-server"
 )
 
+var (
+	_ monitortestframework.ClusterCollector    = &availability{}
+	_ monitortestframework.IntervalConstructor = &availability{}
+	_ monitortestframework.IntervalEvaluator   = &availability{}
+	_ monitortestframework.StorageWriter       = &availability{}
+	_ monitortestframework.Cleaner             = &availability{}
+)
+
 type availability struct {
 	disruptionChecker  *disruptionlibrary.Availability
 	notSupportedReason error
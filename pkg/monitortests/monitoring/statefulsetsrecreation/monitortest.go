@@ -28,6 +28,11 @@ const (
 
 var statefulsetsToCheck = []string{"prometheus-k8s", "alertmanager-main"}
 
+var (
+	_ monitortestframework.ClusterCollector  = &statefulsetsChecker{}
+	_ monitortestframework.IntervalEvaluator = &statefulsetsChecker{}
+)
+
 type statefulsetsChecker struct {
 	statefulsetsUID    map[string]string
 	kubeClient         kubernetes.Interface
@@ -81,15 +86,6 @@ func (sc *statefulsetsChecker) CollectData(
 	return nil, nil, nil
 }
 
-func (sc *statefulsetsChecker) ConstructComputedIntervals(
-	ctx context.Context,
-	startingIntervals monitorapi.Intervals,
-	recordedResources monitorapi.ResourcesMap,
-	beginning, end time.Time,
-) (monitorapi.Intervals, error) {
-	return nil, nil
-}
-
 func (sc *statefulsetsChecker) EvaluateTestsFromConstructedIntervals(
 	ctx context.Context,
 	finalIntervals monitorapi.Intervals,
@@ -133,19 +129,6 @@ func (sc *statefulsetsChecker) EvaluateTestsFromConstructedIntervals(
 	return []*junitapi.JUnitTestCase{{Name: testName}}, nil
 }
 
-func (sc *statefulsetsChecker) WriteContentToStorage(
-	ctx context.Context,
-	storageDir, timeSuffix string,
-	finalIntervals monitorapi.Intervals,
-	finalResourceState monitorapi.ResourcesMap,
-) error {
-	return nil
-}
-
-func (sc *statefulsetsChecker) Cleanup(ctx context.Context) error {
-	return nil
-}
-
 func (sc *statefulsetsChecker) getStatefulsetsUID(ctx context.Context) (map[string]string, error) {
 	statefulsetsUID := make(map[string]string)
 	var failures []error
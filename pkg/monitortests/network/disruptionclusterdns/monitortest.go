@@ -0,0 +1,154 @@
+package disruptionclusterdns
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitortestframework"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	backendsampler "github.com/openshift/origin/pkg/disruption/backend/sampler"
+	disruptionci "github.com/openshift/origin/pkg/disruption/ci"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/disruptionlibrary"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	dnsNamespace   = "openshift-dns"
+	dnsServiceName = "dns-default"
+	dnsPortName    = "dns"
+	// resolvedName always exists, it is fully qualified so the search domains of the test process are not tried.
+	resolvedName = "kubernetes.default.svc.cluster.local."
+
+	probeTimeout = 5 * time.Second
+)
+
+var (
+	_ monitortestframework.ClusterCollector    = &availability{}
+	_ monitortestframework.IntervalConstructor = &availability{}
+	_ monitortestframework.IntervalEvaluator   = &availability{}
+	_ monitortestframework.StorageWriter       = &availability{}
+	_ monitortestframework.Cleaner             = &availability{}
+)
+
+type availability struct {
+	disruptionChecker  *disruptionlibrary.Availability
+	notSupportedReason error
+}
+
+// NewAvailabilityInvariant samples cluster DNS by resolving the kubernetes service through the cluster DNS service.
+// Every lookup goes over a new UDP socket, so there is no reused connection to sample.  The service network is only
+// reachable when openshift-tests runs inside the cluster, elsewhere it is not supported.
+func NewAvailabilityInvariant() monitortestframework.MonitorTest {
+	return &availability{}
+}
+
+func (w *availability) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	kubeClient, err := kubernetes.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+
+	service, err := kubeClient.CoreV1().Services(dnsNamespace).Get(ctx, dnsServiceName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{
+			Reason: fmt.Sprintf("service %s/%s not present", dnsNamespace, dnsServiceName),
+		}
+		return w.notSupportedReason
+	}
+	if err != nil {
+		return err
+	}
+	var port int32
+	for _, servicePort := range service.Spec.Ports {
+		if servicePort.Name == dnsPortName {
+			port = servicePort.Port
+		}
+	}
+	if port == 0 {
+		return fmt.Errorf("service %s/%s has no port named %q", dnsNamespace, dnsServiceName, dnsPortName)
+	}
+	server := net.JoinHostPort(service.Spec.ClusterIP, strconv.Itoa(int(port)))
+
+	dnsProber := backendsampler.NewDNSProber(server, resolvedName)
+	preflightCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+	if _, err := dnsProber.Probe(preflightCtx, 0); err != nil {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{
+			Reason: fmt.Sprintf("cluster DNS %s is not reachable from the test process: %v", server, err),
+		}
+		return w.notSupportedReason
+	}
+
+	newConnections, err := disruptionci.NewProbeSampler(disruptionci.TestConfiguration{
+		TestDescriptor: disruptionci.TestDescriptor{
+			TargetServer:     disruptionci.ClusterDNS,
+			LoadBalancerType: backend.ServiceNetworkType,
+			ConnectionType:   monitorapi.NewConnectionType,
+			Protocol:         backend.ProtocolDNS,
+		},
+		Timeout:        probeTimeout,
+		SampleInterval: time.Second,
+	}, dnsProber)
+	if err != nil {
+		return err
+	}
+
+	disruptionBackedName := "cluster-dns"
+	newConnectionTestName := fmt.Sprintf("[sig-network] disruption/%s connection/new should be available throughout the test", disruptionBackedName)
+	w.disruptionChecker = disruptionlibrary.NewAvailabilityInvariant(
+		newConnectionTestName, "",
+		newConnections, nil,
+	)
+
+	if err := w.disruptionChecker.StartCollection(ctx, adminRESTConfig, recorder); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (w *availability) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, nil, w.notSupportedReason
+	}
+	// we failed and indicated it during setup.
+	if w.disruptionChecker == nil {
+		return nil, nil, nil
+	}
+
+	return w.disruptionChecker.CollectData(ctx)
+}
+
+func (w *availability) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, w.notSupportedReason
+}
+
+func (w *availability) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	// we failed and indicated it during setup.
+	if w.disruptionChecker == nil {
+		return nil, nil
+	}
+
+	return w.disruptionChecker.EvaluateTestsFromConstructedIntervals(ctx, finalIntervals)
+}
+
+func (w *availability) WriteContentToStorage(ctx context.Context, storageDir string, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return w.notSupportedReason
+}
+
+func (w *availability) Cleanup(ctx context.Context) error {
+	return w.notSupportedReason
+}
@@ -27,6 +27,11 @@ import (
 	"k8s.io/client-go/rest"
 )
 
+var (
+	_ monitortestframework.ClusterCollector  = &availability{}
+	_ monitortestframework.IntervalEvaluator = &availability{}
+)
+
 type availability struct {
 	disruptionCheckers []*disruptionlibrary.Availability
 	suppressJunit      bool
@@ -132,10 +137,6 @@ func (w *availability) CollectData(ctx context.Context, storageDir string, begin
 	return intervals, junits, utilerrors.NewAggregate(errs)
 }
 
-func (*availability) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
-	return nil, nil
-}
-
 func (w *availability) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
 	if w.suppressJunit {
 		return nil, nil
@@ -160,14 +161,6 @@ func (w *availability) EvaluateTestsFromConstructedIntervals(ctx context.Context
 	return junits, utilerrors.NewAggregate(errs)
 }
 
-func (*availability) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
-	return nil
-}
-
-func (w *availability) Cleanup(ctx context.Context) error {
-	return nil
-}
-
 const (
 	oauthRouteNamespace = "openshift-authentication"
 	oauthRouteName      = "oauth-openshift"
@@ -73,6 +73,14 @@ func init() {
 	hostNetworkTargetService = resourceread.ReadServiceV1OrDie(yamlOrDie("host-network-target-service.yaml"))
 }
 
+var (
+	_ monitortestframework.ClusterCollector    = &podNetworkAvalibility{}
+	_ monitortestframework.IntervalConstructor = &podNetworkAvalibility{}
+	_ monitortestframework.IntervalEvaluator   = &podNetworkAvalibility{}
+	_ monitortestframework.StorageWriter       = &podNetworkAvalibility{}
+	_ monitortestframework.Cleaner             = &podNetworkAvalibility{}
+)
+
 type podNetworkAvalibility struct {
 	payloadImagePullSpec string
 	notSupportedReason   error
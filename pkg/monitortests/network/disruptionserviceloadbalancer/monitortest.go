@@ -54,6 +54,14 @@ func init() {
 	namespace = resourceread.ReadNamespaceV1OrDie(namespaceYaml)
 }
 
+var (
+	_ monitortestframework.ClusterCollector    = &availability{}
+	_ monitortestframework.IntervalConstructor = &availability{}
+	_ monitortestframework.IntervalEvaluator   = &availability{}
+	_ monitortestframework.StorageWriter       = &availability{}
+	_ monitortestframework.Cleaner             = &availability{}
+)
+
 type availability struct {
 	namespaceName      string
 	notSupportedReason error
@@ -13,6 +13,8 @@ import (
 	"k8s.io/client-go/rest"
 )
 
+var _ monitortestframework.ClusterCollector = &kubeletLogCollector{}
+
 type kubeletLogCollector struct {
 	adminRESTConfig *rest.Config
 }
@@ -43,20 +45,3 @@ func (w *kubeletLogCollector) CollectData(ctx context.Context, storageDir string
 	intervals, err := intervalsFromNodeLogs(ctx, kubeClient, beginning, end)
 	return intervals, nil, err
 }
-
-func (*kubeletLogCollector) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
-	return nil, nil
-}
-
-func (*kubeletLogCollector) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
-	return nil, nil
-}
-
-func (*kubeletLogCollector) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
-	return nil
-}
-
-func (*kubeletLogCollector) Cleanup(ctx context.Context) error {
-	// TODO wire up the start to a context we can kill here
-	return nil
-}
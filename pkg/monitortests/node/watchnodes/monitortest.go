@@ -12,6 +12,11 @@ import (
 	"k8s.io/client-go/rest"
 )
 
+var (
+	_ monitortestframework.ClusterCollector    = &nodeWatcher{}
+	_ monitortestframework.IntervalConstructor = &nodeWatcher{}
+)
+
 type nodeWatcher struct {
 }
 
@@ -40,16 +45,3 @@ func (*nodeWatcher) ConstructComputedIntervals(ctx context.Context, startingInte
 
 	return constructedIntervals, nil
 }
-
-func (*nodeWatcher) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
-	return nil, nil
-}
-
-func (*nodeWatcher) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
-	return nil
-}
-
-func (*nodeWatcher) Cleanup(ctx context.Context) error {
-	// TODO wire up the start to a context we can kill here
-	return nil
-}
@@ -180,6 +180,7 @@ func startNodeMonitoring(ctx context.Context, m monitorapi.RecorderWriter, clien
 				i := monitorapi.NewInterval(monitorapi.SourceNodeMonitor, monitorapi.Warning).
 					Locator(monitorapi.NewLocator().NodeFromName(node.Name)).
 					Message(monitorapi.NewMessage().
+						Reason(monitorapi.NodeDeletedReason).
 						WithAnnotations(map[monitorapi.AnnotationKey]string{
 							monitorapi.AnnotationRoles: nodeRoles(node),
 						}).
@@ -12,6 +12,11 @@ import (
 	"k8s.io/client-go/rest"
 )
 
+var (
+	_ monitortestframework.ClusterCollector    = &podWatcher{}
+	_ monitortestframework.IntervalConstructor = &podWatcher{}
+)
+
 type podWatcher struct {
 }
 
@@ -42,16 +47,3 @@ func (*podWatcher) ConstructComputedIntervals(ctx context.Context, startingInter
 
 	return constructedIntervals, nil
 }
-
-func (*podWatcher) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
-	return nil, nil
-}
-
-func (*podWatcher) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
-	return nil
-}
-
-func (*podWatcher) Cleanup(ctx context.Context) error {
-	// TODO wire up the start to a context we can kill here
-	return nil
-}
@@ -16,6 +16,8 @@ import (
 	"k8s.io/client-go/rest"
 )
 
+var _ monitortestframework.ClusterCollector = &additionalEventsCollector{}
+
 type additionalEventsCollector struct {
 }
 
@@ -66,20 +68,3 @@ func (w *additionalEventsCollector) CollectData(ctx context.Context, storageDir
 
 	return additionIntervals, nil, err
 }
-
-func (*additionalEventsCollector) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
-	return nil, nil
-}
-
-func (*additionalEventsCollector) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
-	return nil, nil
-}
-
-func (*additionalEventsCollector) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
-	return nil
-}
-
-func (*additionalEventsCollector) Cleanup(ctx context.Context) error {
-	// TODO wire up the start to a context we can kill here
-	return nil
-}
@@ -1,7 +1,6 @@
 package alertanalyzer
 
 import (
-	"reflect"
 	"testing"
 	"time"
 
@@ -9,134 +8,6 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func Test_nonOverlappingBlackoutWindowsFromEvents(t *testing.T) {
-	type args struct {
-		blackoutWindows []monitorapi.Interval
-	}
-	tests := []struct {
-		name string
-		args args
-		want []blackoutWindow
-	}{
-		{
-			name: "no-overlap",
-			args: args{
-				blackoutWindows: []monitorapi.Interval{
-					{
-						From: timeOrDie("2022-03-22T19:00:00Z"),
-						To:   timeOrDie("2022-03-22T19:10:00Z"),
-					},
-					{
-						From: timeOrDie("2022-03-22T19:15:00Z"),
-						To:   timeOrDie("2022-03-22T19:20:00Z"),
-					},
-				},
-			},
-			want: []blackoutWindow{
-				{
-					From: timeOrDie("2022-03-22T19:00:00Z"),
-					To:   timeOrDie("2022-03-22T19:10:00Z"),
-				},
-				{
-					From: timeOrDie("2022-03-22T19:15:00Z"),
-					To:   timeOrDie("2022-03-22T19:20:00Z"),
-				},
-			},
-		},
-		{
-			name: "fully-contained",
-			args: args{
-				blackoutWindows: []monitorapi.Interval{
-					{
-						From: timeOrDie("2022-03-22T19:00:00Z"),
-						To:   timeOrDie("2022-03-22T19:10:00Z"),
-					},
-					{
-						From: timeOrDie("2022-03-22T18:55:00Z"),
-						To:   timeOrDie("2022-03-22T19:20:00Z"),
-					},
-				},
-			},
-			want: []blackoutWindow{
-				{
-					From: timeOrDie("2022-03-22T18:55:00Z"),
-					To:   timeOrDie("2022-03-22T19:20:00Z"),
-				},
-			},
-		},
-		{
-			name: "fully-contained-reverse",
-			args: args{
-				blackoutWindows: []monitorapi.Interval{
-					{
-						From: timeOrDie("2022-03-22T18:55:00Z"),
-						To:   timeOrDie("2022-03-22T19:20:00Z"),
-					},
-					{
-						From: timeOrDie("2022-03-22T19:00:00Z"),
-						To:   timeOrDie("2022-03-22T19:10:00Z"),
-					},
-				},
-			},
-			want: []blackoutWindow{
-				{
-					From: timeOrDie("2022-03-22T18:55:00Z"),
-					To:   timeOrDie("2022-03-22T19:20:00Z"),
-				},
-			},
-		},
-		{
-			name: "overlap-beginning",
-			args: args{
-				blackoutWindows: []monitorapi.Interval{
-					{
-						From: timeOrDie("2022-03-22T19:00:00Z"),
-						To:   timeOrDie("2022-03-22T19:10:00Z"),
-					},
-					{
-						From: timeOrDie("2022-03-22T18:55:00Z"),
-						To:   timeOrDie("2022-03-22T19:05:00Z"),
-					},
-				},
-			},
-			want: []blackoutWindow{
-				{
-					From: timeOrDie("2022-03-22T18:55:00Z"),
-					To:   timeOrDie("2022-03-22T19:10:00Z"),
-				},
-			},
-		},
-		{
-			name: "overlap-end",
-			args: args{
-				blackoutWindows: []monitorapi.Interval{
-					{
-						From: timeOrDie("2022-03-22T19:00:00Z"),
-						To:   timeOrDie("2022-03-22T19:10:00Z"),
-					},
-					{
-						From: timeOrDie("2022-03-22T19:05:00Z"),
-						To:   timeOrDie("2022-03-22T19:20:00Z"),
-					},
-				},
-			},
-			want: []blackoutWindow{
-				{
-					From: timeOrDie("2022-03-22T19:00:00Z"),
-					To:   timeOrDie("2022-03-22T19:20:00Z"),
-				},
-			},
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if got := nonOverlappingBlackoutWindowsFromEvents(tt.args.blackoutWindows); !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("nonOverlappingBlackoutWindowsFromEvents() = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
 func timeOrDie(in string) time.Time {
 	startTime, err := time.Parse(time.RFC3339, in)
 	if err != nil {
@@ -11,6 +11,11 @@ import (
 	"k8s.io/client-go/rest"
 )
 
+var (
+	_ monitortestframework.ClusterCollector = &alertSummarySerializer{}
+	_ monitortestframework.StorageWriter    = &alertSummarySerializer{}
+)
+
 type alertSummarySerializer struct {
 	adminRESTConfig *rest.Config
 }
@@ -29,19 +34,6 @@ func (w *alertSummarySerializer) CollectData(ctx context.Context, storageDir str
 	return intervals, nil, err
 }
 
-func (*alertSummarySerializer) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
-	return nil, nil
-}
-
-func (*alertSummarySerializer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
-	return nil, nil
-}
-
 func (*alertSummarySerializer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
 	return writeAlertDataForJobRun(storageDir, nil, finalIntervals, timeSuffix)
 }
-
-func (*alertSummarySerializer) Cleanup(ctx context.Context) error {
-	// TODO wire up the start to a context we can kill here
-	return nil
-}
@@ -0,0 +1,237 @@
+package backendlatencyanalyzer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/openshift/origin/pkg/dataloader"
+	"github.com/openshift/origin/pkg/monitor/backenddisruption"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/allowedbackendlatency"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	"k8s.io/client-go/rest"
+)
+
+var (
+	_ monitortestframework.ClusterCollector  = &backendLatencyAnalyzer{}
+	_ monitortestframework.IntervalEvaluator = &backendLatencyAnalyzer{}
+	_ monitortestframework.StorageWriter     = &backendLatencyAnalyzer{}
+)
+
+type backendLatencyAnalyzer struct {
+	// store the rest config so we can get the JobType at the end of the run
+	adminRESTConfig *rest.Config
+}
+
+// NewAnalyzer compares the P99 latency of every backend sampler that summarizes its latency against historical data,
+// and writes a latency histogram per backend as autodl data.
+// The comparison is inactive until allowedbackendlatency has historical data: its query_results.json is seeded from
+// the BackendLatency table once enough job runs have uploaded their histograms, until then every latency test is
+// skipped and only the autodl data is written.
+func NewAnalyzer() monitortestframework.MonitorTest {
+	return &backendLatencyAnalyzer{}
+}
+
+// latencySummary is the latency of a backend over the whole run.
+type latencySummary struct {
+	backendName   string
+	samples       int64
+	p50, p95, p99 time.Duration
+	histogram     backenddisruption.LatencyHistogram
+}
+
+func (w *backendLatencyAnalyzer) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	w.adminRESTConfig = adminRESTConfig
+	return nil
+}
+
+func (w *backendLatencyAnalyzer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	return nil, nil, nil
+}
+
+func (w *backendLatencyAnalyzer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	summaries, err := computeLatencySummaries(finalIntervals)
+	if err != nil {
+		return nil, err
+	}
+	if len(summaries) == 0 {
+		return nil, nil
+	}
+	jobType, err := platformidentification.GetJobType(ctx, w.adminRESTConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []*junitapi.JUnitTestCase{}
+	for _, summary := range summaries {
+		allowed, details, err := allowedbackendlatency.GetAllowedLatency(summary.backendName, *jobType)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get allowed latency for %s: %w", summary.backendName, err)
+		}
+		ret = append(ret, createLatencyJunit(summary, allowed, details, jobType))
+	}
+	return ret, nil
+}
+
+func (w *backendLatencyAnalyzer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	summaries, err := computeLatencySummaries(finalIntervals)
+	if err != nil {
+		return err
+	}
+	if len(summaries) == 0 {
+		return nil
+	}
+	fileName := filepath.Join(storageDir, fmt.Sprintf("backend-latency%s-%s", timeSuffix, dataloader.AutoDataLoaderSuffix))
+	return dataloader.WriteDataFile(fileName, latencyDataFile(summaries))
+}
+
+func latencyTestName(backendName string) string {
+	return fmt.Sprintf("[sig-trt] backend-latency/%s P99 latency should not regress from historical data", backendName)
+}
+
+// computeLatencySummaries reads the latency summaries recorded by the backend samplers, sorted by backend.  A backend
+// sampled from several places, such as the in-cluster pollers, has its histograms added up and keeps the highest
+// percentiles.
+func computeLatencySummaries(intervals monitorapi.Intervals) ([]*latencySummary, error) {
+	byBackend := map[string]*latencySummary{}
+	for _, interval := range intervals {
+		if interval.Source != monitorapi.SourceBackendLatency || interval.Message.Reason != monitorapi.LatencySummaryEventReason {
+			continue
+		}
+		backendName := monitorapi.BackendDisruptionNameFromLocator(interval.Locator)
+		annotations := interval.Message.Annotations
+
+		samples, err := strconv.ParseInt(annotations[monitorapi.AnnotationCount], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid latency sample count for %s: %w", backendName, err)
+		}
+		histogram, err := backenddisruption.ParseLatencyHistogram(annotations[monitorapi.AnnotationHistogram])
+		if err != nil {
+			return nil, fmt.Errorf("invalid latency histogram for %s: %w", backendName, err)
+		}
+		percentiles := map[monitorapi.AnnotationKey]time.Duration{}
+		for _, key := range []monitorapi.AnnotationKey{monitorapi.AnnotationLatencyP50, monitorapi.AnnotationLatencyP95, monitorapi.AnnotationLatencyP99} {
+			if percentiles[key], err = time.ParseDuration(annotations[key]); err != nil {
+				return nil, fmt.Errorf("invalid %s for %s: %w", key, backendName, err)
+			}
+		}
+
+		summary, ok := byBackend[backendName]
+		if !ok {
+			summary = &latencySummary{backendName: backendName, histogram: backenddisruption.NewLatencyHistogram()}
+			byBackend[backendName] = summary
+		}
+		summary.samples += samples
+		for i := range histogram {
+			summary.histogram[i] += histogram[i]
+		}
+		summary.p50 = maxDuration(summary.p50, percentiles[monitorapi.AnnotationLatencyP50])
+		summary.p95 = maxDuration(summary.p95, percentiles[monitorapi.AnnotationLatencyP95])
+		summary.p99 = maxDuration(summary.p99, percentiles[monitorapi.AnnotationLatencyP99])
+	}
+
+	ret := []*latencySummary{}
+	for _, summary := range byBackend {
+		ret = append(ret, summary)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].backendName < ret[j].backendName })
+	return ret, nil
+}
+
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func createLatencyJunit(summary *latencySummary, allowedLatency *time.Duration, latencyDetails string, jobType *platformidentification.JobType) *junitapi.JUnitTestCase {
+	testName := latencyTestName(summary.backendName)
+	if jobType.Platform == "" {
+		return &junitapi.JUnitTestCase{
+			Name: testName,
+			SkipMessage: &junitapi.SkipMessage{
+				Message: "Unknown platform, skipping latency testing",
+			},
+		}
+	}
+	// there is no entry in query_results.json, nor a valid fallback, we do not have
+	// enough job runs to tell what a regression is.
+	if allowedLatency == nil {
+		return &junitapi.JUnitTestCase{
+			Name: testName,
+			SkipMessage: &junitapi.SkipMessage{
+				Message: "No historical data to calculate allowed latency, the latency check is inactive until allowedbackendlatency/query_results.json has data for this backend",
+			},
+		}
+	}
+
+	// Allow grace of 250ms or 20%, latency varies a lot from one run to the next.
+	allowed := *allowedLatency
+	grace := time.Duration(math.Round(float64(allowed) * 0.2))
+	if grace < 250*time.Millisecond {
+		grace = 250 * time.Millisecond
+	}
+	finalAllowed := (allowed + grace).Round(time.Millisecond)
+	if summary.p99 <= finalAllowed {
+		return &junitapi.JUnitTestCase{
+			Name: testName,
+		}
+	}
+
+	failureMessage := fmt.Sprintf("P99 latency of %s was %s (maxAllowed=%s) over %d samples, p50=%s p95=%s:\n"+
+		"P99 from historical data for similar jobs over past 3 weeks: %s %s\nlatency histogram: %s",
+		summary.backendName, summary.p99, finalAllowed, summary.samples, summary.p50, summary.p95,
+		allowed, latencyDetails, summary.histogram)
+	return &junitapi.JUnitTestCase{
+		Name: testName,
+		FailureOutput: &junitapi.FailureOutput{
+			Output: failureMessage,
+		},
+		SystemOut: failureMessage,
+	}
+}
+
+// latencyDataFile has a row per latency bucket of every backend, the percentiles of the backend are repeated in each.
+func latencyDataFile(summaries []*latencySummary) dataloader.DataFile {
+	rows := []map[string]string{}
+	for _, summary := range summaries {
+		for i, count := range summary.histogram {
+			row := map[string]string{
+				"BackendName": summary.backendName,
+				"Bucket":      backenddisruption.LatencyBucketName(i),
+				"Count":       strconv.FormatInt(count, 10),
+				"Samples":     strconv.FormatInt(summary.samples, 10),
+				"P50Seconds":  strconv.FormatFloat(summary.p50.Seconds(), 'f', 3, 64),
+				"P95Seconds":  strconv.FormatFloat(summary.p95.Seconds(), 'f', 3, 64),
+				"P99Seconds":  strconv.FormatFloat(summary.p99.Seconds(), 'f', 3, 64),
+			}
+			// the last bucket has no upper bound
+			if i < len(backenddisruption.LatencyBuckets) {
+				row["UpperBoundSeconds"] = strconv.FormatFloat(backenddisruption.LatencyBuckets[i].Seconds(), 'f', 3, 64)
+			}
+			rows = append(rows, row)
+		}
+	}
+	return dataloader.DataFile{
+		TableName: "BackendLatency",
+		Schema: map[string]dataloader.DataType{
+			"BackendName":       dataloader.DataTypeString,
+			"Bucket":            dataloader.DataTypeString,
+			"UpperBoundSeconds": dataloader.DataTypeFloat64,
+			"Count":             dataloader.DataTypeInteger,
+			"Samples":           dataloader.DataTypeInteger,
+			"P50Seconds":        dataloader.DataTypeFloat64,
+			"P95Seconds":        dataloader.DataTypeFloat64,
+			"P99Seconds":        dataloader.DataTypeFloat64,
+		},
+		Rows: rows,
+	}
+}
@@ -0,0 +1,70 @@
+package backendlatencyanalyzer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/backenddisruption"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func latencySummaryInterval(backendName string, latencies ...time.Duration) monitorapi.Interval {
+	now := time.Now()
+	return monitorapi.NewInterval(monitorapi.SourceBackendLatency, monitorapi.Info).
+		Locator(monitorapi.NewLocator().Disruption(backendName, "kube-api-http2-internal-lb", "internal-lb", "http2", "kube-api", monitorapi.NewConnectionType)).
+		Message(backenddisruption.LatencySummaryMessage(backendName, latencies)).
+		Build(now.Add(-time.Hour), now)
+}
+
+func TestComputeLatencySummaries(t *testing.T) {
+	summaries, err := computeLatencySummaries(monitorapi.Intervals{
+		latencySummaryInterval("openshift-api-new-connections", 10*time.Millisecond),
+		latencySummaryInterval("kube-api-new-connections", 10*time.Millisecond, 20*time.Millisecond, 300*time.Millisecond),
+		// a second poller of the same backend
+		latencySummaryInterval("kube-api-new-connections", 2*time.Second),
+	})
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+
+	kubeAPI := summaries[0]
+	assert.Equal(t, "kube-api-new-connections", kubeAPI.backendName)
+	assert.Equal(t, int64(4), kubeAPI.samples)
+	assert.Equal(t, 2*time.Second, kubeAPI.p99, "the highest P99 is kept")
+	assert.Equal(t, "50ms=2 100ms=0 250ms=0 500ms=1 1s=0 2.5s=1 5s=0 10s=0 +Inf=0", kubeAPI.histogram.String())
+	assert.Equal(t, "openshift-api-new-connections", summaries[1].backendName)
+
+	dataFile := latencyDataFile(summaries)
+	require.Len(t, dataFile.Rows, 2*len(backenddisruption.LatencyBuckets)+2)
+	assert.Equal(t, map[string]string{
+		"BackendName":       "kube-api-new-connections",
+		"Bucket":            "50ms",
+		"UpperBoundSeconds": "0.050",
+		"Count":             "2",
+		"Samples":           "4",
+		"P50Seconds":        "2.000",
+		"P95Seconds":        "2.000",
+		"P99Seconds":        "2.000",
+	}, dataFile.Rows[0])
+	assert.NotContains(t, dataFile.Rows[len(backenddisruption.LatencyBuckets)], "UpperBoundSeconds")
+}
+
+func TestCreateLatencyJunit(t *testing.T) {
+	jobType := &platformidentification.JobType{Platform: "aws"}
+	summary := &latencySummary{backendName: "kube-api-new-connections", p99: 1100 * time.Millisecond, histogram: backenddisruption.NewLatencyHistogram()}
+
+	allowed := time.Second
+	junit := createLatencyJunit(summary, &allowed, "", jobType)
+	assert.Nil(t, junit.FailureOutput, "within 20%% of the historical P99")
+
+	allowed = 500 * time.Millisecond
+	junit = createLatencyJunit(summary, &allowed, "", jobType)
+	require.NotNil(t, junit.FailureOutput)
+	assert.True(t, strings.HasPrefix(junit.FailureOutput.Output, "P99 latency of kube-api-new-connections was 1.1s (maxAllowed=750ms)"), junit.FailureOutput.Output)
+
+	junit = createLatencyJunit(summary, nil, "", jobType)
+	require.NotNil(t, junit.SkipMessage, "no historical data")
+}
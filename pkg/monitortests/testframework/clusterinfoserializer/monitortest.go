@@ -19,6 +19,11 @@ import (
 	"k8s.io/client-go/rest"
 )
 
+var (
+	_ monitortestframework.ClusterCollector = &clusterInfoSerializer{}
+	_ monitortestframework.StorageWriter    = &clusterInfoSerializer{}
+)
+
 type clusterInfoSerializer struct {
 	adminRESTConfig *rest.Config
 }
@@ -36,14 +41,6 @@ func (w *clusterInfoSerializer) CollectData(ctx context.Context, storageDir stri
 	return nil, nil, nil
 }
 
-func (*clusterInfoSerializer) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
-	return nil, nil
-}
-
-func (*clusterInfoSerializer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
-	return nil, nil
-}
-
 func (w *clusterInfoSerializer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
 	return writeClusterData(
 		filepath.Join(storageDir, fmt.Sprintf("cluster-data%s.json", timeSuffix)),
@@ -51,11 +48,6 @@ func (w *clusterInfoSerializer) WriteContentToStorage(ctx context.Context, stora
 	)
 }
 
-func (*clusterInfoSerializer) Cleanup(ctx context.Context) error {
-	// TODO wire up the start to a context we can kill here
-	return nil
-}
-
 func writeClusterData(filename string, clusterData platformidentification.ClusterData) error {
 	jsonContent, err := json.MarshalIndent(clusterData, "", "    ")
 	if err != nil {
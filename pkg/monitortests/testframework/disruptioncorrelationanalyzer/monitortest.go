@@ -0,0 +1,46 @@
+package disruptioncorrelationanalyzer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/disruptioncorrelation"
+)
+
+var _ monitortestframework.StorageWriter = &disruptionCorrelationAnalyzer{}
+
+type disruptionCorrelationAnalyzer struct {
+	window time.Duration
+}
+
+// NewAnalyzer writes, for every disruption, the intervals from other sources that overlap it or are within window of
+// it, ranked by how likely they are to explain it.
+func NewAnalyzer(window time.Duration) monitortestframework.MonitorTest {
+	return &disruptionCorrelationAnalyzer{
+		window: window,
+	}
+}
+
+// correlationReport is the content of disruption-correlations<timeSuffix>.json.
+type correlationReport struct {
+	Window       string                              `json:"window"`
+	Correlations []disruptioncorrelation.Correlation `json:"correlations"`
+}
+
+func (w *disruptionCorrelationAnalyzer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	report := correlationReport{
+		Window:       w.window.String(),
+		Correlations: disruptioncorrelation.Correlate(finalIntervals, w.window, disruptioncorrelation.DefaultMaxCandidates),
+	}
+	content, err := json.MarshalIndent(report, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(storageDir, fmt.Sprintf("disruption-correlations%s.json", timeSuffix)), content, 0644)
+}
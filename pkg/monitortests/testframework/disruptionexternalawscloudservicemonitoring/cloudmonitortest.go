@@ -29,6 +29,14 @@ const (
 	externalServiceURL = "http://trt-openshift-tests-endpoint-lb-1161093811.us-east-1.elb.amazonaws.com/health"
 )
 
+var (
+	_ monitortestframework.ClusterCollector    = &cloudAvailability{}
+	_ monitortestframework.IntervalConstructor = &cloudAvailability{}
+	_ monitortestframework.IntervalEvaluator   = &cloudAvailability{}
+	_ monitortestframework.StorageWriter       = &cloudAvailability{}
+	_ monitortestframework.Cleaner             = &cloudAvailability{}
+)
+
 type cloudAvailability struct {
 	disruptionChecker  *disruptionlibrary.Availability
 	notSupportedReason error
@@ -29,6 +29,14 @@ const (
 	externalServiceURL = "http://20.127.186.25/health"
 )
 
+var (
+	_ monitortestframework.ClusterCollector    = &cloudAvailability{}
+	_ monitortestframework.IntervalConstructor = &cloudAvailability{}
+	_ monitortestframework.IntervalEvaluator   = &cloudAvailability{}
+	_ monitortestframework.StorageWriter       = &cloudAvailability{}
+	_ monitortestframework.Cleaner             = &cloudAvailability{}
+)
+
 type cloudAvailability struct {
 	disruptionChecker  *disruptionlibrary.Availability
 	notSupportedReason error
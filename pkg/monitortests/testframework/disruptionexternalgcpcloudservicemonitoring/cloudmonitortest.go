@@ -32,6 +32,14 @@ const (
 	externalServiceURL = "http://35.212.33.188/health"
 )
 
+var (
+	_ monitortestframework.ClusterCollector    = &cloudAvailability{}
+	_ monitortestframework.IntervalConstructor = &cloudAvailability{}
+	_ monitortestframework.IntervalEvaluator   = &cloudAvailability{}
+	_ monitortestframework.StorageWriter       = &cloudAvailability{}
+	_ monitortestframework.Cleaner             = &cloudAvailability{}
+)
+
 type cloudAvailability struct {
 	disruptionChecker  *disruptionlibrary.Availability
 	notSupportedReason error
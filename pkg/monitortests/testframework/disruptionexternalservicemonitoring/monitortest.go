@@ -22,6 +22,14 @@ const (
 	externalServiceURL = "http://static.redhat.com/test/rhel-networkmanager.txt"
 )
 
+var (
+	_ monitortestframework.ClusterCollector    = &availability{}
+	_ monitortestframework.IntervalConstructor = &availability{}
+	_ monitortestframework.IntervalEvaluator   = &availability{}
+	_ monitortestframework.StorageWriter       = &availability{}
+	_ monitortestframework.Cleaner             = &availability{}
+)
+
 type availability struct {
 	disruptionChecker  *disruptionlibrary.Availability
 	notSupportedReason error
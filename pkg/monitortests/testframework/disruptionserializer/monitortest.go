@@ -11,11 +11,11 @@ import (
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	"github.com/openshift/origin/pkg/monitortestframework"
-	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/rest"
 )
 
+var _ monitortestframework.StorageWriter = &disruptionSummarySerializer{}
+
 type disruptionSummarySerializer struct {
 }
 
@@ -23,32 +23,11 @@ func NewDisruptionSummarySerializer() monitortestframework.MonitorTest {
 	return &disruptionSummarySerializer{}
 }
 
-func (w *disruptionSummarySerializer) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
-	return nil
-}
-
-func (w *disruptionSummarySerializer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
-	return nil, nil, nil
-}
-
-func (*disruptionSummarySerializer) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
-	return nil, nil
-}
-
-func (*disruptionSummarySerializer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
-	return nil, nil
-}
-
 func (*disruptionSummarySerializer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
 	backendDisruption := computeDisruptionData(finalIntervals)
 	return writeDisruptionData(filepath.Join(storageDir, fmt.Sprintf("backend-disruption%s.json", timeSuffix)), backendDisruption)
 }
 
-func (*disruptionSummarySerializer) Cleanup(ctx context.Context) error {
-	// TODO wire up the start to a context we can kill here
-	return nil
-}
-
 type BackendDisruptionList struct {
 	// BackendDisruptions is keyed by name to make the consumption easier
 	BackendDisruptions map[string]*BackendDisruption
@@ -65,6 +44,12 @@ type BackendDisruption struct {
 	DisruptedDuration  metav1.Duration
 	DisruptionMessages []string
 
+	// DisruptedDurationByCategory breaks DisruptedDuration down by the category of the
+	// errors that caused it, for instance ConnectionRefused, IOTimeout or HTTP5xx.  It
+	// tells load balancer problems apart from server side outages.  Disruption reported
+	// by a sampler that does not categorize its errors is not included.
+	DisruptedDurationByCategory map[string]metav1.Duration `json:",omitempty"`
+
 	// New disruption test framework is introducing these fields, for
 	// previous version of the test, these fields will default:
 	//   LoadBalancerType will default to "external-lb"
@@ -110,13 +95,14 @@ func computeDisruptionData(eventIntervals monitorapi.Intervals) *BackendDisrupti
 			monitorapi.BackendDisruptionSeconds(backendDisruptionName, allDisruptionEventsIntervals)
 
 		bs := &BackendDisruption{
-			Name:               backendDisruptionName,
-			BackendName:        backendDisruptionName,
-			ConnectionType:     strings.Title(string(connectionType)),
-			DisruptedDuration:  metav1.Duration{Duration: disruptionDuration},
-			DisruptionMessages: disruptionMessages,
-			LoadBalancerType:   "",
-			Protocol:           "",
+			Name:                        backendDisruptionName,
+			BackendName:                 backendDisruptionName,
+			ConnectionType:              strings.Title(string(connectionType)),
+			DisruptedDuration:           metav1.Duration{Duration: disruptionDuration},
+			DisruptionMessages:          disruptionMessages,
+			DisruptedDurationByCategory: disruptionByCategory(backendDisruptionName, allDisruptionEventsIntervals),
+			LoadBalancerType:            "",
+			Protocol:                    "",
 			// for existing disruption test, the 'disruption' locator
 			// part closely resembles the api being tested.
 			TargetAPI: "",
@@ -126,3 +112,32 @@ func computeDisruptionData(eventIntervals monitorapi.Intervals) *BackendDisrupti
 
 	return ret
 }
+
+// disruptionByCategory totals the disruption of the backend by the error category of the intervals, nil when none of
+// its intervals has a category.
+func disruptionByCategory(backendDisruptionName string, eventIntervals monitorapi.Intervals) map[string]metav1.Duration {
+	disruptionEvents := eventIntervals.Filter(
+		monitorapi.And(
+			monitorapi.IsErrorEvent,
+			monitorapi.IsEventForBackendDisruptionName(backendDisruptionName),
+		),
+	)
+	categoryToIntervals := map[string]monitorapi.Intervals{}
+	for _, eventInterval := range disruptionEvents {
+		category := eventInterval.Message.Annotations[monitorapi.AnnotationErrorCategory]
+		if len(category) == 0 {
+			continue
+		}
+		categoryToIntervals[category] = append(categoryToIntervals[category], eventInterval)
+	}
+	if len(categoryToIntervals) == 0 {
+		return nil
+	}
+
+	ret := map[string]metav1.Duration{}
+	for category, categoryIntervals := range categoryToIntervals {
+		// rounded the same way as the DisruptedDuration
+		ret[category] = metav1.Duration{Duration: categoryIntervals.Duration(1 * time.Second).Round(time.Second)}
+	}
+	return ret
+}
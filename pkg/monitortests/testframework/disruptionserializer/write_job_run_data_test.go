@@ -454,3 +454,37 @@ func TestComputeDisruptionData(t *testing.T) {
 		})
 	}
 }
+
+func TestComputeDisruptionDataByCategory(t *testing.T) {
+	now := time.Now()
+	disruption := func(category string, from, to time.Duration) monitorapi.Interval {
+		message := monitorapi.NewMessage().Reason(monitorapi.DisruptionBeganEventReason).HumanMessage("foo")
+		if len(category) > 0 {
+			message = message.WithAnnotation(monitorapi.AnnotationErrorCategory, category)
+		}
+		return monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Error).
+			Locator(monitorapi.NewLocator().Disruption("etcd-tcp-internal-lb-new-connections", "etcd-tcp-internal-lb", "internal-lb", "tcp", "etcd", monitorapi.NewConnectionType)).
+			Message(message).
+			Build(now.Add(from), now.Add(to))
+	}
+
+	disruptions := computeDisruptionData(monitorapi.Intervals{
+		disruption("ConnectionRefused", 0, 10*time.Second),
+		disruption("IOTimeout", 10*time.Second, 15*time.Second),
+		disruption("ConnectionRefused", time.Minute, time.Minute+20*time.Second),
+	})
+	ad := disruptions.BackendDisruptions["etcd-tcp-internal-lb-new-connections"]
+	if assert.NotNil(t, ad) {
+		assert.Equal(t, metav1.Duration{Duration: 35 * time.Second}, ad.DisruptedDuration)
+		assert.Equal(t, map[string]metav1.Duration{
+			"ConnectionRefused": {Duration: 30 * time.Second},
+			"IOTimeout":         {Duration: 5 * time.Second},
+		}, ad.DisruptedDurationByCategory)
+	}
+
+	disruptions = computeDisruptionData(monitorapi.Intervals{disruption("", 0, 10*time.Second)})
+	ad = disruptions.BackendDisruptions["etcd-tcp-internal-lb-new-connections"]
+	if assert.NotNil(t, ad) {
+		assert.Nil(t, ad.DisruptedDurationByCategory, "disruption without a category is not broken down")
+	}
+}
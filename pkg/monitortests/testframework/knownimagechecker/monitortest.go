@@ -30,6 +30,12 @@ import (
 
 const testName = "[sig-arch] Only known images used by tests"
 
+var (
+	_ monitortestframework.ClusterCollector  = &clusterImageValidator{}
+	_ monitortestframework.IntervalEvaluator = &clusterImageValidator{}
+	_ monitortestframework.StorageWriter     = &clusterImageValidator{}
+)
+
 type clusterImageValidator struct {
 	adminKubeConfig *rest.Config
 }
@@ -47,10 +53,6 @@ func (w *clusterImageValidator) CollectData(ctx context.Context, storageDir stri
 	return nil, nil, nil
 }
 
-func (*clusterImageValidator) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
-	return nil, nil
-}
-
 // EvaluateTestsFromConstructedIntervals checks whether the cluster pulled an image that is
 // outside the allowed list of images. The list is defined as a set of static test case images, the
 // local cluster registry, any repository referenced by the image streams in the cluster's 'openshift'
@@ -202,11 +204,6 @@ func (*clusterImageValidator) WriteContentToStorage(ctx context.Context, storage
 	return monitorserialization.EventsToFile(filepath.Join(storageDir, fmt.Sprintf("e2e-events%s.json", timeSuffix)), finalIntervals)
 }
 
-func (*clusterImageValidator) Cleanup(ctx context.Context) error {
-	// TODO wire up the start to a context we can kill here
-	return nil
-}
-
 func hasAnyStringPrefix(s string, prefixes []string) bool {
 	for _, prefix := range prefixes {
 		if strings.HasPrefix(s, prefix) {
@@ -17,6 +17,11 @@ import (
 
 const testName = "[sig-node] kubelet metrics endpoints should always be reachable"
 
+var (
+	_ monitortestframework.ClusterCollector  = &metricsEndpointDown{}
+	_ monitortestframework.IntervalEvaluator = &metricsEndpointDown{}
+)
+
 type metricsEndpointDown struct {
 	adminRESTConfig *rest.Config
 }
@@ -35,10 +40,6 @@ func (w *metricsEndpointDown) CollectData(ctx context.Context, storageDir string
 	return intervals, nil, err
 }
 
-func (*metricsEndpointDown) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
-	return nil, nil
-}
-
 func (*metricsEndpointDown) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
 	failures := []string{}
 	logger := logrus.WithField("MonitorTest", "MetricsEndpointDown")
@@ -87,12 +88,3 @@ func (*metricsEndpointDown) EvaluateTestsFromConstructedIntervals(ctx context.Co
 	})
 	return junits, nil
 }
-
-func (*metricsEndpointDown) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
-	return nil
-}
-
-func (*metricsEndpointDown) Cleanup(ctx context.Context) error {
-	// TODO wire up the start to a context we can kill here
-	return nil
-}
@@ -8,11 +8,11 @@ import (
 	"github.com/openshift/origin/pkg/monitortestframework"
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
-	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
 	"github.com/sirupsen/logrus"
-	"k8s.io/client-go/rest"
 )
 
+var _ monitortestframework.IntervalConstructor = &pathologicalEventAnalyzer{}
+
 type pathologicalEventAnalyzer struct {
 }
 
@@ -20,30 +20,10 @@ func NewAnalyzer() monitortestframework.MonitorTest {
 	return &pathologicalEventAnalyzer{}
 }
 
-func (w *pathologicalEventAnalyzer) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
-	return nil
-}
-
-func (w *pathologicalEventAnalyzer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
-	return nil, nil, nil
-}
-
 func (*pathologicalEventAnalyzer) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
 	return markMissedPathologicalEvents(startingIntervals), nil
 }
 
-func (*pathologicalEventAnalyzer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
-	return nil, nil
-}
-
-func (*pathologicalEventAnalyzer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
-	return nil
-}
-
-func (*pathologicalEventAnalyzer) Cleanup(ctx context.Context) error {
-	return nil
-}
-
 // getPathologicalEventMapKey returns a string key that can be used in a map to identify other occurrences of the same
 // event.
 func getPathologicalEventMapKey(interval monitorapi.Interval) string {
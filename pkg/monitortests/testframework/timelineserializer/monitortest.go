@@ -3,17 +3,16 @@ package timelineserializer
 import (
 	"context"
 	"sort"
-	"time"
 
 	"github.com/openshift/origin/pkg/monitortestframework"
 
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
-	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
-	"k8s.io/client-go/rest"
 )
 
+var _ monitortestframework.StorageWriter = &timelineSerializer{}
+
 type timelineSerializer struct {
 }
 
@@ -21,22 +20,6 @@ func NewTimelineSerializer() monitortestframework.MonitorTest {
 	return &timelineSerializer{}
 }
 
-func (w *timelineSerializer) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
-	return nil
-}
-
-func (w *timelineSerializer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
-	return nil, nil, nil
-}
-
-func (*timelineSerializer) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
-	return nil, nil
-}
-
-func (*timelineSerializer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
-	return nil, nil
-}
-
 func (*timelineSerializer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
 	errs := []error{}
 	var err error
@@ -77,8 +60,3 @@ func (*timelineSerializer) WriteContentToStorage(ctx context.Context, storageDir
 
 	return utilerrors.NewAggregate(errs)
 }
-
-func (*timelineSerializer) Cleanup(ctx context.Context) error {
-	// TODO wire up the start to a context we can kill here
-	return nil
-}
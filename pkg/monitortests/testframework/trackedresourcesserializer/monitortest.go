@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
-	"time"
 
 	"github.com/openshift/origin/pkg/monitortestframework"
 
@@ -12,10 +11,10 @@ import (
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
-	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
-	"k8s.io/client-go/rest"
 )
 
+var _ monitortestframework.StorageWriter = &trackedResourcesSerializer{}
+
 type trackedResourcesSerializer struct {
 }
 
@@ -23,22 +22,6 @@ func NewTrackedResourcesSerializer() monitortestframework.MonitorTest {
 	return &trackedResourcesSerializer{}
 }
 
-func (w *trackedResourcesSerializer) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
-	return nil
-}
-
-func (w *trackedResourcesSerializer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
-	return nil, nil, nil
-}
-
-func (*trackedResourcesSerializer) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
-	return nil, nil
-}
-
-func (*trackedResourcesSerializer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
-	return nil, nil
-}
-
 func (*trackedResourcesSerializer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
 	errors := []error{}
 
@@ -52,8 +35,3 @@ func (*trackedResourcesSerializer) WriteContentToStorage(ctx context.Context, st
 
 	return utilerrors.NewAggregate(errors)
 }
-
-func (*trackedResourcesSerializer) Cleanup(ctx context.Context) error {
-	// TODO wire up the start to a context we can kill here
-	return nil
-}
@@ -13,6 +13,11 @@ import (
 	"k8s.io/client-go/rest"
 )
 
+var (
+	_ monitortestframework.ClusterCollector    = &operatorWatcher{}
+	_ monitortestframework.IntervalConstructor = &operatorWatcher{}
+)
+
 type operatorWatcher struct {
 }
 
@@ -41,16 +46,3 @@ func (*operatorWatcher) ConstructComputedIntervals(ctx context.Context, starting
 
 	return constructedIntervals, nil
 }
-
-func (*operatorWatcher) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
-	return nil, nil
-}
-
-func (*operatorWatcher) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
-	return nil
-}
-
-func (*operatorWatcher) Cleanup(ctx context.Context) error {
-	// TODO wire up the start to a context we can kill here
-	return nil
-}
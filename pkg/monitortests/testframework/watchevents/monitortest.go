@@ -12,6 +12,11 @@ import (
 	"k8s.io/client-go/rest"
 )
 
+var (
+	_ monitortestframework.ClusterCollector    = &eventWatcher{}
+	_ monitortestframework.IntervalConstructor = &eventWatcher{}
+)
+
 type eventWatcher struct {
 }
 
@@ -40,16 +45,3 @@ func (*eventWatcher) ConstructComputedIntervals(ctx context.Context, startingInt
 
 	return constructedIntervals, nil
 }
-
-func (*eventWatcher) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
-	return nil, nil
-}
-
-func (*eventWatcher) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
-	return nil
-}
-
-func (*eventWatcher) Cleanup(ctx context.Context) error {
-	// TODO wire up the start to a context we can kill here
-	return nil
-}
@@ -19,6 +19,11 @@ import (
 	"time"
 )
 
+var (
+	_ monitortestframework.ClusterCollector = &watchRequestCountSerializer{}
+	_ monitortestframework.StorageWriter    = &watchRequestCountSerializer{}
+)
+
 type watchRequestCountSerializer struct {
 	monitorStartTime time.Time
 	adminRESTConfig  *rest.Config
@@ -38,14 +43,6 @@ func (w *watchRequestCountSerializer) CollectData(ctx context.Context, storageDi
 	return nil, nil, nil
 }
 
-func (w *watchRequestCountSerializer) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (constructedIntervals monitorapi.Intervals, err error) {
-	return nil, nil
-}
-
-func (w *watchRequestCountSerializer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
-	return nil, nil
-}
-
 func (w *watchRequestCountSerializer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
 	oc := exutil.NewCLIWithoutNamespace("api-requests")
 
@@ -83,10 +80,6 @@ func (w *watchRequestCountSerializer) WriteContentToStorage(ctx context.Context,
 	return nil
 }
 
-func (w *watchRequestCountSerializer) Cleanup(ctx context.Context) error {
-	return nil
-}
-
 type OperatorKey struct {
 	NodeName string
 	Operator string
@@ -39,51 +39,25 @@ const sippyURL = "https://sippy.dptools.openshift.org/sippy-ng/"
 func (opt *Options) Run() error {
 	logrus.Infof("Scanning for %s files in: %s", testFailureSummaryFilePrefix, opt.JUnitDir)
 
-	resultFiles, err := filepath.Glob(fmt.Sprintf("%s/%s*.json", opt.JUnitDir, testFailureSummaryFilePrefix))
+	prowJobRuns, err := ReadProwJobRuns(opt.JUnitDir)
 	if err != nil {
-		logrus.Infof("Error scanning for test failure summary files: %v", err)
+		logrus.WithError(err).Info("Error reading test failure summary files")
 		return nil
 	}
-	logrus.Infof("Found files: %v", resultFiles)
 
 	// we didn't find any files to process. log but don't return an error as  step may not have produced those files
-	if len(resultFiles) == 0 {
+	if len(prowJobRuns) == 0 {
 		logrus.Infof("Missing : %s file(s), exiting", testFailureSummaryFilePrefix)
 		return nil
 	}
-
-	prowJobRuns := []*ProwJobRun{}
-	// Read each result file into a ProwJobRun struct:
-	for _, rf := range resultFiles {
-		data, err := os.ReadFile(rf)
-		if err != nil {
-			logrus.Infof("Error reading test failure summary file: %s - %v", rf, err)
-			return nil
-		}
-		jobRun := &ProwJobRun{}
-		err = json.Unmarshal(data, jobRun)
-		if err != nil {
-			logrus.Infof("Error unmarshalling ProwJob json for: %s - %v", rf, err)
-			return nil
-		}
-		prowJobRuns = append(prowJobRuns, jobRun)
-	}
+	logrus.Infof("Found %d %s files", len(prowJobRuns), testFailureSummaryFilePrefix)
 
 	// We will often have more than one output file for this job run because openshift-tests is often
 	// invoked multiple times (pre/post upgrade). We need to merge the data together in this case.
-	var finalProwJobRun *ProwJobRun
-	for _, pjr := range prowJobRuns {
-		if finalProwJobRun == nil {
-			finalProwJobRun = pjr
-			continue
-		}
-		if pjr.ProwJob.Name != finalProwJobRun.ProwJob.Name {
-			logrus.Errorf("Mismatched job names found in %s files, %s != %s",
-				testFailureSummaryFilePrefix, finalProwJobRun.ProwJob.Name, pjr.ProwJob.Name)
-			return nil
-		}
-		finalProwJobRun.Tests = append(finalProwJobRun.Tests, pjr.Tests...)
-		finalProwJobRun.TestCount += pjr.TestCount
+	finalProwJobRun, err := MergeProwJobRuns(prowJobRuns)
+	if err != nil {
+		logrus.WithError(err).Error("Error merging test failure summaries")
+		return nil
 	}
 
 	inputBytes, err := json.Marshal(finalProwJobRun)
@@ -67,6 +67,11 @@ func WriteJobRunTestFailureSummary(artifactDir, timeSuffix string, finalSuiteRes
 		})
 	}
 
+	return WriteProwJobRun(artifactDir, timeSuffix, outputFileSubStr, &jr)
+}
+
+// WriteProwJobRun writes jr as a test failure summary to artifactDir.
+func WriteProwJobRun(artifactDir, timeSuffix, outputFileSubStr string, jr *ProwJobRun) error {
 	jsonContent, err := json.MarshalIndent(jr, "", "    ")
 	if err != nil {
 		return err
@@ -76,6 +81,47 @@ func WriteJobRunTestFailureSummary(artifactDir, timeSuffix string, finalSuiteRes
 	return ioutil.WriteFile(outputFile, jsonContent, 0644)
 }
 
+// ReadProwJobRuns reads every test failure summary in dir.
+func ReadProwJobRuns(dir string) ([]*ProwJobRun, error) {
+	resultFiles, err := filepath.Glob(fmt.Sprintf("%s/%s*.json", dir, testFailureSummaryFilePrefix))
+	if err != nil {
+		return nil, fmt.Errorf("error scanning for test failure summary files: %w", err)
+	}
+
+	prowJobRuns := []*ProwJobRun{}
+	for _, rf := range resultFiles {
+		data, err := os.ReadFile(rf)
+		if err != nil {
+			return nil, fmt.Errorf("error reading test failure summary file: %s - %w", rf, err)
+		}
+		jobRun := &ProwJobRun{}
+		if err := json.Unmarshal(data, jobRun); err != nil {
+			return nil, fmt.Errorf("error unmarshalling ProwJob json for: %s - %w", rf, err)
+		}
+		prowJobRuns = append(prowJobRuns, jobRun)
+	}
+	return prowJobRuns, nil
+}
+
+// MergeProwJobRuns combines the test failure summaries of several invocations of openshift-tests in the same job
+// run, such as before and after an upgrade or the shards of a suite.  It returns nil when there are no summaries.
+func MergeProwJobRuns(prowJobRuns []*ProwJobRun) (*ProwJobRun, error) {
+	var finalProwJobRun *ProwJobRun
+	for _, pjr := range prowJobRuns {
+		if finalProwJobRun == nil {
+			finalProwJobRun = pjr
+			continue
+		}
+		if pjr.ProwJob.Name != finalProwJobRun.ProwJob.Name {
+			return nil, fmt.Errorf("mismatched job names found in %s files, %s != %s",
+				testFailureSummaryFilePrefix, finalProwJobRun.ProwJob.Name, pjr.ProwJob.Name)
+		}
+		finalProwJobRun.Tests = append(finalProwJobRun.Tests, pjr.Tests...)
+		finalProwJobRun.TestCount += pjr.TestCount
+	}
+	return finalProwJobRun, nil
+}
+
 // passFail is a simple struct to track test names which can appear more than once.
 // If both passed and failed are true, it was a flake.
 type passFail struct {
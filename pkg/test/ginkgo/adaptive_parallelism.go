@@ -0,0 +1,329 @@
+package ginkgo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	// adaptiveParallelismPeriod is how often the number of tests running in parallel is adjusted.
+	adaptiveParallelismPeriod = 30 * time.Second
+	// clusterHealthWindow is how long a disruption or API server error keeps the cluster unhealthy after it was seen.
+	clusterHealthWindow = 2 * time.Minute
+	// apiServerErrorThreshold is how many 429 or 5xx responses within clusterHealthWindow make the cluster unhealthy.
+	apiServerErrorThreshold = 3
+)
+
+// clusterHealth passes every interval to its delegate and watches them for signs that the cluster is struggling:
+// disruption reported by the backend disruption samplers and NotReady nodes.  The 429 and 5xx responses from the API
+// server are seen by the clients whose transport is wrapped by wrapTransport.  It is threadsafe.
+type clusterHealth struct {
+	monitorapi.Recorder
+
+	lock sync.Mutex
+	now  func() time.Time
+	// lastDisruption is when disruption was last seen, openDisruptions are the started disruption intervals that
+	// have not ended.
+	lastDisruption  time.Time
+	openDisruptions sets.Int
+	apiServerErrors []time.Time
+	notReadyNodes   sets.String
+}
+
+func newClusterHealth(delegate monitorapi.Recorder) *clusterHealth {
+	return &clusterHealth{
+		Recorder:        delegate,
+		now:             time.Now,
+		openDisruptions: sets.NewInt(),
+		notReadyNodes:   sets.NewString(),
+	}
+}
+
+func (h *clusterHealth) AddIntervals(intervals ...monitorapi.Interval) {
+	h.Recorder.AddIntervals(intervals...)
+	for _, interval := range intervals {
+		h.observe(interval, -1)
+	}
+}
+
+func (h *clusterHealth) StartInterval(interval monitorapi.Interval) int {
+	id := h.Recorder.StartInterval(interval)
+	h.observe(interval, id)
+	return id
+}
+
+func (h *clusterHealth) EndInterval(startedInterval int, t time.Time) *monitorapi.Interval {
+	ret := h.Recorder.EndInterval(startedInterval, t)
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.openDisruptions.Has(startedInterval) {
+		h.openDisruptions.Delete(startedInterval)
+		h.lastDisruption = h.now()
+	}
+	return ret
+}
+
+// observe records the signal an interval carries, id is the interval's id when it was started and is still open.
+func (h *clusterHealth) observe(interval monitorapi.Interval, id int) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	switch {
+	case interval.Source == monitorapi.SourceDisruption && interval.Message.Reason == monitorapi.DisruptionBeganEventReason:
+		h.lastDisruption = h.now()
+		if id >= 0 && interval.To.IsZero() {
+			h.openDisruptions.Insert(id)
+		}
+
+	case interval.Source == monitorapi.SourceNodeMonitor:
+		node := interval.Locator.Keys[monitorapi.LocatorNodeKey]
+		switch interval.Message.Reason {
+		case monitorapi.NodeNotReadyReason:
+			h.notReadyNodes.Insert(node)
+		case "Ready", monitorapi.NodeDeletedReason:
+			h.notReadyNodes.Delete(node)
+		}
+	}
+}
+
+// wrapTransport counts the 429 and 5xx responses of a client toward the API server errors, it is a rest.Config
+// WrapTransport.
+func (h *clusterHealth) wrapTransport(rt http.RoundTripper) http.RoundTripper {
+	return &responseCodeRoundTripper{delegate: rt, health: h}
+}
+
+func (h *clusterHealth) observeAPIServerError() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.apiServerErrors = append(h.apiServerErrors, h.now())
+}
+
+type responseCodeRoundTripper struct {
+	delegate http.RoundTripper
+	health   *clusterHealth
+}
+
+func (rt *responseCodeRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := rt.delegate.RoundTrip(req)
+	if err == nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError) {
+		rt.health.observeAPIServerError()
+	}
+	return resp, err
+}
+
+func (rt *responseCodeRoundTripper) WrappedRoundTripper() http.RoundTripper {
+	return rt.delegate
+}
+
+// unhealthy returns why the cluster is unhealthy at now, or nothing when it is healthy.
+func (h *clusterHealth) unhealthy(now time.Time) []string {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	reasons := []string{}
+	if h.openDisruptions.Len() > 0 || (!h.lastDisruption.IsZero() && now.Sub(h.lastDisruption) < clusterHealthWindow) {
+		reasons = append(reasons, "backend disruption")
+	}
+
+	recent := []time.Time{}
+	for _, t := range h.apiServerErrors {
+		if now.Sub(t) < clusterHealthWindow {
+			recent = append(recent, t)
+		}
+	}
+	h.apiServerErrors = recent
+	if len(recent) >= apiServerErrorThreshold {
+		reasons = append(reasons, fmt.Sprintf("%d API server 429 or 5xx errors in the last %v", len(recent), clusterHealthWindow))
+	}
+
+	if h.notReadyNodes.Len() > 0 {
+		reasons = append(reasons, fmt.Sprintf("nodes not ready: %s", strings.Join(h.notReadyNodes.List(), ", ")))
+	}
+	return reasons
+}
+
+// workerLimit bounds how many workers run a test at once, across every Execute of a suite.  A nil workerLimit does
+// not limit the workers.  It is threadsafe.
+type workerLimit struct {
+	lock   sync.Mutex
+	cond   *sync.Cond
+	limit  int
+	active int
+}
+
+func newWorkerLimit(limit int) *workerLimit {
+	l := &workerLimit{limit: limit}
+	l.cond = sync.NewCond(&l.lock)
+	return l
+}
+
+// acquire waits until fewer than limit workers are running a test.  It returns false when the context is finished.
+func (l *workerLimit) acquire(ctx context.Context) bool {
+	if l == nil {
+		return ctx.Err() == nil
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	for l.active >= l.limit {
+		if ctx.Err() != nil {
+			return false
+		}
+		l.cond.Wait()
+	}
+	if ctx.Err() != nil {
+		return false
+	}
+	l.active++
+	return true
+}
+
+func (l *workerLimit) release() {
+	if l == nil {
+		return
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.active--
+	l.cond.Broadcast()
+}
+
+// set changes the limit.  Workers already running a test finish it when the limit shrinks.
+func (l *workerLimit) set(limit int) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.limit = limit
+	l.cond.Broadcast()
+}
+
+func (l *workerLimit) get() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.limit
+}
+
+// wake releases the workers waiting in acquire, so they notice the context is finished.
+func (l *workerLimit) wake() {
+	if l == nil {
+		return
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.cond.Broadcast()
+}
+
+// adaptiveParallelism halves the number of tests running in parallel, down to min, whenever the cluster is
+// unhealthy, and grows it back toward max while the cluster is healthy.  Every change is recorded as an interval
+// lasting until the next change, so the effect on flakes can be judged from the timeline.
+type adaptiveParallelism struct {
+	health   *clusterHealth
+	limit    *workerLimit
+	min, max int
+
+	// intervalID is the interval for the current parallelism, -1 while running at max.
+	intervalID int
+	cancel     context.CancelFunc
+	done       chan struct{}
+}
+
+func newAdaptiveParallelism(health *clusterHealth, minimum, maximum int) *adaptiveParallelism {
+	return &adaptiveParallelism{
+		health:     health,
+		limit:      newWorkerLimit(maximum),
+		min:        minimum,
+		max:        maximum,
+		intervalID: -1,
+	}
+}
+
+// start adjusts the parallelism until stop is called.
+func (a *adaptiveParallelism) start(ctx context.Context) {
+	ctx, a.cancel = context.WithCancel(ctx)
+	a.done = make(chan struct{})
+	go func() {
+		defer close(a.done)
+		ticker := time.NewTicker(adaptiveParallelismPeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				a.record(time.Now(), a.max, nil)
+				return
+			case now := <-ticker.C:
+				a.adjust(now)
+			}
+		}
+	}()
+}
+
+// stop ends the adjustments and the interval of the current parallelism.  It may be called more than once.
+func (a *adaptiveParallelism) stop() {
+	if a.cancel == nil {
+		return
+	}
+	a.cancel()
+	<-a.done
+}
+
+func (a *adaptiveParallelism) adjust(now time.Time) {
+	current := a.limit.get()
+	reasons := a.health.unhealthy(now)
+
+	next := current
+	if len(reasons) > 0 {
+		next = current / 2
+		if next < a.min {
+			next = a.min
+		}
+	} else {
+		// grow by a tenth of the maximum each period, so recovering takes a few minutes
+		next = current + a.max/10
+		if next == current {
+			next++
+		}
+		if next > a.max {
+			next = a.max
+		}
+	}
+	if next == current {
+		return
+	}
+	a.limit.set(next)
+	a.record(now, next, reasons)
+}
+
+// record ends the interval of the previous parallelism and, unless the runner is back at max, starts one for
+// parallelism.
+func (a *adaptiveParallelism) record(now time.Time, parallelism int, reasons []string) {
+	if a.intervalID != -1 {
+		a.health.EndInterval(a.intervalID, now)
+		a.intervalID = -1
+	}
+	if parallelism == a.max {
+		return
+	}
+
+	message := monitorapi.NewMessage().Reason(monitorapi.TestParallelismChangedReason).
+		WithAnnotation(monitorapi.AnnotationParallelism, strconv.Itoa(parallelism))
+	if len(reasons) > 0 {
+		message = message.HumanMessagef("running at most %d of %d tests in parallel because of %s", parallelism, a.max, strings.Join(reasons, "; "))
+	} else {
+		message = message.HumanMessagef("running at most %d of %d tests in parallel while the cluster recovers", parallelism, a.max)
+	}
+	a.intervalID = a.health.StartInterval(
+		monitorapi.NewInterval(monitorapi.SourceTestParallelism, monitorapi.Warning).
+			Locator(monitorapi.NewLocator().TestRunner()).
+			Message(message).
+			Display().
+			Build(now, time.Time{}),
+	)
+}
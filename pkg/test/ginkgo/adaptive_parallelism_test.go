@@ -0,0 +1,204 @@
+package ginkgo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// intervalRecorder keeps the intervals recorded through it.
+type intervalRecorder struct {
+	lock      sync.Mutex
+	intervals monitorapi.Intervals
+}
+
+func (r *intervalRecorder) Intervals(from, to time.Time) monitorapi.Intervals {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append(monitorapi.Intervals{}, r.intervals...)
+}
+func (r *intervalRecorder) CurrentResourceState() monitorapi.ResourcesMap            { return nil }
+func (r *intervalRecorder) RecordResource(resourceType string, obj runtime.Object)   {}
+func (r *intervalRecorder) Record(conditions ...monitorapi.Condition)                {}
+func (r *intervalRecorder) RecordAt(t time.Time, conditions ...monitorapi.Condition) {}
+func (r *intervalRecorder) AddIntervals(intervals ...monitorapi.Interval) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.intervals = append(r.intervals, intervals...)
+}
+func (r *intervalRecorder) StartInterval(interval monitorapi.Interval) int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.intervals = append(r.intervals, interval)
+	return len(r.intervals) - 1
+}
+func (r *intervalRecorder) EndInterval(startedInterval int, t time.Time) *monitorapi.Interval {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.intervals[startedInterval].To = t
+	return &r.intervals[startedInterval]
+}
+
+func disruptionBegan(message string, from, to time.Time) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Error).
+		Locator(monitorapi.NewLocator().DisruptionRequiredOnly("kube-api-new-connections", "")).
+		Message(monitorapi.NewMessage().Reason(monitorapi.DisruptionBeganEventReason).HumanMessage(message)).
+		Build(from, to)
+}
+
+func nodeReadiness(node string, reason monitorapi.IntervalReason, at time.Time) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceNodeMonitor, monitorapi.Warning).
+		Locator(monitorapi.NewLocator().NodeFromName(node)).
+		Message(monitorapi.NewMessage().Reason(reason).HumanMessage("node readiness")).
+		Build(at, at)
+}
+
+func TestClusterHealth(t *testing.T) {
+	now := time.Date(2023, 2, 14, 20, 30, 0, 0, time.UTC)
+	health := newClusterHealth(&intervalRecorder{})
+	health.now = func() time.Time { return now }
+	assert.Empty(t, health.unhealthy(now))
+
+	// an open disruption keeps the cluster unhealthy until it ends, and for a while after
+	id := health.StartInterval(disruptionBegan("connection refused", now, time.Time{}))
+	assert.Equal(t, []string{"backend disruption"}, health.unhealthy(now.Add(time.Hour)))
+	now = now.Add(time.Hour)
+	health.EndInterval(id, now)
+	assert.Equal(t, []string{"backend disruption"}, health.unhealthy(now.Add(time.Minute)))
+	assert.Empty(t, health.unhealthy(now.Add(clusterHealthWindow)))
+
+	// API server errors are read from the responses of the clients, they count once there are enough of them
+	now = now.Add(time.Hour)
+	statusCode := http.StatusOK
+	client := &http.Client{Transport: health.wrapTransport(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: statusCode, Body: http.NoBody, Request: req}, nil
+	}))}
+	get := func() {
+		resp, err := client.Get("https://api.example.com/api/v1/nodes")
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	get()
+	for _, code := range []int{http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusServiceUnavailable} {
+		assert.Empty(t, health.unhealthy(now))
+		statusCode = code
+		get()
+	}
+	assert.Equal(t, []string{"3 API server 429 or 5xx errors in the last 2m0s"}, health.unhealthy(now))
+	assert.Empty(t, health.unhealthy(now.Add(clusterHealthWindow)))
+
+	health.AddIntervals(nodeReadiness("worker-a", monitorapi.NodeNotReadyReason, now), nodeReadiness("worker-b", monitorapi.NodeNotReadyReason, now))
+	assert.Equal(t, []string{"nodes not ready: worker-a, worker-b"}, health.unhealthy(now))
+	health.AddIntervals(nodeReadiness("worker-a", "Ready", now))
+	assert.Equal(t, []string{"nodes not ready: worker-b"}, health.unhealthy(now))
+	// a node deleted while it was not ready never becomes ready again
+	health.AddIntervals(nodeReadiness("worker-b", monitorapi.NodeDeletedReason, now))
+	assert.Empty(t, health.unhealthy(now))
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+func TestAdaptiveParallelism(t *testing.T) {
+	now := time.Date(2023, 2, 14, 20, 30, 0, 0, time.UTC)
+	recorder := &intervalRecorder{}
+	health := newClusterHealth(recorder)
+	health.now = func() time.Time { return now }
+	adaptive := newAdaptiveParallelism(health, 3, 20)
+
+	health.AddIntervals(nodeReadiness("worker-a", monitorapi.NodeNotReadyReason, now))
+	limits := []int{}
+	for i := 0; i < 3; i++ {
+		now = now.Add(adaptiveParallelismPeriod)
+		adaptive.adjust(now)
+		limits = append(limits, adaptive.limit.get())
+	}
+	health.AddIntervals(nodeReadiness("worker-a", "Ready", now))
+	for i := 0; i < 9; i++ {
+		now = now.Add(adaptiveParallelismPeriod)
+		adaptive.adjust(now)
+		limits = append(limits, adaptive.limit.get())
+	}
+	assert.Equal(t, []int{10, 5, 3, 5, 7, 9, 11, 13, 15, 17, 19, 20}, limits)
+
+	changes := monitorapi.Intervals{}
+	for _, interval := range recorder.Intervals(time.Time{}, time.Time{}) {
+		if interval.Source == monitorapi.SourceTestParallelism {
+			changes = append(changes, interval)
+		}
+	}
+	require.Len(t, changes, 11, "every change below the maximum is recorded")
+	assert.Equal(t, "running at most 10 of 20 tests in parallel because of nodes not ready: worker-a", changes[0].Message.HumanMessage)
+	assert.Equal(t, "10", changes[0].Message.Annotations[monitorapi.AnnotationParallelism])
+	assert.Equal(t, changes[1].From, changes[0].To)
+	assert.Equal(t, "running at most 19 of 20 tests in parallel while the cluster recovers", changes[10].Message.HumanMessage)
+	assert.Equal(t, now, changes[10].To, "the last change ends when the maximum is reached again")
+}
+
+func TestAdaptiveParallelismStop(t *testing.T) {
+	recorder := &intervalRecorder{}
+	adaptive := newAdaptiveParallelism(newClusterHealth(recorder), 3, 20)
+	adaptive.stop()
+
+	adaptive.start(context.Background())
+	adaptive.limit.set(10)
+	adaptive.record(time.Now(), 10, nil)
+	adaptive.stop()
+	adaptive.stop()
+
+	intervals := recorder.Intervals(time.Time{}, time.Time{})
+	require.Len(t, intervals, 1)
+	assert.False(t, intervals[0].To.IsZero(), "stopping ends the interval of the current parallelism")
+}
+
+// concurrencyCheckingSuiteRunner records the most tests that ran at the same time.
+type concurrencyCheckingSuiteRunner struct {
+	lock    sync.Mutex
+	running int
+	most    int
+}
+
+func (r *concurrencyCheckingSuiteRunner) RunOneTest(ctx context.Context, test *testCase) {
+	r.lock.Lock()
+	r.running++
+	if r.running > r.most {
+		r.most = r.running
+	}
+	r.lock.Unlock()
+
+	time.Sleep(time.Millisecond)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.running--
+}
+
+func TestWorkerLimit(t *testing.T) {
+	tests := []*testCase{}
+	for i := 0; i < 100; i++ {
+		tests = append(tests, &testCase{name: fmt.Sprintf("test %d", i)})
+	}
+	testSuiteRunner := &concurrencyCheckingSuiteRunner{}
+	execute(context.TODO(), testSuiteRunner, tests, 20, newWorkerLimit(3))
+	assert.Equal(t, 3, testSuiteRunner.most)
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	limit := newWorkerLimit(0)
+	context.AfterFunc(ctx, limit.wake)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	assert.False(t, limit.acquire(ctx), "workers waiting for room stop with the context")
+}
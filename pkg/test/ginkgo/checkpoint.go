@@ -0,0 +1,173 @@
+package ginkgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// checkpointFilename is the journal in --junit-dir that every completed test is appended to, so that a run that is
+// interrupted can be resumed without losing the results it already has.
+const checkpointFilename = "e2e-checkpoint.jsonl"
+
+// checkpointEntry is one line of the journal.
+type checkpointEntry struct {
+	Name     string        `json:"name"`
+	State    TestState     `json:"state"`
+	Start    time.Time     `json:"start"`
+	End      time.Time     `json:"end"`
+	Duration time.Duration `json:"duration"`
+	Output   string        `json:"output,omitempty"`
+	// Retry is set for the runs that retry a failure, they are run again on resume along with the other failures.
+	Retry bool `json:"retry,omitempty"`
+}
+
+// checkpointJournal appends completed tests to the journal.  It is threadsafe and a nil journal records nothing.
+type checkpointJournal struct {
+	lock sync.Mutex
+	file *os.File
+}
+
+// openCheckpointJournal starts a new journal in dir, or when resuming, continues the existing one and returns the
+// entries it already holds.
+func openCheckpointJournal(dir string, resume bool) (*checkpointJournal, []checkpointEntry, error) {
+	filename := filepath.Join(dir, checkpointFilename)
+	if !resume {
+		file, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not create checkpoint: %w", err)
+		}
+		return &checkpointJournal{file: file}, nil, nil
+	}
+
+	entries, validLength, err := readCheckpoint(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	file, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not open checkpoint: %w", err)
+	}
+	// drop an entry that was only partially written when the previous run died, so new entries start on a new line.
+	if err := file.Truncate(validLength); err != nil {
+		file.Close()
+		return nil, nil, fmt.Errorf("could not open checkpoint: %w", err)
+	}
+	if _, err := file.Seek(validLength, io.SeekStart); err != nil {
+		file.Close()
+		return nil, nil, fmt.Errorf("could not open checkpoint: %w", err)
+	}
+	return &checkpointJournal{file: file}, entries, nil
+}
+
+// readCheckpoint returns the complete entries of the journal and the length of the journal they were read from.  A
+// missing journal has no entries.
+func readCheckpoint(filename string) ([]checkpointEntry, int64, error) {
+	content, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("could not read checkpoint: %w", err)
+	}
+
+	entries := []checkpointEntry{}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	var validLength int64
+	for {
+		entry := checkpointEntry{}
+		err := decoder.Decode(&entry)
+		if err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("could not read checkpoint %s: %w", filename, err)
+		}
+		switch entry.State {
+		case TestSucceeded, TestFailed, TestFailedTimeout, TestFlaked, TestSkipped, TestUnknown:
+		default:
+			return nil, 0, fmt.Errorf("could not read checkpoint %s: test %q has unknown state %q", filename, entry.Name, entry.State)
+		}
+		entries = append(entries, entry)
+		// the decoder stops right after the value, include the newline that ends it.
+		validLength = decoder.InputOffset()
+		if validLength < int64(len(content)) && content[validLength] == '\n' {
+			validLength++
+		}
+	}
+	return entries, validLength, nil
+}
+
+// Record appends the result of a test that ran to completion.
+func (j *checkpointJournal) Record(test *testCase, testRunResult *testRunResult) error {
+	if j == nil {
+		return nil
+	}
+	content, err := json.Marshal(checkpointEntry{
+		Name:     test.name,
+		State:    testRunResult.testState,
+		Start:    testRunResult.start,
+		End:      testRunResult.end,
+		Duration: test.duration,
+		Output:   string(testRunResult.testOutputBytes),
+		Retry:    test.previous != nil,
+	})
+	if err != nil {
+		return err
+	}
+
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	_, err = j.file.Write(append(content, '\n'))
+	return err
+}
+
+func (j *checkpointJournal) Close() error {
+	if j == nil {
+		return nil
+	}
+	return j.file.Close()
+}
+
+// resumedTests returns the tests the journal holds a result for.  Retries are left out, because the failures they
+// retried are retried again at the end of the resumed run.
+func resumedTests(entries []checkpointEntry) []*testCase {
+	ret := []*testCase{}
+	for _, entry := range entries {
+		if entry.Retry {
+			continue
+		}
+		test := &testCase{name: entry.Name}
+		mutateTestCaseWithResults(test, &testRunResultHandle{
+			testRunResult: &testRunResult{
+				name:            entry.Name,
+				start:           entry.Start,
+				end:             entry.End,
+				testState:       entry.State,
+				testOutputBytes: []byte(entry.Output),
+			},
+		})
+		ret = append(ret, test)
+	}
+	return ret
+}
+
+// skipCompleted returns the tests that still have to run.  Each result in completed, a count by test name, accounts
+// for one run of a test with that name and is used up, so the same counts can be applied to every bucket in turn.
+func skipCompleted(tests []*testCase, completed map[string]int) []*testCase {
+	ret := []*testCase{}
+	for _, test := range tests {
+		if completed[test.name] > 0 {
+			completed[test.name]--
+			continue
+		}
+		ret = append(ret, test)
+	}
+	return ret
+}
@@ -0,0 +1,72 @@
+package ginkgo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpointResume(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2023, 2, 14, 20, 30, 0, 0, time.UTC)
+	result := func(name string, state TestState) *testRunResult {
+		return &testRunResult{name: name, start: start, end: start.Add(3 * time.Second), testState: state, testOutputBytes: []byte(name + " output")}
+	}
+
+	journal, entries, err := openCheckpointJournal(dir, false)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+	passed := &testCase{name: "passed", duration: 3 * time.Second}
+	failed := &testCase{name: "failed", duration: 3 * time.Second}
+	require.NoError(t, journal.Record(passed, result("passed", TestSucceeded)))
+	require.NoError(t, journal.Record(failed, result("failed", TestFailed)))
+	require.NoError(t, journal.Record(failed.Retry(), result("failed", TestSucceeded)))
+	require.NoError(t, journal.Close())
+
+	// the run died part way through writing the next result
+	filename := filepath.Join(dir, checkpointFilename)
+	file, err := os.OpenFile(filename, os.O_WRONLY|os.O_APPEND, 0644)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"name":"partial","sta`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	journal, entries, err = openCheckpointJournal(dir, true)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	resumed := resumedTests(entries)
+	require.Len(t, resumed, 2)
+	assert.Equal(t, "passed", resumed[0].name)
+	assert.True(t, resumed[0].success)
+	assert.Equal(t, 3*time.Second, resumed[0].duration)
+	assert.Equal(t, "passed output", string(resumed[0].testOutputBytes))
+	assert.Equal(t, "failed", resumed[1].name)
+	assert.True(t, resumed[1].failed)
+
+	// new results continue the journal after the last complete entry
+	require.NoError(t, journal.Record(&testCase{name: "next"}, result("next", TestSkipped)))
+	require.NoError(t, journal.Close())
+	entries, _, err = readCheckpoint(filename)
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+	assert.Equal(t, "next", entries[3].Name)
+}
+
+func TestCheckpointResumeWithoutJournal(t *testing.T) {
+	journal, entries, err := openCheckpointJournal(t.TempDir(), true)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+	require.NoError(t, journal.Close())
+}
+
+func TestSkipCompleted(t *testing.T) {
+	completed := map[string]int{"a": 2, "b": 1}
+	early := skipCompleted([]*testCase{{name: "a"}, {name: "c"}}, completed)
+	late := skipCompleted([]*testCase{{name: "a"}, {name: "a"}, {name: "b"}}, completed)
+	assert.Equal(t, []string{"c"}, testNames(early))
+	assert.Equal(t, []string{"a"}, testNames(late), "each result only accounts for one run of a test")
+}
@@ -9,7 +9,7 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
-	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
@@ -21,6 +21,7 @@ import (
 	"github.com/openshift/origin/pkg/clioptions/clusterinfo"
 	"github.com/openshift/origin/pkg/defaultmonitortests"
 	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/livestream"
 	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
 	"github.com/openshift/origin/pkg/monitortestframework"
 	"github.com/openshift/origin/pkg/riskanalysis"
@@ -62,12 +63,23 @@ type GinkgoRunSuiteOptions struct {
 
 	DryRun        bool
 	PrintCommands bool
+	PrintSchedule bool
 	genericclioptions.IOStreams
 
 	StartTime time.Time
 
-	ExactMonitorTests   []string
-	DisableMonitorTests []string
+	ExactMonitorTests    []string
+	DisableMonitorTests  []string
+	MonitorPhaseTimeouts []string
+	IntervalSpillDir     string
+	LiveStreamAddress    string
+	TestDurationsFile    string
+	ShardCount           int
+	ShardIndex           int
+	Resume               bool
+	QuarantineFile       string
+	QuarantineHistory    string
+	MinParallelism       int
 }
 
 func NewGinkgoRunSuiteOptions(streams genericclioptions.IOStreams) *GinkgoRunSuiteOptions {
@@ -82,6 +94,7 @@ func (o *GinkgoRunSuiteOptions) BindFlags(flags *pflag.FlagSet) {
 
 	flags.BoolVar(&o.DryRun, "dry-run", o.DryRun, "Print the tests to run without executing them.")
 	flags.BoolVar(&o.PrintCommands, "print-commands", o.PrintCommands, "Print the sub-commands that would be executed instead.")
+	flags.BoolVar(&o.PrintSchedule, "print-schedule", o.PrintSchedule, "Print the predicted wall time of each bucket of tests for one pass through the suite instead.  Requires --test-durations-file.")
 	flags.StringVar(&o.ClusterStabilityDuringTest, "cluster-stability", o.ClusterStabilityDuringTest, "cluster stability during test, usually dependent on the job: Stable or Disruptive. Empty default will be treated as Stable.")
 	flags.StringVar(&o.JUnitDir, "junit-dir", o.JUnitDir, "The directory to write test reports to.")
 	flags.IntVar(&o.Count, "count", o.Count, "Run each test a specified number of times. Defaults to 1 or the suite's preferred value. -1 will run forever.")
@@ -89,9 +102,20 @@ func (o *GinkgoRunSuiteOptions) BindFlags(flags *pflag.FlagSet) {
 	flags.DurationVar(&o.Timeout, "timeout", o.Timeout, "Set the maximum time a test can run before being aborted. This is read from the suite by default, but will be 10 minutes otherwise.")
 	flags.BoolVar(&o.IncludeSuccessOutput, "include-success", o.IncludeSuccessOutput, "Print output from successful tests.")
 	flags.IntVar(&o.Parallelism, "max-parallel-tests", o.Parallelism, "Maximum number of tests running in parallel. 0 defaults to test suite recommended value, which is different in each suite.")
+	flags.IntVar(&o.MinParallelism, "min-parallel-tests", o.MinParallelism, "If set, the number of tests running in parallel adapts to the health of the cluster: it shrinks toward this many while there is backend disruption, API server 429 or 5xx errors, or NotReady nodes, and grows back to --max-parallel-tests afterwards.  Each change is recorded as an interval.")
 	flags.StringSliceVar(&o.ExactMonitorTests, "monitor", o.ExactMonitorTests,
 		fmt.Sprintf("list of exactly which monitors to enable. All others will be disabled.  Current monitors are: [%s]", strings.Join(monitorNames, ", ")))
 	flags.StringSliceVar(&o.DisableMonitorTests, "disable-monitor", o.DisableMonitorTests, "list of monitors to disable.  Defaults for others will be honored.")
+	flags.StringSliceVar(&o.MonitorPhaseTimeouts, "monitor-phase-timeout", o.MonitorPhaseTimeouts,
+		"list of [<monitor>/]<phase>=<duration> bounding how long monitors may spend in a phase, for instance CollectData=30m.  Phases have no timeout unless one is set here, a duration of 0 disables the timeout.")
+	flags.StringVar(&o.IntervalSpillDir, "interval-spill-dir", o.IntervalSpillDir, "If set, monitor intervals are spilled to an append-only log in this directory instead of being held in memory.  Useful for long runs or --count=-1.")
+	flags.StringVar(&o.LiveStreamAddress, "live-stream-address", o.LiveStreamAddress, "If set, serve the intervals, tracked resources, and test progress of this run over HTTP on this address, for instance localhost:8080.")
+	flags.StringVar(&o.TestDurationsFile, "test-durations-file", o.TestDurationsFile, "If set, a JSON list of {\"TestName\", \"P50\", \"P95\"} historical test durations in seconds.  The longest tests of each bucket are started first.")
+	flags.IntVar(&o.ShardCount, "shard-count", o.ShardCount, "Split the suite into this many shards, each run by a separate invocation against the same cluster.  Tests are split by a stable hash of their name, or by --test-durations-file when set.  Combine the results with merge-results.")
+	flags.IntVar(&o.ShardIndex, "shard-index", o.ShardIndex, "The shard to run, from 0 to --shard-count - 1.  [Serial], [Early], and [Late] tests all run on shard 0, and so do the monitor tests.")
+	flags.BoolVar(&o.Resume, "resume", o.Resume, "Resume an interrupted run from the checkpoint it left in --junit-dir.  Tests that already completed are not run again and their results are included in the final junit.")
+	flags.StringVar(&o.QuarantineFile, "quarantine-file", o.QuarantineFile, "If set, a JSON list of {\"name\" or \"regex\", \"owner\", \"jira\", \"expires\"} quarantined tests to use instead of the built-in quarantine.  Failures of quarantined tests are reported as flakes.")
+	flags.StringVar(&o.QuarantineHistory, "quarantine-history-file", o.QuarantineHistory, "If set, a JSON list of {\"TestName\", \"ConsecutivePasses\"} used to report quarantined tests that pass consistently enough to be released.")
 }
 
 func (o *GinkgoRunSuiteOptions) Validate() error {
@@ -134,24 +158,27 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 	var fallbackSyntheticTestResult []*junitapi.JUnitTestCase
 	if len(os.Getenv("OPENSHIFT_SKIP_EXTERNAL_TESTS")) == 0 {
 		buf := &bytes.Buffer{}
-		fmt.Fprintf(buf, "Attempting to pull tests from external binary...\n")
-		externalTests, err := externalTestsForSuite(ctx)
-		if err == nil {
-			filteredTests := []*testCase{}
-			for _, test := range tests {
-				// tests contains all the tests "registered" in openshif-tests binary,
-				// this also includes vendored k8s tests, since this path assumes we're
-				// using external binary to run these tests we need to remove them
-				// from the final lists, which contains:
-				// 1. origin tests, only
-				// 2. k8s tests, coming from external binary
-				if !strings.Contains(test.name, "[Suite:k8s]") {
-					filteredTests = append(filteredTests, test)
+		fmt.Fprintf(buf, "Attempting to pull tests from external binaries...\n")
+		externalTests, replacedLabels, err := externalTestsForSuite(ctx, externalBinaries, buf)
+		filteredTests := []*testCase{}
+	testLoop:
+		for _, test := range tests {
+			// tests contains all the tests "registered" in openshif-tests binary,
+			// this also includes vendored tests, such as the k8s tests, that an
+			// external binary now provides, so we need to remove them from the
+			// final lists, which contains:
+			// 1. origin tests, and the vendored tests of binaries that failed
+			// 2. tests coming from the external binaries
+			for _, label := range replacedLabels {
+				if strings.Contains(test.name, label) {
+					continue testLoop
 				}
 			}
-			tests = append(filteredTests, externalTests...)
-			fmt.Fprintf(buf, "Got %d tests from external binary\n", len(externalTests))
-		} else {
+			filteredTests = append(filteredTests, test)
+		}
+		tests = append(filteredTests, externalTests...)
+		fmt.Fprintf(buf, "Got %d tests from external binaries\n", len(externalTests))
+		if err != nil {
 			fmt.Fprintf(buf, "Falling back to built-in suite, failed reading external test suites: %v\n", err)
 			// adding this test twice (one failure here, and success below) will
 			// ensure it gets picked as flake further down in synthetic tests processing
@@ -191,6 +218,14 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 	if count == 0 {
 		count = suite.Count
 	}
+	if o.Resume {
+		if len(o.JUnitDir) == 0 {
+			return fmt.Errorf("--resume requires --junit-dir")
+		}
+		if count == -1 {
+			return fmt.Errorf("--resume cannot be used with --count=-1")
+		}
+	}
 
 	start := time.Now()
 	if o.StartTime.IsZero() {
@@ -205,12 +240,57 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 		timeout = 15 * time.Minute
 	}
 
-	testRunnerContext := newCommandContext(o.AsEnv(), timeout)
+	parallelism := o.Parallelism
+	if parallelism == 0 {
+		parallelism = suite.Parallelism
+	}
+	if parallelism == 0 {
+		parallelism = 10
+	}
+	if o.MinParallelism < 0 || o.MinParallelism > parallelism {
+		return fmt.Errorf("--min-parallel-tests must be between 0 and the maximum of %d tests in parallel", parallelism)
+	}
+
+	var durations TestDurations
+	if len(o.TestDurationsFile) > 0 {
+		durations, err = LoadTestDurations(o.TestDurationsFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	quarantine, err := LoadQuarantine(o.QuarantineFile)
+	if err != nil {
+		return err
+	}
+	var quarantineHistory QuarantinePassHistory
+	if len(o.QuarantineHistory) > 0 {
+		quarantineHistory, err = LoadQuarantinePassHistory(o.QuarantineHistory)
+		if err != nil {
+			return err
+		}
+	}
+
+	if o.ShardCount != 0 {
+		if err := validateShard(o.ShardIndex, o.ShardCount); err != nil {
+			return err
+		}
+		tests = shardTests(tests, o.ShardIndex, o.ShardCount, durations)
+		fmt.Fprintf(o.Out, "found %d tests for shard %d of %d\n", len(tests), o.ShardIndex, o.ShardCount)
+	}
+
+	testRunnerContext := newCommandContext(o.AsEnv(), timeout, o.JUnitDir)
 
 	if o.PrintCommands {
-		newParallelTestQueue(testRunnerContext).OutputCommands(ctx, tests, o.Out)
+		newParallelTestQueue(testRunnerContext, durations, r, nil).OutputCommands(ctx, tests, o.Out)
 		return nil
 	}
+	if o.PrintSchedule {
+		if durations == nil {
+			return fmt.Errorf("--print-schedule requires --test-durations-file")
+		}
+		return bucketTests(tests).printSchedule(o.Out, durations, parallelism)
+	}
 	if o.DryRun {
 		for _, test := range sortedTests(tests) {
 			fmt.Fprintf(o.Out, "%q\n", test.name)
@@ -240,12 +320,25 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 		}
 	}
 
-	parallelism := o.Parallelism
-	if parallelism == 0 {
-		parallelism = suite.Parallelism
-	}
-	if parallelism == 0 {
-		parallelism = 10
+	var checkpoint *checkpointJournal
+	var results *testResultsWriter
+	var resumed []*testCase
+	if len(o.JUnitDir) > 0 {
+		var checkpointEntries []checkpointEntry
+		checkpoint, checkpointEntries, err = openCheckpointJournal(o.JUnitDir, o.Resume)
+		if err != nil {
+			return err
+		}
+		defer checkpoint.Close()
+		results, err = openTestResults(o.JUnitDir, o.Resume)
+		if err != nil {
+			return err
+		}
+		defer results.Close()
+		resumed = resumedTests(checkpointEntries)
+		if o.Resume {
+			fmt.Fprintf(o.Out, "resuming with %d completed tests from %s\n", len(resumed), filepath.Join(o.JUnitDir, checkpointFilename))
+		}
 	}
 
 	ctx, cancelFn := context.WithCancel(context.Background())
@@ -266,15 +359,40 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 	}()
 	signal.Notify(abortCh, syscall.SIGINT, syscall.SIGTERM)
 
-	monitorTests, err := defaultmonitortests.NewMonitorTestsFor(monitorTestInfo)
+	monitorTests, err := defaultmonitortests.NewMonitorTestsFor(monitorTestsForShard(monitorTestInfo, o.ShardIndex, o.ShardCount))
 	if err != nil {
 		logrus.Errorf("Error getting monitor tests: %v", err)
 	}
 
-	monitorEventRecorder := monitor.NewRecorder()
+	monitorEventRecorder, err := monitor.NewRecorderFor(o.IntervalSpillDir)
+	if err != nil {
+		return err
+	}
+	currentProgress := &currentTestSuiteProgress{}
+	if len(o.LiveStreamAddress) > 0 {
+		var liveStream *livestream.Server
+		liveStream, monitorEventRecorder = livestream.NewServer(monitorEventRecorder, func() interface{} {
+			return currentProgress.Status()
+		})
+		if err := liveStream.Start(ctx, o.LiveStreamAddress); err != nil {
+			return err
+		}
+	}
+	var adaptive *adaptiveParallelism
+	var workerLimit *workerLimit
+	monitorRESTConfig := restConfig
+	if o.MinParallelism > 0 {
+		health := newClusterHealth(monitorEventRecorder)
+		monitorEventRecorder = health
+		// the monitor tests watch and sample the API server, their 429 and 5xx responses tell it is overloaded.
+		monitorRESTConfig = rest.CopyConfig(restConfig)
+		monitorRESTConfig.Wrap(health.wrapTransport)
+		adaptive = newAdaptiveParallelism(health, o.MinParallelism, parallelism)
+		workerLimit = adaptive.limit
+	}
 	m := monitor.NewMonitor(
 		monitorEventRecorder,
-		restConfig,
+		monitorRESTConfig,
 		o.JUnitDir,
 		monitorTests,
 	)
@@ -296,27 +414,12 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 		includeSuccess = true
 	}
 	testOutputLock := &sync.Mutex{}
-	testOutputConfig := newTestOutputConfig(testOutputLock, o.Out, monitorEventRecorder, includeSuccess)
-
-	early, notEarly := splitTests(tests, func(t *testCase) bool {
-		return strings.Contains(t.name, "[Early]")
-	})
-
-	late, primaryTests := splitTests(notEarly, func(t *testCase) bool {
-		return strings.Contains(t.name, "[Late]")
-	})
-
-	kubeTests, openshiftTests := splitTests(primaryTests, func(t *testCase) bool {
-		return strings.Contains(t.name, "[Suite:k8s]")
-	})
+	testOutputConfig := newTestOutputConfig(testOutputLock, o.Out, monitorEventRecorder, currentProgress, checkpoint, results, includeSuccess)
 
-	storageTests, kubeTests := splitTests(kubeTests, func(t *testCase) bool {
-		return strings.Contains(t.name, "[sig-storage]")
-	})
-
-	mustGatherTests, openshiftTests := splitTests(openshiftTests, func(t *testCase) bool {
-		return strings.Contains(t.name, "[sig-cli] oc adm must-gather")
-	})
+	buckets := bucketTests(tests)
+	early, late := buckets.early, buckets.late
+	kubeTests, storageTests := buckets.kube, buckets.storage
+	openshiftTests, mustGatherTests := buckets.openshift, buckets.mustGather
 
 	// If user specifies a count, duplicate the kube and openshift tests that many times.
 	expectedTestCount := len(early) + len(late)
@@ -335,16 +438,35 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 	}
 	expectedTestCount += len(openshiftTests) + len(kubeTests) + len(storageTests) + len(mustGatherTests)
 
+	if len(resumed) > 0 {
+		completed := map[string]int{}
+		for _, test := range resumed {
+			completed[test.name]++
+		}
+		early = skipCompleted(early, completed)
+		kubeTests = skipCompleted(kubeTests, completed)
+		storageTests = skipCompleted(storageTests, completed)
+		openshiftTests = skipCompleted(openshiftTests, completed)
+		mustGatherTests = skipCompleted(mustGatherTests, completed)
+		late = skipCompleted(late, completed)
+	}
+
 	abortFn := neverAbort
 	testCtx := ctx
 	if o.FailFast {
 		abortFn, testCtx = abortOnFailure(ctx)
 	}
 
-	tests = nil
+	// the tests that completed before the run was interrupted are reported as if they ran now
+	tests = append([]*testCase{}, resumed...)
 
 	// run our Early tests
-	q := newParallelTestQueue(testRunnerContext)
+	if adaptive != nil {
+		adaptive.start(ctx)
+		defer adaptive.stop()
+	}
+
+	q := newParallelTestQueue(testRunnerContext, durations, r, workerLimit)
 	q.Execute(testCtx, early, parallelism, testOutputConfig, abortFn)
 	tests = append(tests, early...)
 
@@ -360,7 +482,7 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 
 		// I thought about randomizing the order of the kube, storage, and openshift tests, but storage dominates our e2e runs, so it doesn't help much.
 		storageTestsCopy := copyTests(storageTests)
-		q.Execute(testCtx, storageTestsCopy, max(1, parallelism/2), testOutputConfig, abortFn) // storage tests only run at half the parallelism, so we can avoid cloud provider quota problems.
+		q.Execute(testCtx, storageTestsCopy, storageParallelism(parallelism), testOutputConfig, abortFn)
 		tests = append(tests, storageTestsCopy...)
 
 		openshiftTestsCopy := copyTests(openshiftTests)
@@ -376,6 +498,11 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 	// TODO: will move to the monitor
 	pc.SetEvents([]string{postUpgradeEvent})
 
+	// the upgrade changed the cluster the remaining tests run against
+	if upgrade {
+		testRunnerContext.testProvider.refresh()
+	}
+
 	// run Late test suits after everything else
 	q.Execute(testCtx, late, parallelism, testOutputConfig, abortFn)
 	tests = append(tests, late...)
@@ -409,70 +536,45 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 	pass, fail, skip, failing := summarizeTests(tests)
 
 	// attempt to retry failures to do flake detection
-	if fail > 0 && fail <= suite.MaximumAllowedFlakes {
-		var retries []*testCase
-
-		// Make a copy of the all failing tests (subject to the max allowed flakes) so we can have
-		// a list of tests to retry.
-		for _, test := range failing {
-			retry := test.Retry()
-			retries = append(retries, retry)
-			if len(retries) > suite.MaximumAllowedFlakes {
-				break
-			}
-		}
-
-		fmt.Fprintf(o.Out, "Retry count: %d\n", len(retries))
-
-		// Run the tests in the retries list.
-		q := newParallelTestQueue(testRunnerContext)
-		q.Execute(testCtx, retries, parallelism, testOutputConfig, abortFn)
-
-		var flaky, skipped []string
-		var repeatFailures []*testCase
-		for _, test := range retries {
-			if test.success {
-				flaky = append(flaky, test.name)
-			} else if test.skipped {
-				skipped = append(skipped, test.name)
-			} else {
-				repeatFailures = append(repeatFailures, test)
-			}
-		}
+	retryPolicy := suite.retryPolicy()
+	if fail > 0 {
+		q := newParallelTestQueue(testRunnerContext, durations, r, workerLimit)
+		outcome := retryPolicy.retry(failing, parallelism, func(retries []*testCase, parallelism int) {
+			fmt.Fprintf(o.Out, "Retry count: %d\n", len(retries))
+			q.Execute(testCtx, retries, parallelism, testOutputConfig, abortFn)
+		})
 
-		// Add the list of retries into the list of all tests.
-		for _, retry := range retries {
-			if retry.flake {
-				// Retry tests that flaked are omitted so that the original test is counted as a failure.
-				fmt.Fprintf(o.Out, "Ignoring retry that returned a flake, original failure is authoritative for test: %s\n", retry.name)
-				continue
-			}
-			tests = append(tests, retry)
-		}
-		if len(flaky) > 0 {
-			failing = repeatFailures
-			sort.Strings(flaky)
+		// Add the retries into the list of all tests, each attempt is reported on its own.
+		tests = append(tests, outcome.retries...)
+		failing = outcome.failing
+		if len(outcome.flaky) > 0 {
+			flaky := sets.NewString(outcome.flaky...).List()
 			fmt.Fprintf(o.Out, "Flaky tests:\n\n%s\n\n", strings.Join(flaky, "\n"))
 		}
-		if len(skipped) > 0 {
-			// If a retry test got skipped, it means we very likely failed a precondition in the first failure, so
-			// we need to remove the failure case.
-			var withoutPreconditionFailures []*testCase
-		testLoop:
-			for _, t := range tests {
-				for _, st := range skipped {
-					if t.name == st && t.failed {
-						continue testLoop
-					}
-					withoutPreconditionFailures = append(withoutPreconditionFailures, t)
-				}
+		if len(outcome.skipped) > 0 {
+			skipped := sets.NewString(outcome.skipped...)
+			if retryPolicy.FailThenSkip == FailThenSkipIgnoreFailure {
+				// If a retry test got skipped, it means we very likely failed a precondition in the first failure, so
+				// we need to remove the failure case.
+				tests, _ = splitTests(tests, func(t *testCase) bool { return !(t.failed && skipped.Has(t.name)) })
 			}
-			tests = withoutPreconditionFailures
-			failing = repeatFailures
-			sort.Strings(skipped)
-			fmt.Fprintf(o.Out, "Skipped tests that failed a precondition:\n\n%s\n\n", strings.Join(skipped, "\n"))
+			fmt.Fprintf(o.Out, "Skipped tests that failed a precondition:\n\n%s\n\n", strings.Join(skipped.List(), "\n"))
+		}
+	}
 
+	// failures of quarantined tests are reported as flakes until their quarantine expires
+	failing, quarantineResults := quarantine.excuseFailures(failing, time.Now())
+	if len(quarantineResults) > 0 {
+		quarantined := []string{}
+		for _, result := range quarantineResults {
+			quarantined = append(quarantined, result.Name)
 		}
+		fmt.Fprintf(o.Out, "Quarantined tests that failed, reported as flakes:\n\n%s\n\n", strings.Join(quarantined, "\n"))
+	}
+
+	// stop adapting before the monitor stops, so the interval of the current parallelism ends with the tests.
+	if adaptive != nil {
+		adaptive.stop()
 	}
 
 	// monitor the cluster while the tests are running and report any detected anomalies
@@ -543,6 +645,23 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 		wasMasterNodeUpdated = clusterinfo.WasMasterNodeUpdated(events)
 	}
 
+	providerResults := testRunnerContext.testProvider.junitResults()
+	syntheticTestResults = append(syntheticTestResults, providerResults...)
+	if len(providerResults) > 0 && providerResults[0].FailureOutput != nil {
+		fmt.Fprintf(o.Out, "Cluster discovery failed:\n\n%s\n\n", providerResults[0].FailureOutput.Output)
+		// discovery that succeeded for the other test processes is a flake
+		syntheticFailure = syntheticFailure || len(providerResults) == 1
+	}
+
+	syntheticTestResults = append(syntheticTestResults, quarantineResults...)
+	if result := quarantine.check(tests, quarantineHistory, time.Now()); result != nil {
+		syntheticTestResults = append(syntheticTestResults, result)
+		if result.FailureOutput != nil {
+			fmt.Fprintf(o.Out, "Quarantines to lift:\n\n%s\n\n", result.FailureOutput.Output)
+			syntheticFailure = true
+		}
+	}
+
 	// report the outcome of the test
 	if len(failing) > 0 {
 		names := sets.NewString(testNames(failing)...).List()
@@ -551,6 +670,12 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 
 	if len(o.JUnitDir) > 0 {
 		finalSuiteResults := generateJUnitTestSuiteResults(junitSuiteName, duration, tests, syntheticTestResults...)
+		if o.Resume {
+			finalSuiteResults.Properties = append(finalSuiteResults.Properties, &junitapi.TestSuiteProperty{
+				Name:  "ResumedTests",
+				Value: strconv.Itoa(len(resumed)),
+			})
+		}
 		if err := writeJUnitReport(finalSuiteResults, "junit_e2e", timeSuffix, o.JUnitDir, o.ErrOut); err != nil {
 			fmt.Fprintf(o.Out, "error: Unable to write e2e JUnit xml results: %v", err)
 		}
@@ -561,7 +686,7 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 	}
 
 	if fail > 0 {
-		if len(failing) > 0 || suite.MaximumAllowedFlakes == 0 {
+		if len(failing) > 0 || (retryPolicy.Budget == 0 && len(quarantineResults) == 0) {
 			return fmt.Errorf("%d fail, %d pass, %d skip (%s)", fail, pass, skip, duration)
 		}
 		fmt.Fprintf(o.Out, "%d flakes detected, suite allows passing with only flakes\n\n", fail)
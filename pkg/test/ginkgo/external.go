@@ -14,32 +14,194 @@ import (
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/errors"
 
 	imagev1 "github.com/openshift/api/image/v1"
 	"github.com/openshift/origin/test/extended/util"
 )
 
+// External test binaries are shipped in the release payload and speak a small protocol so openshift-tests can list
+// and run their tests along with its own.  A binary is registered in externalBinaries by the tag of its image in the
+// release payload and its path in that image.  Version v1 of the protocol has three commands, each writing JSON, and
+// only JSON, to stdout.  Anything written to stderr is treated as log output.
+//
+//	<binary> info
+//	    {"apiVersion": "v1", "name": "<component>", "version": "<version of the binary>"}
+//
+//	<binary> list
+//	    [{"name": "<test name>", "labels": "<labels appended to the name, such as [Suite:k8s]>"}, ...]
+//
+//	<binary> run-test <test name>
+//	    {"name": "<test name>", "result": "passed|failed|skipped|flaked", "output": "<test output>", "error": "<why>"}
+//
+// The exit code of run-test is not used once a result is written.  A test that does not finish within its timeout is
+// sent SIGINT, then SIGABRT a minute later, and is reported as timed out.
+//
+// Binaries that predate the protocol have no info command.  They list tests as JSON arrays on lines starting with
+// "[{" among other output, and report the result of run-test with its exit code: 0 passed, 1 failed, 2 timed out,
+// 3 skipped, and 4 flaked.
+const (
+	externalAPIVersionV1 = "v1"
+	// legacyExternalAPIVersion is the protocol of binaries without an info command.
+	legacyExternalAPIVersion = "legacy"
+)
+
+// ExternalBinary describes a test binary in the release payload.
+type ExternalBinary struct {
+	// ImageTag is the tag of the image in the release payload that contains the binary.
+	ImageTag string
+	// Path is the path of the binary in the image.
+	Path string
+	// ReplacesLabel is set for a binary that provides tests that are also built into openshift-tests.  Once the
+	// tests of the binary are listed, the built-in tests with this label in their name are dropped.
+	ReplacesLabel string
+}
+
+func (b ExternalBinary) String() string {
+	return fmt.Sprintf("%s:%s", b.ImageTag, b.Path)
+}
+
+// externalBinaries are the binaries whose tests are merged into every suite.
+var externalBinaries = []ExternalBinary{
+	{ImageTag: "hyperkube", Path: "/usr/bin/k8s-tests", ReplacesLabel: "[Suite:k8s]"},
+}
+
+// externalBinary is an ExternalBinary extracted from the release payload.
+type externalBinary struct {
+	ExternalBinary
+	// path is where the binary was extracted to.
+	path string
+	// apiVersion is the version of the protocol the binary speaks.
+	apiVersion string
+}
+
+// ExternalBinaryInfo is what the info command writes.
+type ExternalBinaryInfo struct {
+	APIVersion string `json:"apiVersion"`
+	Name       string `json:"name"`
+	Version    string `json:"version,omitempty"`
+}
+
+// serializedTest is a test written by the list command.
 type serializedTest struct {
-	Name   string
-	Labels string
+	Name   string `json:"name"`
+	Labels string `json:"labels,omitempty"`
+}
+
+// ExternalTestResult is what the run-test command writes.
+type ExternalTestResult struct {
+	Name   string `json:"name"`
+	Result string `json:"result"`
+	Output string `json:"output,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+func (r ExternalTestResult) testState() (TestState, error) {
+	switch r.Result {
+	case "passed":
+		return TestSucceeded, nil
+	case "failed":
+		return TestFailed, nil
+	case "skipped":
+		return TestSkipped, nil
+	case "flaked":
+		return TestFlaked, nil
+	}
+	return TestUnknown, fmt.Errorf("unknown result %q", r.Result)
 }
 
-// externalTestsForSuite reads tests from external binary, currently only
-// k8s-tests is supported
-func externalTestsForSuite(ctx context.Context) ([]*testCase, error) {
+// externalTestsForSuite reads the tests of every binary.  It returns the tests of the binaries it could read, the
+// labels of the built-in tests they replace, and an error for each binary it could not read, whose tests are then
+// run from the built-in suite where possible.
+func externalTestsForSuite(ctx context.Context, binaries []ExternalBinary, out io.Writer) ([]*testCase, []string, error) {
 	var tests []*testCase
+	var replacedLabels []string
+	var errs []error
+	for _, binary := range binaries {
+		extracted, err := extractExternalBinary(ctx, binary)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		binaryTests, err := extracted.listTests(ctx)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		fmt.Fprintf(out, "Got %d tests from %s (protocol %s)\n", len(binaryTests), binary, extracted.apiVersion)
+		tests = append(tests, binaryTests...)
+		if len(binary.ReplacesLabel) > 0 {
+			replacedLabels = append(replacedLabels, binary.ReplacesLabel)
+		}
+	}
+	return tests, replacedLabels, errors.NewAggregate(errs)
+}
 
-	// TODO: add support for binaries from other images
-	testBinary, err := extractBinaryFromReleaseImage("hyperkube", "/usr/bin/k8s-tests")
+// extractExternalBinary extracts the binary from the release payload and finds the protocol it speaks.
+func extractExternalBinary(ctx context.Context, binary ExternalBinary) (*externalBinary, error) {
+	path, err := extractBinaryFromReleaseImage(binary.ImageTag, binary.Path)
 	if err != nil {
-		return nil, fmt.Errorf("unable to extract k8s-tests binary: %w", err)
+		return nil, fmt.Errorf("unable to extract %s binary: %w", binary, err)
 	}
 
-	command := exec.Command(testBinary, "list")
-	testList, err := runWithTimeout(ctx, command, 1*time.Minute)
+	ret := &externalBinary{
+		ExternalBinary: binary,
+		path:           path,
+		apiVersion:     legacyExternalAPIVersion,
+	}
+	// binaries that fail info or do not write JSON predate the protocol.
+	stdout, err := runStdoutWithTimeout(ctx, exec.Command(path, "info"), 1*time.Minute, io.Discard)
 	if err != nil {
-		return nil, fmt.Errorf("failed running '%s list': %w", testBinary, err)
+		return ret, nil
+	}
+	info := ExternalBinaryInfo{}
+	if err := json.Unmarshal(stdout, &info); err != nil || len(info.APIVersion) == 0 {
+		return ret, nil
+	}
+	if info.APIVersion != externalAPIVersionV1 {
+		return nil, fmt.Errorf("%s speaks unsupported protocol version %q", binary, info.APIVersion)
+	}
+	ret.apiVersion = info.APIVersion
+	return ret, nil
+}
+
+func (b *externalBinary) listTests(ctx context.Context) ([]*testCase, error) {
+	var serializedTests []serializedTest
+	switch b.apiVersion {
+	case externalAPIVersionV1:
+		stdout, err := runStdoutWithTimeout(ctx, exec.Command(b.path, "list"), 1*time.Minute, io.Discard)
+		if err != nil {
+			return nil, fmt.Errorf("failed running '%s list': %w", b, err)
+		}
+		if err := json.Unmarshal(stdout, &serializedTests); err != nil {
+			return nil, fmt.Errorf("failed reading '%s list': %w", b, err)
+		}
+	default:
+		testList, err := runWithTimeout(ctx, exec.Command(b.path, "list"), 1*time.Minute)
+		if err != nil {
+			return nil, fmt.Errorf("failed running '%s list': %w", b, err)
+		}
+		serializedTests, err = parseLegacyTestList(testList)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading '%s list': %w", b, err)
+		}
 	}
+
+	tests := []*testCase{}
+	for _, test := range serializedTests {
+		tests = append(tests, &testCase{
+			name:          test.Name + test.Labels,
+			rawName:       test.Name,
+			binary:        b,
+			testExclusion: exclusionGroup(test.Name + test.Labels),
+		})
+	}
+	return tests, nil
+}
+
+// parseLegacyTestList reads the JSON arrays of tests on the lines starting with "[{".
+func parseLegacyTestList(testList []byte) ([]serializedTest, error) {
+	ret := []serializedTest{}
 	buf := bytes.NewBuffer(testList)
 	for {
 		line, err := buf.ReadString('\n')
@@ -50,19 +212,25 @@ func externalTestsForSuite(ctx context.Context) ([]*testCase, error) {
 			continue
 		}
 		serializedTests := []serializedTest{}
-		err = json.Unmarshal([]byte(line), &serializedTests)
-		if err != nil {
+		if err := json.Unmarshal([]byte(line), &serializedTests); err != nil {
 			return nil, err
 		}
-		for _, test := range serializedTests {
-			tests = append(tests, &testCase{
-				name:       test.Name + test.Labels,
-				rawName:    test.Name,
-				binaryName: testBinary,
-			})
-		}
+		ret = append(ret, serializedTests...)
 	}
-	return tests, nil
+	return ret, nil
+}
+
+// reportsResults is true when run-test writes an ExternalTestResult.
+func (b *externalBinary) reportsResults() bool {
+	return b != nil && b.apiVersion == externalAPIVersionV1
+}
+
+// source is the provenance of the tests of the binary reported in junit, empty for the built-in tests.
+func (b *externalBinary) source() string {
+	if b == nil {
+		return ""
+	}
+	return b.ExternalBinary.String()
 }
 
 // extractBinaryFromReleaseImage is responsible for resolving the tag from
@@ -0,0 +1,113 @@
+package ginkgo
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBinary writes a shell script that acts as an external test binary.
+func fakeBinary(t *testing.T, script string) string {
+	path := filepath.Join(t.TempDir(), "fake-tests")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755))
+	return path
+}
+
+func TestListExternalTests(t *testing.T) {
+	v1 := &externalBinary{
+		ExternalBinary: ExternalBinary{ImageTag: "fake", Path: "/usr/bin/fake-tests"},
+		path: fakeBinary(t, `echo "starting" >&2
+echo '[{"name": "[sig-fake] works", "labels": " [Suite:fake]"}, {"name": "[sig-fake] [Exclusive:config] changes config"}]'
+`),
+		apiVersion: externalAPIVersionV1,
+	}
+	tests, err := v1.listTests(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, tests, 2)
+	assert.Equal(t, "[sig-fake] works [Suite:fake]", tests[0].name)
+	assert.Equal(t, "[sig-fake] works", tests[0].rawName)
+	assert.Equal(t, "fake:/usr/bin/fake-tests", tests[0].binary.source())
+	assert.Equal(t, "config", tests[1].testExclusion)
+	assert.Equal(t, tests[0].binary, tests[0].Retry().binary, "retries must run from the same binary")
+
+	legacy := &externalBinary{
+		path: fakeBinary(t, `echo "some logging"
+echo '[{"Name": "[sig-fake] legacy", "Labels": " [Suite:k8s]"}]'
+`),
+		apiVersion: legacyExternalAPIVersion,
+	}
+	tests, err = legacy.listTests(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"[sig-fake] legacy [Suite:k8s]"}, testNames(tests))
+}
+
+func TestRunExternalTest(t *testing.T) {
+	tests := []struct {
+		name       string
+		script     string
+		wantResult string
+		wantOutput string
+		wantErr    bool
+	}{
+		{
+			name: "failure reported with exit code 0",
+			script: `echo "log line" >&2
+echo '{"name": "t", "result": "failed", "output": "test output", "error": "expected 1, got 2"}'`,
+			wantResult: "failed",
+			wantOutput: "test output\nexpected 1, got 2\nlog line\n",
+		},
+		{
+			name: "skip reported with a non-zero exit code",
+			script: `echo '{"name": "t", "result": "skipped", "output": "skip [not supported]"}'
+exit 3`,
+			wantResult: "skipped",
+			wantOutput: "skip [not supported]",
+			wantErr:    true,
+		},
+		{
+			name:       "no result",
+			script:     `echo "not json"`,
+			wantOutput: "not json\n\ntest did not report a result: invalid character 'o' in literal null (expecting 'u')\n",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, result, err := runExternalTestWithTimeout(context.TODO(), exec.Command(fakeBinary(t, tt.script)), time.Minute)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantOutput, string(output))
+			if len(tt.wantResult) == 0 {
+				assert.Nil(t, result)
+				return
+			}
+			require.NotNil(t, result)
+			assert.Equal(t, tt.wantResult, result.Result)
+		})
+	}
+}
+
+func TestExternalTestResultState(t *testing.T) {
+	for result, want := range map[string]TestState{
+		"passed":  TestSucceeded,
+		"failed":  TestFailed,
+		"skipped": TestSkipped,
+		"flaked":  TestFlaked,
+	} {
+		state, err := ExternalTestResult{Result: result}.testState()
+		assert.NoError(t, err)
+		assert.Equal(t, want, state)
+	}
+	state, err := ExternalTestResult{Result: "exploded"}.testState()
+	assert.Error(t, err)
+	assert.Equal(t, TestUnknown, state)
+}
@@ -40,6 +40,8 @@ func generateJUnitTestSuiteResults(
 				Name:      test.name,
 				SystemOut: string(test.testOutputBytes),
 				Duration:  test.duration.Seconds(),
+				Attempt:   test.attempt(),
+				Binary:    test.binary.source(),
 				SkipMessage: &junitapi.SkipMessage{
 					Message: lastLinesUntil(string(test.testOutputBytes), 100, "skip ["),
 				},
@@ -51,6 +53,8 @@ func generateJUnitTestSuiteResults(
 				Name:      test.name,
 				SystemOut: string(test.testOutputBytes),
 				Duration:  test.duration.Seconds(),
+				Attempt:   test.attempt(),
+				Binary:    test.binary.source(),
 				FailureOutput: &junitapi.FailureOutput{
 					Output: lastLinesUntil(string(test.testOutputBytes), 100, "fail ["),
 				},
@@ -62,6 +66,8 @@ func generateJUnitTestSuiteResults(
 				Name:      test.name,
 				SystemOut: string(test.testOutputBytes),
 				Duration:  test.duration.Seconds(),
+				Attempt:   test.attempt(),
+				Binary:    test.binary.source(),
 				FailureOutput: &junitapi.FailureOutput{
 					Output: lastLinesUntil(string(test.testOutputBytes), 100, "flake:"),
 				},
@@ -72,12 +78,16 @@ func generateJUnitTestSuiteResults(
 			s.TestCases = append(s.TestCases, &junitapi.JUnitTestCase{
 				Name:     test.name,
 				Duration: test.duration.Seconds(),
+				Attempt:  test.attempt(),
+				Binary:   test.binary.source(),
 			})
 		case test.success:
 			s.NumTests++
 			s.TestCases = append(s.TestCases, &junitapi.JUnitTestCase{
 				Name:     test.name,
 				Duration: test.duration.Seconds(),
+				Attempt:  test.attempt(),
+				Binary:   test.binary.source(),
 			})
 		}
 	}
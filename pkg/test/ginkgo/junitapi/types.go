@@ -66,6 +66,13 @@ type JUnitTestCase struct {
 	// Duration is the time taken in seconds to run the test
 	Duration float64 `xml:"time,attr"`
 
+	// Attempt is the 1-based index of this run of the test when failures are retried
+	Attempt int `xml:"attempt,attr,omitempty"`
+
+	// Binary is the external binary, as <release image tag>:<path>, the test came from, empty for tests built
+	// into openshift-tests
+	Binary string `xml:"binary,attr,omitempty"`
+
 	// SkipMessage holds the reason why the test was skipped
 	SkipMessage *SkipMessage `xml:"skipped"`
 
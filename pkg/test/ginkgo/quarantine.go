@@ -0,0 +1,230 @@
+package ginkgo
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// defaultQuarantine is the quarantine used unless --quarantine-file is set.
+//
+//go:embed quarantine.json
+var defaultQuarantine []byte
+
+const (
+	// DefaultQuarantineReleaseAfterPasses is how many runs in a row a quarantined test may pass before its quarantine
+	// has to be lifted.
+	DefaultQuarantineReleaseAfterPasses = 10
+
+	quarantineDateFormat = "2006-01-02"
+
+	quarantineTestName = "[sig-arch] quarantined tests should be released once their quarantine expires or they pass consistently"
+)
+
+// QuarantineEntry quarantines the tests named Name, or matching Regex.  A quarantined test still runs, but its
+// failures are reported as flakes until the entry expires.
+type QuarantineEntry struct {
+	Name  string `json:"name,omitempty"`
+	Regex string `json:"regex,omitempty"`
+	// Owner is who is responsible for fixing the test and lifting the quarantine.
+	Owner string `json:"owner"`
+	// Jira is the link to the bug tracking the fix.
+	Jira string `json:"jira"`
+	// Expires is the day, as YYYY-MM-DD in UTC, the quarantine ends.
+	Expires string `json:"expires"`
+	// ReleaseAfterPasses is how many runs in a row the test may pass before the quarantine has to be lifted,
+	// DefaultQuarantineReleaseAfterPasses when unset.
+	ReleaseAfterPasses int `json:"releaseAfterPasses,omitempty"`
+
+	regex   *regexp.Regexp
+	expires time.Time
+}
+
+func (e *QuarantineEntry) matches(name string) bool {
+	if e.regex != nil {
+		return e.regex.MatchString(name)
+	}
+	return e.Name == name
+}
+
+func (e *QuarantineEntry) String() string {
+	test := e.Name
+	if e.regex != nil {
+		test = fmt.Sprintf("tests matching %q", e.Regex)
+	}
+	return fmt.Sprintf("%s (owner %s, %s, expires %s)", test, e.Owner, e.Jira, e.Expires)
+}
+
+// Quarantine is the list of quarantined tests.
+type Quarantine struct {
+	entries []*QuarantineEntry
+}
+
+// LoadQuarantine reads a JSON list of QuarantineEntry from filename, or the built-in quarantine when filename is
+// empty.
+func LoadQuarantine(filename string) (*Quarantine, error) {
+	if len(filename) == 0 {
+		return parseQuarantine(defaultQuarantine, "built-in quarantine")
+	}
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return parseQuarantine(content, filename)
+}
+
+func parseQuarantine(content []byte, source string) (*Quarantine, error) {
+	entries := []*QuarantineEntry{}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&entries); err != nil {
+		return nil, fmt.Errorf("failed to read quarantine from %v: %w", source, err)
+	}
+
+	for i, entry := range entries {
+		switch {
+		case len(entry.Name) == 0 && len(entry.Regex) == 0:
+			return nil, fmt.Errorf("quarantine entry %d in %v must set name or regex", i, source)
+		case len(entry.Name) > 0 && len(entry.Regex) > 0:
+			return nil, fmt.Errorf("quarantine entry %d in %v must set only one of name or regex", i, source)
+		case len(entry.Owner) == 0:
+			return nil, fmt.Errorf("quarantine entry %d in %v must set an owner", i, source)
+		case len(entry.Jira) == 0:
+			return nil, fmt.Errorf("quarantine entry %d in %v must set a jira", i, source)
+		}
+		if len(entry.Regex) > 0 {
+			regex, err := regexp.Compile(entry.Regex)
+			if err != nil {
+				return nil, fmt.Errorf("quarantine entry %d in %v has an invalid regex: %w", i, source, err)
+			}
+			entry.regex = regex
+		}
+		expires, err := time.Parse(quarantineDateFormat, entry.Expires)
+		if err != nil {
+			return nil, fmt.Errorf("quarantine entry %d in %v must expire on a YYYY-MM-DD date: %w", i, source, err)
+		}
+		entry.expires = expires
+		if entry.ReleaseAfterPasses == 0 {
+			entry.ReleaseAfterPasses = DefaultQuarantineReleaseAfterPasses
+		}
+	}
+	return &Quarantine{entries: entries}, nil
+}
+
+// entryFor returns the first entry quarantining the test, or nil.
+func (q *Quarantine) entryFor(name string) *QuarantineEntry {
+	if q == nil {
+		return nil
+	}
+	for _, entry := range q.entries {
+		if entry.matches(name) {
+			return entry
+		}
+	}
+	return nil
+}
+
+// QuarantinePassHistory is how many runs in a row each test passed before this one, keyed by test name.
+type QuarantinePassHistory map[string]int
+
+// LoadQuarantinePassHistory reads a JSON list of {"TestName": ..., "ConsecutivePasses": ...}.
+func LoadQuarantinePassHistory(filename string) (QuarantinePassHistory, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	decoded := []struct {
+		TestName          string
+		ConsecutivePasses int
+	}{}
+	if err := json.Unmarshal(content, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to read quarantine pass history from %v: %w", filename, err)
+	}
+	ret := QuarantinePassHistory{}
+	for _, curr := range decoded {
+		ret[curr.TestName] = curr.ConsecutivePasses
+	}
+	return ret, nil
+}
+
+// excuseFailures removes the quarantined tests from failing and returns, for each of them, a passing junit result.
+// Next to the failures that are still reported, the passing result makes the failures read as a flake.  The failures
+// of a test whose quarantine has expired by now are not excused.
+func (q *Quarantine) excuseFailures(failing []*testCase, now time.Time) ([]*testCase, []*junitapi.JUnitTestCase) {
+	remaining := []*testCase{}
+	excused := map[string]*QuarantineEntry{}
+	for _, test := range failing {
+		entry := q.entryFor(test.name)
+		if entry == nil || !now.Before(entry.expires) {
+			remaining = append(remaining, test)
+			continue
+		}
+		excused[test.name] = entry
+	}
+
+	results := []*junitapi.JUnitTestCase{}
+	for _, name := range sets.List(sets.KeySet(excused)) {
+		results = append(results, &junitapi.JUnitTestCase{
+			Name:      name,
+			SystemOut: fmt.Sprintf("Failures are reported as flakes while the test is quarantined: %s", excused[name]),
+		})
+	}
+	return remaining, results
+}
+
+// check returns a junit result that fails when a quarantine matching the tests that ran has expired, or a test has
+// passed enough runs in a row that it no longer needs to be quarantined.  It returns nil when no test is quarantined.
+func (q *Quarantine) check(tests []*testCase, history QuarantinePassHistory, now time.Time) *junitapi.JUnitTestCase {
+	type result struct {
+		entry     *QuarantineEntry
+		succeeded bool
+		failed    bool
+	}
+	results := map[string]*result{}
+	for _, test := range tests {
+		entry := q.entryFor(test.name)
+		if entry == nil {
+			continue
+		}
+		if _, ok := results[test.name]; !ok {
+			results[test.name] = &result{entry: entry}
+		}
+		results[test.name].succeeded = results[test.name].succeeded || test.success
+		results[test.name].failed = results[test.name].failed || test.failed || test.flake
+	}
+	if len(results) == 0 {
+		return nil
+	}
+
+	problems := []string{}
+	for _, name := range sets.List(sets.KeySet(results)) {
+		result := results[name]
+		if !now.Before(result.entry.expires) {
+			problems = append(problems, fmt.Sprintf("quarantine expired: %s", result.entry))
+			continue
+		}
+		// only a run without failures that the test did not just skip extends its streak
+		if !result.succeeded || result.failed {
+			continue
+		}
+		if passes := history[name] + 1; passes >= result.entry.ReleaseAfterPasses {
+			problems = append(problems, fmt.Sprintf("passed %d runs in a row, lift the quarantine: %s", passes, result.entry))
+		}
+	}
+
+	ret := &junitapi.JUnitTestCase{Name: quarantineTestName}
+	if len(problems) > 0 {
+		output := strings.Join(problems, "\n")
+		ret.SystemOut = output
+		ret.FailureOutput = &junitapi.FailureOutput{Output: output}
+	}
+	return ret
+}
@@ -0,0 +1,106 @@
+package ginkgo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuarantine(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "valid",
+			content: `[{"name": "a", "owner": "me", "jira": "https://issues.redhat.com/browse/OCPBUGS-1", "expires": "2023-03-01"}, {"regex": "b.*", "owner": "me", "jira": "OCPBUGS-2", "expires": "2023-03-01", "releaseAfterPasses": 3}]`,
+		},
+		{
+			name:    "no test",
+			content: `[{"owner": "me", "jira": "OCPBUGS-1", "expires": "2023-03-01"}]`,
+			wantErr: "must set name or regex",
+		},
+		{
+			name:    "name and regex",
+			content: `[{"name": "a", "regex": "a", "owner": "me", "jira": "OCPBUGS-1", "expires": "2023-03-01"}]`,
+			wantErr: "only one of name or regex",
+		},
+		{
+			name:    "no owner",
+			content: `[{"name": "a", "jira": "OCPBUGS-1", "expires": "2023-03-01"}]`,
+			wantErr: "must set an owner",
+		},
+		{
+			name:    "bad date",
+			content: `[{"name": "a", "owner": "me", "jira": "OCPBUGS-1", "expires": "03012023"}]`,
+			wantErr: "YYYY-MM-DD",
+		},
+		{
+			name:    "unknown field",
+			content: `[{"name": "a", "owner": "me", "jira": "OCPBUGS-1", "expires": "2023-03-01", "expiry": "2023-03-01"}]`,
+			wantErr: "unknown field",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseQuarantine([]byte(tt.content), "test")
+			if len(tt.wantErr) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+
+	_, err := LoadQuarantine("")
+	assert.NoError(t, err, "the built-in quarantine must be valid")
+}
+
+func TestQuarantine(t *testing.T) {
+	quarantine, err := parseQuarantine([]byte(`[
+  {"name": "[sig-network] flaky", "owner": "network", "jira": "OCPBUGS-1", "expires": "2023-03-01", "releaseAfterPasses": 3},
+  {"regex": "^\\[sig-storage\\] csi", "owner": "storage", "jira": "OCPBUGS-2", "expires": "2023-02-01"}
+]`), "test")
+	require.NoError(t, err)
+	now := time.Date(2023, 2, 14, 20, 30, 0, 0, time.UTC)
+
+	flaky := &testCase{name: "[sig-network] flaky", failed: true}
+	csi := &testCase{name: "[sig-storage] csi attach", failed: true}
+	other := &testCase{name: "[sig-apps] other", failed: true}
+	beforeExpiry := time.Date(2023, 1, 14, 20, 30, 0, 0, time.UTC)
+	failing, excused := quarantine.excuseFailures([]*testCase{flaky, csi, other, csi.Retry()}, beforeExpiry)
+	assert.Equal(t, []string{"[sig-apps] other"}, testNames(failing))
+	require.Len(t, excused, 2)
+	assert.Equal(t, "[sig-network] flaky", excused[0].Name)
+	assert.Nil(t, excused[0].FailureOutput, "the excused failures must read as a flake")
+	assert.Contains(t, excused[0].SystemOut, "owner network, OCPBUGS-1, expires 2023-03-01")
+	assert.Equal(t, "[sig-storage] csi attach", excused[1].Name)
+
+	// the csi quarantine has expired, its failures are failures again.
+	failing, excused = quarantine.excuseFailures([]*testCase{flaky, csi, other}, now)
+	assert.Equal(t, []string{"[sig-storage] csi attach", "[sig-apps] other"}, testNames(failing))
+	require.Len(t, excused, 1)
+	assert.Equal(t, "[sig-network] flaky", excused[0].Name)
+
+	// the csi quarantine expired and is reported even though the test failed.
+	result := quarantine.check([]*testCase{flaky, csi, other}, nil, now)
+	require.NotNil(t, result)
+	require.NotNil(t, result.FailureOutput)
+	assert.Equal(t, `quarantine expired: tests matching "^\\[sig-storage\\] csi" (owner storage, OCPBUGS-2, expires 2023-02-01)`, result.FailureOutput.Output)
+
+	passing := &testCase{name: "[sig-network] flaky", success: true}
+	result = quarantine.check([]*testCase{passing}, QuarantinePassHistory{"[sig-network] flaky": 1}, now)
+	assert.Nil(t, result.FailureOutput, "two passes in a row are not enough to lift the quarantine")
+	result = quarantine.check([]*testCase{passing}, QuarantinePassHistory{"[sig-network] flaky": 2}, now)
+	require.NotNil(t, result.FailureOutput)
+	assert.Contains(t, result.FailureOutput.Output, "passed 3 runs in a row, lift the quarantine: [sig-network] flaky")
+
+	retried := &testCase{name: "[sig-network] flaky", failed: true}
+	result = quarantine.check([]*testCase{retried, passing}, QuarantinePassHistory{"[sig-network] flaky": 2}, now)
+	assert.Nil(t, result.FailureOutput, "a run with a failure ends the streak")
+
+	assert.Nil(t, quarantine.check([]*testCase{other}, nil, now), "nothing to report without quarantined tests")
+}
@@ -4,23 +4,36 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"math/rand"
 	"strings"
 	"sync"
 )
 
 // parallelByFileTestQueue runs tests in parallel unless they have
-// the `[Serial]` tag on their name or if another test with the
-// testExclusion field is currently running. Serial tests are
-// defered until all other tests are completed.
+// the `[Serial]` tag on their name or if another test with the same
+// testExclusion group is currently running. Serial tests are
+// defered until all other tests are completed.  When historical
+// durations are known, the longest tests are started first.
 type parallelByFileTestQueue struct {
 	commandContext *commandContext
+	durations      TestDurations
+	// random shuffles the tests with the same expected duration.
+	random *rand.Rand
+	// workerLimit optionally bounds the parallel tests across every Execute, below the parallelism of each.
+	workerLimit *workerLimit
 }
 
 type TestFunc func(ctx context.Context, test *testCase)
 
-func newParallelTestQueue(commandContext *commandContext) *parallelByFileTestQueue {
+// newParallelTestQueue creates a queue, durations may be nil to run the tests in the order they are given and
+// workerLimit may be nil to run as many tests as the parallelism of each Execute.  random is the seeded source the
+// tests of the suite were shuffled with, it may be nil to leave the tests with the same expected duration in order.
+func newParallelTestQueue(commandContext *commandContext, durations TestDurations, random *rand.Rand, workerLimit *workerLimit) *parallelByFileTestQueue {
 	return &parallelByFileTestQueue{
 		commandContext: commandContext,
+		durations:      durations,
+		random:         random,
+		workerLimit:    workerLimit,
 	}
 }
 
@@ -54,41 +67,89 @@ func abortOnFailure(parentContext context.Context) (testAbortFunc, context.Conte
 	}, testCtx
 }
 
-// queueAllTests writes all the tests to the channel and closes it when all are finished
-// even with buffering, this can take a while since we don't infinitely buffer.
-func queueAllTests(remainingParallelTests chan *testCase, tests []*testCase) {
-	for i := range tests {
-		curr := tests[i]
-		remainingParallelTests <- curr
-	}
+// exclusiveTestQueue hands out tests in order, passing over any test whose testExclusion group already has a test
+// running until that test is done.  It is threadsafe.
+type exclusiveTestQueue struct {
+	lock      sync.Mutex
+	cond      *sync.Cond
+	remaining []*testCase
+	// running holds the testExclusion groups that have a test running.
+	running map[string]bool
+}
 
-	close(remainingParallelTests)
+func newExclusiveTestQueue(tests []*testCase) *exclusiveTestQueue {
+	q := &exclusiveTestQueue{
+		remaining: tests,
+		running:   map[string]bool{},
+	}
+	q.cond = sync.NewCond(&q.lock)
+	return q
 }
 
-// runTestsUntilChannelEmpty reads from the channel to consume tests, run them, and return when the channel is closed.
-func runTestsUntilChannelEmpty(ctx context.Context, remainingParallelTests chan *testCase, testSuiteRunner testSuiteRunner) {
-	for {
-		select {
-		// if the context is finished, simply return
-		case <-ctx.Done():
-			return
+// next returns the first test that may start now, waiting while only tests of running groups remain.  It returns nil
+// when no tests remain or the context is finished.
+func (q *exclusiveTestQueue) next(ctx context.Context) *testCase {
+	q.lock.Lock()
+	defer q.lock.Unlock()
 
-		case test, ok := <-remainingParallelTests:
-			if !ok { // channel closed, then we're done
-				return
+	for {
+		if ctx.Err() != nil || len(q.remaining) == 0 {
+			return nil
+		}
+		for i, test := range q.remaining {
+			if len(test.testExclusion) > 0 && q.running[test.testExclusion] {
+				continue
 			}
-			// if the context is finished, simply return
-			if ctx.Err() != nil {
-				return
+			q.remaining = append(q.remaining[:i], q.remaining[i+1:]...)
+			if len(test.testExclusion) > 0 {
+				q.running[test.testExclusion] = true
 			}
-			testSuiteRunner.RunOneTest(ctx, test)
+			return test
 		}
+		q.cond.Wait()
+	}
+}
+
+// done lets the next test of the group of test start.
+func (q *exclusiveTestQueue) done(test *testCase) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if len(test.testExclusion) > 0 {
+		delete(q.running, test.testExclusion)
+	}
+	q.cond.Broadcast()
+}
+
+// wake releases the workers waiting in next, so they notice the context is finished.
+func (q *exclusiveTestQueue) wake() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.cond.Broadcast()
+}
+
+// runTestsUntilQueueEmpty takes tests from the queue, runs them, and returns when the queue is empty.  Each test
+// waits for room under the workerLimit before it is taken.
+func runTestsUntilQueueEmpty(ctx context.Context, remainingParallelTests *exclusiveTestQueue, workerLimit *workerLimit, testSuiteRunner testSuiteRunner) {
+	for {
+		if !workerLimit.acquire(ctx) {
+			return
+		}
+		test := remainingParallelTests.next(ctx)
+		if test == nil {
+			workerLimit.release()
+			return
+		}
+		testSuiteRunner.RunOneTest(ctx, test)
+		remainingParallelTests.done(test)
+		workerLimit.release()
 	}
 }
 
 // tests are currently being mutated during the run process.
 func (q *parallelByFileTestQueue) Execute(ctx context.Context, tests []*testCase, parallelism int, testOutput testOutputConfig, maybeAbortOnFailureFn testAbortFunc) {
 	testSuiteProgress := newTestSuiteProgress(len(tests))
+	testOutput.currentProgress.set(testSuiteProgress)
 	testSuiteRunner := &testSuiteRunnerImpl{
 		commandContext:        q.commandContext,
 		testOutput:            testOutput,
@@ -96,26 +157,32 @@ func (q *parallelByFileTestQueue) Execute(ctx context.Context, tests []*testCase
 		maybeAbortOnFailureFn: maybeAbortOnFailureFn,
 	}
 
-	execute(ctx, testSuiteRunner, tests, parallelism)
+	if q.durations != nil {
+		tests = q.durations.orderLongestFirst(tests, q.random)
+	}
+	execute(ctx, testSuiteRunner, tests, parallelism, q.workerLimit)
 }
 
 // execute is a convenience for unit testing
-func execute(ctx context.Context, testSuiteRunner testSuiteRunner, tests []*testCase, parallelism int) {
+func execute(ctx context.Context, testSuiteRunner testSuiteRunner, tests []*testCase, parallelism int, workerLimit *workerLimit) {
 	if ctx.Err() != nil {
 		return
 	}
 
 	serial, parallel := splitTests(tests, isSerialTest)
 
-	remainingParallelTests := make(chan *testCase, 100)
-	go queueAllTests(remainingParallelTests, parallel)
+	remainingParallelTests := newExclusiveTestQueue(parallel)
+	stopWaking := context.AfterFunc(ctx, remainingParallelTests.wake)
+	defer stopWaking()
+	stopWakingWorkers := context.AfterFunc(ctx, workerLimit.wake)
+	defer stopWakingWorkers()
 
 	var wg sync.WaitGroup
 	for i := 0; i < parallelism; i++ {
 		wg.Add(1)
 		go func(ctx context.Context) {
 			defer wg.Done()
-			runTestsUntilChannelEmpty(ctx, remainingParallelTests, testSuiteRunner)
+			runTestsUntilQueueEmpty(ctx, remainingParallelTests, workerLimit, testSuiteRunner)
 		}(ctx)
 	}
 	wg.Wait()
@@ -2,6 +2,7 @@ package ginkgo
 
 import (
 	"context"
+	"fmt"
 	"math/rand"
 	"strings"
 	"sync"
@@ -57,10 +58,84 @@ func Test_execute(t *testing.T) {
 	tests := makeTestCases()
 	testSuiteRunner := &testingSuiteRunner{}
 	parallelism := 30
-	execute(context.TODO(), testSuiteRunner, tests, parallelism)
+	execute(context.TODO(), testSuiteRunner, tests, parallelism, nil)
 
 	testsCompleted := testSuiteRunner.getTestsRun()
 	if len(tests) != len(testsCompleted) {
 		t.Errorf("expected %v, got %v", len(tests), len(testsCompleted))
 	}
 }
+
+// exclusionCheckingSuiteRunner records the most tests of each testExclusion group that ran at the same time.
+type exclusionCheckingSuiteRunner struct {
+	lock       sync.Mutex
+	running    map[string]int
+	maxRunning map[string]int
+}
+
+func (r *exclusionCheckingSuiteRunner) RunOneTest(ctx context.Context, test *testCase) {
+	r.lock.Lock()
+	r.running[test.testExclusion]++
+	if r.running[test.testExclusion] > r.maxRunning[test.testExclusion] {
+		r.maxRunning[test.testExclusion] = r.running[test.testExclusion]
+	}
+	r.lock.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	r.lock.Lock()
+	r.running[test.testExclusion]--
+	r.lock.Unlock()
+}
+
+func Test_executeExclusionGroups(t *testing.T) {
+	tests := []*testCase{}
+	for i := 0; i < 10; i++ {
+		for _, name := range []string{"[Exclusive:proxy] config", "[Exclusive:apiserver] config", "other"} {
+			name = fmt.Sprintf("%s %d", name, i)
+			tests = append(tests, &testCase{name: name, testExclusion: exclusionGroup(name)})
+		}
+	}
+	testSuiteRunner := &exclusionCheckingSuiteRunner{running: map[string]int{}, maxRunning: map[string]int{}}
+	execute(context.TODO(), testSuiteRunner, tests, 10, nil)
+
+	if got := testSuiteRunner.maxRunning["proxy"]; got != 1 {
+		t.Errorf("expected proxy tests to run one at a time, got %d at once", got)
+	}
+	if got := testSuiteRunner.maxRunning["apiserver"]; got != 1 {
+		t.Errorf("expected apiserver tests to run one at a time, got %d at once", got)
+	}
+	if got := testSuiteRunner.maxRunning[""]; got < 2 {
+		t.Errorf("expected tests without a group to run in parallel, got %d at once", got)
+	}
+}
+
+func Test_executeCancelledWhileWaiting(t *testing.T) {
+	tests := []*testCase{
+		{name: "[Exclusive:a] first", testExclusion: "a"},
+		{name: "[Exclusive:a] second", testExclusion: "a"},
+	}
+	ctx, cancel := context.WithCancel(context.TODO())
+	testSuiteRunner := &cancellingSuiteRunner{cancel: cancel}
+	execute(ctx, testSuiteRunner, tests, 2, nil)
+
+	if testSuiteRunner.count != 1 {
+		t.Errorf("expected the second test to be abandoned once cancelled, %d ran", testSuiteRunner.count)
+	}
+}
+
+// cancellingSuiteRunner cancels the run from the first test.
+type cancellingSuiteRunner struct {
+	lock   sync.Mutex
+	count  int
+	cancel context.CancelFunc
+}
+
+func (r *cancellingSuiteRunner) RunOneTest(ctx context.Context, test *testCase) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.count++
+	// give the other worker time to wait on the group
+	time.Sleep(10 * time.Millisecond)
+	r.cancel()
+}
@@ -0,0 +1,144 @@
+package ginkgo
+
+import (
+	"strings"
+)
+
+// RetryPolicy controls how the failures of a suite are retried to tell flakes from real failures.
+type RetryPolicy struct {
+	// MaxAttempts is the most times a failing test runs, counting the first run.  Less than 2 disables retries.
+	MaxAttempts int
+	// Budget is the most retries run for the whole suite.  When more tests fail than the budget allows, nothing is
+	// retried: the run fails regardless, and retrying would only delay reporting it.  A suite with a budget may pass
+	// with flakes, one without fails on any failure.
+	Budget int
+	// Serial runs retries one at a time, so that the load from other tests cannot cause the retry to fail again.
+	Serial bool
+	// Overrides change MaxAttempts for the tests whose name contains a label, the first matching label wins.
+	Overrides []RetryOverride
+	// FailThenSkip is what a failure means when the test is skipped on a retry.
+	FailThenSkip FailThenSkipPolicy
+}
+
+// RetryOverride sets the attempts of the tests labeled Label, for instance one attempt for [Disruptive] tests.
+type RetryOverride struct {
+	Label       string
+	MaxAttempts int
+}
+
+type FailThenSkipPolicy string
+
+var (
+	// FailThenSkipIgnoreFailure drops the failed attempts of a test that is skipped when retried.  The skip very
+	// likely means a precondition failed the first time, so the test is not counted as failing.
+	FailThenSkipIgnoreFailure FailThenSkipPolicy = ""
+	// FailThenSkipFail keeps the failed attempts and counts the test as failing.
+	FailThenSkipFail FailThenSkipPolicy = "Fail"
+)
+
+// DefaultRetryPolicy is the policy of a suite without a RetryPolicy: every failure is retried once, in parallel, as
+// long as there are no more than maximumAllowedFlakes of them.
+func DefaultRetryPolicy(maximumAllowedFlakes int) RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 2,
+		Budget:      maximumAllowedFlakes,
+	}
+}
+
+// retryPolicy returns the RetryPolicy of the suite, or the default one.
+func (s *TestSuite) retryPolicy() RetryPolicy {
+	if s.RetryPolicy != nil {
+		return *s.RetryPolicy
+	}
+	return DefaultRetryPolicy(s.MaximumAllowedFlakes)
+}
+
+func (p RetryPolicy) maxAttempts(test *testCase) int {
+	for _, override := range p.Overrides {
+		if strings.Contains(test.name, override.Label) {
+			return override.MaxAttempts
+		}
+	}
+	return p.MaxAttempts
+}
+
+func (p RetryPolicy) parallelism(parallelism int) int {
+	if p.Serial {
+		return 1
+	}
+	return parallelism
+}
+
+// retryOutcome is the result of retrying the failures of a suite.
+type retryOutcome struct {
+	// retries are the attempts to report along with the first runs, each as its own junit entry.
+	retries []*testCase
+	// failing are the tests that failed every attempt they were given.
+	failing []*testCase
+	// flaky are the names of the tests that passed on a retry.
+	flaky []string
+	// skipped are the names of the tests that were skipped on a retry.
+	skipped []string
+}
+
+// retry runs the failing tests again, as the policy allows, until they pass, are skipped, or run out of attempts.
+// run executes a group of tests at a parallelism and records their results on them.
+func (p RetryPolicy) retry(failing []*testCase, parallelism int, run func(tests []*testCase, parallelism int)) retryOutcome {
+	outcome := retryOutcome{}
+	if len(failing) > p.Budget {
+		outcome.failing = failing
+		return outcome
+	}
+
+	budget := p.Budget
+	pending := failing
+	for len(pending) > 0 {
+		attempts := []*testCase{}
+		for _, test := range pending {
+			if budget == 0 || test.attempt() >= p.maxAttempts(test) {
+				outcome.failing = append(outcome.failing, test)
+				continue
+			}
+			budget--
+			attempts = append(attempts, test.Retry())
+		}
+		if len(attempts) == 0 {
+			break
+		}
+		run(attempts, p.parallelism(parallelism))
+
+		pending = nil
+		for _, attempt := range attempts {
+			switch {
+			case attempt.success:
+				outcome.flaky = append(outcome.flaky, attempt.name)
+				outcome.retries = append(outcome.retries, attempt)
+			case attempt.skipped:
+				outcome.skipped = append(outcome.skipped, attempt.name)
+				outcome.retries = append(outcome.retries, attempt)
+				if p.FailThenSkip == FailThenSkipFail {
+					outcome.failing = append(outcome.failing, attempt.previous)
+				}
+			case attempt.flake:
+				// a retry that flakes is not reported, so the failure before it stays authoritative.
+				pending = append(pending, attempt)
+			case attempt.failed:
+				outcome.retries = append(outcome.retries, attempt)
+				pending = append(pending, attempt)
+			default:
+				// the run was interrupted before the retry completed.
+				outcome.failing = append(outcome.failing, attempt.previous)
+			}
+		}
+	}
+	return outcome
+}
+
+// attempt is the 1-based index of this run of the test among its retries.
+func (t *testCase) attempt() int {
+	attempt := 1
+	for previous := t.previous; previous != nil; previous = previous.previous {
+		attempt++
+	}
+	return attempt
+}
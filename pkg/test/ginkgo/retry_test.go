@@ -0,0 +1,144 @@
+package ginkgo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// scriptedRun returns a run function that gives every attempt of a test the next state in its script, and failed
+// once the script runs out.  It records the parallelism of each run.
+func scriptedRun(scripts map[string][]TestState, parallelisms *[]int) func(tests []*testCase, parallelism int) {
+	return func(tests []*testCase, parallelism int) {
+		*parallelisms = append(*parallelisms, parallelism)
+		for _, test := range tests {
+			state := TestFailed
+			if script := scripts[test.name]; len(script) > 0 {
+				state, scripts[test.name] = script[0], script[1:]
+			}
+			mutateTestCaseWithResults(test, &testRunResultHandle{testRunResult: &testRunResult{name: test.name, testState: state}})
+		}
+	}
+}
+
+func failedTests(names ...string) []*testCase {
+	ret := []*testCase{}
+	for _, name := range names {
+		ret = append(ret, &testCase{name: name, failed: true})
+	}
+	return ret
+}
+
+func TestRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  RetryPolicy
+		failing []*testCase
+		scripts map[string][]TestState
+
+		wantRetries      []string
+		wantAttempts     []int
+		wantFailing      []string
+		wantFlaky        []string
+		wantSkipped      []string
+		wantParallelisms []int
+	}{
+		{
+			name:             "default retries each failure once",
+			policy:           DefaultRetryPolicy(3),
+			failing:          failedTests("flaky", "broken"),
+			scripts:          map[string][]TestState{"flaky": {TestSucceeded}},
+			wantRetries:      []string{"flaky", "broken"},
+			wantAttempts:     []int{2, 2},
+			wantFailing:      []string{"broken"},
+			wantFlaky:        []string{"flaky"},
+			wantParallelisms: []int{10},
+		},
+		{
+			name:        "too many failures for the budget are not retried",
+			policy:      DefaultRetryPolicy(1),
+			failing:     failedTests("a", "b"),
+			wantFailing: []string{"a", "b"},
+		},
+		{
+			name:             "no budget",
+			policy:           DefaultRetryPolicy(0),
+			failing:          failedTests("a"),
+			wantFailing:      []string{"a"},
+			wantParallelisms: nil,
+		},
+		{
+			name: "attempts, overrides and serial retries",
+			policy: RetryPolicy{
+				MaxAttempts: 3,
+				Budget:      10,
+				Serial:      true,
+				Overrides:   []RetryOverride{{Label: "[Disruptive]", MaxAttempts: 1}},
+			},
+			failing:          failedTests("third time lucky", "[Disruptive] once"),
+			scripts:          map[string][]TestState{"third time lucky": {TestFailed, TestSucceeded}},
+			wantRetries:      []string{"third time lucky", "third time lucky"},
+			wantAttempts:     []int{2, 3},
+			wantFailing:      []string{"[Disruptive] once"},
+			wantFlaky:        []string{"third time lucky"},
+			wantParallelisms: []int{1, 1},
+		},
+		{
+			name:             "the budget is shared by every attempt",
+			policy:           RetryPolicy{MaxAttempts: 5, Budget: 3},
+			failing:          failedTests("a", "b"),
+			wantRetries:      []string{"a", "b", "a"},
+			wantAttempts:     []int{2, 2, 3},
+			wantFailing:      []string{"b", "a"},
+			wantParallelisms: []int{10, 10},
+		},
+		{
+			name:             "a skip after a failure ignores the failure by default",
+			policy:           DefaultRetryPolicy(3),
+			failing:          failedTests("precondition"),
+			scripts:          map[string][]TestState{"precondition": {TestSkipped}},
+			wantRetries:      []string{"precondition"},
+			wantAttempts:     []int{2},
+			wantSkipped:      []string{"precondition"},
+			wantParallelisms: []int{10},
+		},
+		{
+			name:             "a skip after a failure can fail the test",
+			policy:           RetryPolicy{MaxAttempts: 2, Budget: 3, FailThenSkip: FailThenSkipFail},
+			failing:          failedTests("precondition"),
+			scripts:          map[string][]TestState{"precondition": {TestSkipped}},
+			wantRetries:      []string{"precondition"},
+			wantAttempts:     []int{2},
+			wantFailing:      []string{"precondition"},
+			wantSkipped:      []string{"precondition"},
+			wantParallelisms: []int{10},
+		},
+		{
+			name:             "a retry that flakes is not reported",
+			policy:           DefaultRetryPolicy(3),
+			failing:          failedTests("flaked"),
+			scripts:          map[string][]TestState{"flaked": {TestFlaked}},
+			wantFailing:      []string{"flaked"},
+			wantParallelisms: []int{10},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var parallelisms []int
+			outcome := tt.policy.retry(tt.failing, 10, scriptedRun(tt.scripts, &parallelisms))
+
+			attempts := []int{}
+			for _, retry := range outcome.retries {
+				attempts = append(attempts, retry.attempt())
+			}
+			assert.Equal(t, tt.wantRetries, testNames(outcome.retries))
+			if tt.wantAttempts != nil {
+				assert.Equal(t, tt.wantAttempts, attempts)
+			}
+			assert.Equal(t, tt.wantFailing, testNames(outcome.failing))
+			assert.Equal(t, tt.wantFlaky, outcome.flaky)
+			assert.Equal(t, tt.wantSkipped, outcome.skipped)
+			assert.Equal(t, tt.wantParallelisms, parallelisms)
+		})
+	}
+}
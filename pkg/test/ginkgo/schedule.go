@@ -0,0 +1,246 @@
+package ginkgo
+
+import (
+	"bytes"
+	"container/heap"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// TestDuration is how long a test historically takes.
+type TestDuration struct {
+	P50 time.Duration
+	P95 time.Duration
+}
+
+// TestDurations are historical test durations keyed by test name.  They let the queue start the longest tests first,
+// so a long test that happens to be shuffled to the end of a bucket cannot hold up the whole bucket.
+type TestDurations map[string]TestDuration
+
+// LoadTestDurations reads a JSON list of {"TestName": ..., "P50": ..., "P95": ...} with the percentiles in seconds,
+// either as numbers or as the strings BigQuery exports.
+func LoadTestDurations(filename string) (TestDurations, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	type decodingTestDuration struct {
+		TestName string
+		P50      seconds
+		P95      seconds
+	}
+	decoded := []decodingTestDuration{}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("failed to read test durations from %v: %w", filename, err)
+	}
+
+	ret := TestDurations{}
+	for _, curr := range decoded {
+		if len(curr.TestName) == 0 {
+			return nil, fmt.Errorf("failed to read test durations from %v: missing TestName", filename)
+		}
+		ret[curr.TestName] = TestDuration{
+			P50: time.Duration(curr.P50),
+			P95: time.Duration(curr.P95),
+		}
+	}
+	return ret, nil
+}
+
+// seconds decodes a number of seconds, quoted or not, into a duration.
+type seconds time.Duration
+
+func (s *seconds) UnmarshalJSON(data []byte) error {
+	value := strings.Trim(string(data), `"`)
+	if len(value) == 0 || value == "null" {
+		*s = 0
+		return nil
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("invalid number of seconds %s: %w", data, err)
+	}
+	*s = seconds(parsed * float64(time.Second))
+	return nil
+}
+
+// estimate is the duration planned for a test.  The P95 is used so that tests with a long tail start early, the P50
+// when that is all there is.
+func (d TestDurations) estimate(test *testCase) (time.Duration, bool) {
+	duration, ok := d[test.name]
+	switch {
+	case !ok:
+		return 0, false
+	case duration.P95 > 0:
+		return duration.P95, true
+	case duration.P50 > 0:
+		return duration.P50, true
+	}
+	return 0, false
+}
+
+// estimator returns the estimate for each of tests, tests without history are expected to take the median of the
+// tests with history.
+func (d TestDurations) estimator(tests []*testCase) func(test *testCase) time.Duration {
+	known := []time.Duration{}
+	for _, test := range tests {
+		if estimate, ok := d.estimate(test); ok {
+			known = append(known, estimate)
+		}
+	}
+	var median time.Duration
+	if len(known) > 0 {
+		sort.Slice(known, func(i, j int) bool { return known[i] < known[j] })
+		median = known[len(known)/2]
+	}
+
+	return func(test *testCase) time.Duration {
+		if estimate, ok := d.estimate(test); ok {
+			return estimate
+		}
+		return median
+	}
+}
+
+// orderLongestFirst returns a copy of tests with the longest expected tests first.  Tests with the same estimate are
+// shuffled by random so their order stays independent from run to run, yet is reproduced by the same seed.  random may
+// be nil to keep them in the order given.  Because every worker takes the next test from a shared queue, this is the
+// longest-processing-time-first packing of the tests onto the workers.
+func (d TestDurations) orderLongestFirst(tests []*testCase, random *rand.Rand) []*testCase {
+	estimate := d.estimator(tests)
+	ordered := make([]*testCase, len(tests))
+	copy(ordered, tests)
+	if random != nil {
+		random.Shuffle(len(ordered), func(i, j int) { ordered[i], ordered[j] = ordered[j], ordered[i] })
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return estimate(ordered[i]) > estimate(ordered[j])
+	})
+	return ordered
+}
+
+// predictWallTime simulates execute: the parallel tests are handed, in order, to whichever of the parallelism workers
+// is free first, then the serial tests run one at a time.  It also returns how many tests have no history.
+func (d TestDurations) predictWallTime(tests []*testCase, parallelism int) (time.Duration, int) {
+	estimate := d.estimator(tests)
+	noHistory := 0
+	for _, test := range tests {
+		if _, ok := d.estimate(test); !ok {
+			noHistory++
+		}
+	}
+
+	serial, parallel := splitTests(d.orderLongestFirst(tests, nil), isSerialTest)
+	workers := make(workerFinishTimes, max(1, parallelism))
+	for _, test := range parallel {
+		workers[0] += estimate(test)
+		heap.Fix(&workers, 0)
+	}
+	var wallTime time.Duration
+	for _, finish := range workers {
+		if finish > wallTime {
+			wallTime = finish
+		}
+	}
+	for _, test := range serial {
+		wallTime += estimate(test)
+	}
+	return wallTime, noHistory
+}
+
+// workerFinishTimes is a min-heap of when each worker finishes its tests.
+type workerFinishTimes []time.Duration
+
+func (h workerFinishTimes) Len() int            { return len(h) }
+func (h workerFinishTimes) Less(i, j int) bool  { return h[i] < h[j] }
+func (h workerFinishTimes) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *workerFinishTimes) Push(x interface{}) { *h = append(*h, x.(time.Duration)) }
+func (h *workerFinishTimes) Pop() interface{} {
+	old := *h
+	ret := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return ret
+}
+
+// suiteBuckets are the groups of tests a suite runs one after the other.
+type suiteBuckets struct {
+	early      []*testCase
+	kube       []*testCase
+	storage    []*testCase
+	openshift  []*testCase
+	mustGather []*testCase
+	late       []*testCase
+}
+
+func bucketTests(tests []*testCase) suiteBuckets {
+	early, notEarly := splitTests(tests, func(t *testCase) bool {
+		return strings.Contains(t.name, "[Early]")
+	})
+
+	late, primaryTests := splitTests(notEarly, func(t *testCase) bool {
+		return strings.Contains(t.name, "[Late]")
+	})
+
+	kubeTests, openshiftTests := splitTests(primaryTests, func(t *testCase) bool {
+		return strings.Contains(t.name, "[Suite:k8s]")
+	})
+
+	storageTests, kubeTests := splitTests(kubeTests, func(t *testCase) bool {
+		return strings.Contains(t.name, "[sig-storage]")
+	})
+
+	mustGatherTests, openshiftTests := splitTests(openshiftTests, func(t *testCase) bool {
+		return strings.Contains(t.name, "[sig-cli] oc adm must-gather")
+	})
+
+	return suiteBuckets{
+		early:      early,
+		kube:       kubeTests,
+		storage:    storageTests,
+		openshift:  openshiftTests,
+		mustGather: mustGatherTests,
+		late:       late,
+	}
+}
+
+// storageParallelism is the parallelism storage tests run at, half the usual to avoid cloud provider quota problems.
+func storageParallelism(parallelism int) int {
+	return max(1, parallelism/2)
+}
+
+// printSchedule writes the predicted wall time of each bucket for one pass through the suite.
+func (b suiteBuckets) printSchedule(out io.Writer, durations TestDurations, parallelism int) error {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "BUCKET\tTESTS\tSERIAL\tPARALLELISM\tNO HISTORY\tPREDICTED")
+
+	var total time.Duration
+	for _, bucket := range []struct {
+		name        string
+		tests       []*testCase
+		parallelism int
+	}{
+		{name: "early", tests: b.early, parallelism: parallelism},
+		{name: "kube", tests: b.kube, parallelism: parallelism},
+		{name: "storage", tests: b.storage, parallelism: storageParallelism(parallelism)},
+		{name: "openshift", tests: b.openshift, parallelism: parallelism},
+		{name: "must-gather", tests: b.mustGather, parallelism: parallelism},
+		{name: "late", tests: b.late, parallelism: parallelism},
+	} {
+		serial, _ := splitTests(bucket.tests, isSerialTest)
+		predicted, noHistory := durations.predictWallTime(bucket.tests, bucket.parallelism)
+		total += predicted
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%s\n", bucket.name, len(bucket.tests), len(serial), bucket.parallelism, noHistory, predicted.Round(time.Second))
+	}
+	fmt.Fprintf(w, "total\t\t\t\t\t%s\n", total.Round(time.Second))
+	return w.Flush()
+}
@@ -0,0 +1,100 @@
+package ginkgo
+
+import (
+	"bytes"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadTestDurations(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "durations.json")
+	content := `[
+  {"TestName": "quoted", "P50": "12.5", "P95": "30"},
+  {"TestName": "numbers", "P50": 1, "P95": 2.5},
+  {"TestName": "only-p50", "P50": 4}
+]`
+	require.NoError(t, os.WriteFile(filename, []byte(content), 0644))
+
+	durations, err := LoadTestDurations(filename)
+	require.NoError(t, err)
+	assert.Equal(t, TestDurations{
+		"quoted":   {P50: 12500 * time.Millisecond, P95: 30 * time.Second},
+		"numbers":  {P50: 1 * time.Second, P95: 2500 * time.Millisecond},
+		"only-p50": {P50: 4 * time.Second},
+	}, durations)
+
+	require.NoError(t, os.WriteFile(filename, []byte(`[{"TestName": "bad", "P50": "soon"}]`), 0644))
+	_, err = LoadTestDurations(filename)
+	assert.Error(t, err)
+}
+
+func TestOrderLongestFirst(t *testing.T) {
+	durations := TestDurations{
+		"short":  {P50: 1 * time.Second, P95: 2 * time.Second},
+		"long":   {P50: 10 * time.Minute, P95: 14 * time.Minute},
+		"medium": {P50: 3 * time.Minute},
+		"tied-a": {P95: 5 * time.Second},
+		"tied-b": {P95: 5 * time.Second},
+	}
+	tests := []*testCase{{name: "short"}, {name: "tied-a"}, {name: "unknown"}, {name: "medium"}, {name: "tied-b"}, {name: "long"}}
+
+	ordered := durations.orderLongestFirst(tests, rand.New(rand.NewSource(1)))
+	names := []string{}
+	for _, test := range ordered {
+		names = append(names, test.name)
+	}
+	// unknown tests are expected to take the median of the known tests, 5s here.
+	assert.Equal(t, []string{"long", "medium"}, names[:2])
+	assert.ElementsMatch(t, []string{"tied-a", "tied-b", "unknown"}, names[2:5])
+	assert.Equal(t, "short", names[5])
+	assert.Equal(t, "short", tests[0].name, "the given tests must not be reordered")
+
+	// the ties are broken by the seed of the suite, so a run can be reproduced
+	assert.Equal(t, ordered, durations.orderLongestFirst(tests, rand.New(rand.NewSource(1))))
+}
+
+func TestPredictWallTime(t *testing.T) {
+	durations := TestDurations{
+		"a":          {P95: 6 * time.Minute},
+		"b":          {P95: 4 * time.Minute},
+		"c":          {P95: 3 * time.Minute},
+		"d":          {P95: 3 * time.Minute},
+		"e [Serial]": {P95: 1 * time.Minute},
+	}
+	tests := []*testCase{{name: "d"}, {name: "c"}, {name: "e [Serial]"}, {name: "b"}, {name: "a"}, {name: "unknown"}}
+
+	// unknown takes the median, 3m.  Two workers: a(6) | b(4)+c(3) -> a+d(9) | b+c+unknown(10), then 1m of serial.
+	predicted, noHistory := durations.predictWallTime(tests, 2)
+	assert.Equal(t, 11*time.Minute, predicted)
+	assert.Equal(t, 1, noHistory)
+}
+
+func TestPrintSchedule(t *testing.T) {
+	durations := TestDurations{
+		"[Early] early":                    {P95: time.Minute},
+		"[sig-storage] volume [Suite:k8s]": {P95: 10 * time.Minute},
+		"[sig-storage] other [Suite:k8s]":  {P95: 10 * time.Minute},
+		"[sig-apps] deployment":            {P95: 2 * time.Minute},
+	}
+	tests := []*testCase{}
+	for name := range durations {
+		tests = append(tests, &testCase{name: name})
+	}
+
+	out := &bytes.Buffer{}
+	require.NoError(t, bucketTests(tests).printSchedule(out, durations, 2))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 8)
+	assert.Equal(t, []string{"early", "1", "0", "2", "0", "1m0s"}, strings.Fields(lines[1]))
+	// storage runs at half the parallelism, so the two storage tests run one after the other.
+	assert.Equal(t, []string{"storage", "2", "0", "1", "0", "20m0s"}, strings.Fields(lines[3]))
+	assert.Equal(t, []string{"openshift", "1", "0", "2", "0", "2m0s"}, strings.Fields(lines[4]))
+	assert.Equal(t, []string{"total", "23m0s"}, strings.Fields(lines[7]))
+}
@@ -0,0 +1,120 @@
+package ginkgo
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitortestframework"
+)
+
+// designatedShard runs every test that cannot be split across shards.
+const designatedShard = 0
+
+// isPinnedToDesignatedShard returns true for tests that must run on designatedShard: [Serial] tests assume nothing
+// else runs at the same time, and [Early] and [Late] tests bracket the run.
+func isPinnedToDesignatedShard(test *testCase) bool {
+	return isSerialTest(test) || strings.Contains(test.name, "[Early]") || strings.Contains(test.name, "[Late]")
+}
+
+// shardMonitorTests are the only monitor tests that the shards other than designatedShard run.  Every shard watches the
+// same cluster, so the other monitor tests run on designatedShard alone and merge-results sees their results once.  The
+// intervals are still serialized so that the e2e test intervals of every shard are merged.
+var shardMonitorTests = []string{"interval-serializer"}
+
+// monitorTestsForShard narrows the monitor tests of shard index of count to shardMonitorTests, unless it is the
+// designatedShard.
+func monitorTestsForShard(info monitortestframework.MonitorTestInitializationInfo, index, count int) monitortestframework.MonitorTestInitializationInfo {
+	if count <= 1 || index == designatedShard {
+		return info
+	}
+	info.ExactMonitorTests = shardMonitorTests
+	info.DisableMonitorTests = nil
+	return info
+}
+
+// validateShard checks that index is a valid shard of count shards.
+func validateShard(index, count int) error {
+	if count < 1 {
+		return fmt.Errorf("--shard-count must be at least 1, got %d", count)
+	}
+	if index < 0 || index >= count {
+		return fmt.Errorf("--shard-index must be between 0 and %d, got %d", count-1, index)
+	}
+	return nil
+}
+
+// shardTests returns the tests that shard index of count runs, in the order they were given.  Every invocation given
+// the same tests, in any order, and the same durations agrees on the split.  Without durations, tests are assigned by
+// a stable hash of their name.  With durations, they are packed so that every shard is expected to take about as
+// long, counting the pinned tests against designatedShard.
+func shardTests(tests []*testCase, index, count int, durations TestDurations) []*testCase {
+	if count <= 1 {
+		return tests
+	}
+
+	pinned, splittable := splitTests(tests, isPinnedToDesignatedShard)
+	var assignments map[*testCase]int
+	if durations == nil {
+		assignments = shardByHash(splittable, count)
+	} else {
+		assignments = shardByDuration(pinned, splittable, count, durations)
+	}
+
+	ret := []*testCase{}
+	for _, test := range tests {
+		shard, ok := assignments[test]
+		if !ok {
+			shard = designatedShard
+		}
+		if shard == index {
+			ret = append(ret, test)
+		}
+	}
+	return ret
+}
+
+func shardByHash(tests []*testCase, count int) map[*testCase]int {
+	ret := map[*testCase]int{}
+	for _, test := range tests {
+		hash := fnv.New32a()
+		hash.Write([]byte(test.name))
+		ret[test] = int(hash.Sum32() % uint32(count))
+	}
+	return ret
+}
+
+// shardByDuration hands the longest test to the shard with the least work so far, ties are broken by test name and
+// then by the lowest shard, so the result does not depend on the order of tests.
+func shardByDuration(pinned, tests []*testCase, count int, durations TestDurations) map[*testCase]int {
+	estimate := durations.estimator(append(append([]*testCase{}, pinned...), tests...))
+
+	load := make([]time.Duration, count)
+	for _, test := range pinned {
+		load[designatedShard] += estimate(test)
+	}
+
+	ordered := make([]*testCase, len(tests))
+	copy(ordered, tests)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if estimate(ordered[i]) != estimate(ordered[j]) {
+			return estimate(ordered[i]) > estimate(ordered[j])
+		}
+		return ordered[i].name < ordered[j].name
+	})
+
+	ret := map[*testCase]int{}
+	for _, test := range ordered {
+		lightest := 0
+		for shard := range load {
+			if load[shard] < load[lightest] {
+				lightest = shard
+			}
+		}
+		ret[test] = lightest
+		load[lightest] += estimate(test)
+	}
+	return ret
+}
@@ -0,0 +1,108 @@
+package ginkgo
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/origin/pkg/monitortestframework"
+)
+
+func TestShardTests(t *testing.T) {
+	tests := []*testCase{
+		{name: "[Early] early"},
+		{name: "[Serial] serial"},
+		{name: "[Late] late"},
+	}
+	for i := 0; i < 50; i++ {
+		tests = append(tests, &testCase{name: fmt.Sprintf("test-%d", i)})
+	}
+	durations := TestDurations{}
+	for i, test := range tests {
+		durations[test.name] = TestDuration{P95: time.Duration(i+1) * time.Second}
+	}
+
+	for _, tc := range []struct {
+		name      string
+		durations TestDurations
+	}{
+		{name: "by hash"},
+		{name: "by duration", durations: durations},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			const count = 3
+			shuffled := make([]*testCase, len(tests))
+			copy(shuffled, tests)
+			rand.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+			all := []string{}
+			for index := 0; index < count; index++ {
+				shard := shardTests(tests, index, count, tc.durations)
+				names := []string{}
+				for _, test := range shard {
+					names = append(names, test.name)
+				}
+				if index == designatedShard {
+					assert.Subset(t, names, []string{"[Early] early", "[Serial] serial", "[Late] late"})
+				} else {
+					assert.NotContains(t, names, "[Serial] serial")
+				}
+
+				reshuffled := []string{}
+				for _, test := range shardTests(shuffled, index, count, tc.durations) {
+					reshuffled = append(reshuffled, test.name)
+				}
+				assert.ElementsMatch(t, names, reshuffled, "the order of the tests must not change the shards")
+				all = append(all, names...)
+			}
+			require.Len(t, all, len(tests), "every test must run on exactly one shard")
+		})
+	}
+}
+
+func TestShardByDurationBalances(t *testing.T) {
+	durations := TestDurations{
+		"[Serial] serial": {P95: 10 * time.Minute},
+		"a":               {P95: 8 * time.Minute},
+		"b":               {P95: 6 * time.Minute},
+		"c":               {P95: 5 * time.Minute},
+		"d":               {P95: 4 * time.Minute},
+		"e":               {P95: 3 * time.Minute},
+	}
+	tests := []*testCase{{name: "e"}, {name: "d"}, {name: "c"}, {name: "b"}, {name: "a"}, {name: "[Serial] serial"}}
+
+	// shard 0 starts with 10m of serial work: a(8) -> 1, b(6) -> 2, c(5) -> 2, d(4) -> 1, e(3) -> 0 for 13 | 12 | 11.
+	assignments := map[string]int{}
+	for index := 0; index < 3; index++ {
+		for _, test := range shardTests(tests, index, 3, durations) {
+			assignments[test.name] = index
+		}
+	}
+	assert.Equal(t, map[string]int{"[Serial] serial": 0, "a": 1, "b": 2, "c": 2, "d": 1, "e": 0}, assignments)
+}
+
+func TestValidateShard(t *testing.T) {
+	assert.NoError(t, validateShard(0, 1))
+	assert.NoError(t, validateShard(2, 3))
+	assert.Error(t, validateShard(3, 3))
+	assert.Error(t, validateShard(-1, 3))
+	assert.Error(t, validateShard(0, 0))
+}
+
+func TestMonitorTestsForShard(t *testing.T) {
+	info := monitortestframework.MonitorTestInitializationInfo{
+		ClusterStabilityDuringTest: monitortestframework.Stable,
+		DisableMonitorTests:        []string{"pod-lifecycle"},
+	}
+	assert.Equal(t, info, monitorTestsForShard(info, 0, 0), "an unsharded run keeps its monitor tests")
+	assert.Equal(t, info, monitorTestsForShard(info, designatedShard, 3))
+
+	other := monitorTestsForShard(info, 1, 3)
+	assert.Equal(t, monitortestframework.Stable, other.ClusterStabilityDuringTest)
+	assert.Equal(t, []string{"interval-serializer"}, other.ExactMonitorTests)
+	assert.Empty(t, other.DisableMonitorTests)
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"sort"
 	"sync"
+	"time"
 )
 
 type testSuiteProgress struct {
@@ -12,14 +13,82 @@ type testSuiteProgress struct {
 	failures int
 	index    int
 	total    int
+	// running holds the start times of the tests that are running, a test may run more than once at a time.
+	running map[string][]time.Time
 }
 
 func newTestSuiteProgress(total int) *testSuiteProgress {
 	return &testSuiteProgress{
-		total: total,
+		total:   total,
+		running: map[string][]time.Time{},
 	}
 }
 
+// testSuiteStatus is a point in time view of a testSuiteProgress.
+type testSuiteStatus struct {
+	Failures int           `json:"failures"`
+	Started  int           `json:"started"`
+	Total    int           `json:"total"`
+	Running  []runningTest `json:"running"`
+}
+
+type runningTest struct {
+	Name    string    `json:"name"`
+	Started time.Time `json:"started"`
+}
+
+func (s *testSuiteProgress) Status() testSuiteStatus {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	ret := testSuiteStatus{
+		Failures: s.failures,
+		Started:  s.index,
+		Total:    s.total,
+		Running:  []runningTest{},
+	}
+	for name, starts := range s.running {
+		for _, start := range starts {
+			ret.Running = append(ret.Running, runningTest{Name: name, Started: start})
+		}
+	}
+	sort.Slice(ret.Running, func(i, j int) bool {
+		if !ret.Running[i].Started.Equal(ret.Running[j].Started) {
+			return ret.Running[i].Started.Before(ret.Running[j].Started)
+		}
+		return ret.Running[i].Name < ret.Running[j].Name
+	})
+	return ret
+}
+
+// currentTestSuiteProgress tracks the testSuiteProgress of the group of tests that is executing, each call to
+// Execute starts a new one.
+type currentTestSuiteProgress struct {
+	lock    sync.Mutex
+	current *testSuiteProgress
+}
+
+func (c *currentTestSuiteProgress) set(progress *testSuiteProgress) {
+	if c == nil {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.current = progress
+}
+
+// Status returns the status of the executing group of tests, nil before the first group starts.
+func (c *currentTestSuiteProgress) Status() *testSuiteStatus {
+	c.lock.Lock()
+	current := c.current
+	c.lock.Unlock()
+	if current == nil {
+		return nil
+	}
+	status := current.Status()
+	return &status
+}
+
 func (s *testSuiteProgress) LogTestStart(out io.Writer, testName string) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
@@ -31,6 +100,8 @@ func (s *testSuiteProgress) LogTestStart(out io.Writer, testName string) {
 		s.total++
 	}
 
+	s.running[testName] = append(s.running[testName], time.Now())
+
 	fmt.Fprintf(out, "started: %d/%d/%d %q\n\n", s.failures, s.index, s.total, testName)
 }
 
@@ -38,6 +109,11 @@ func (s *testSuiteProgress) TestEnded(testName string, testRunResult *testRunRes
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
+	if starts := s.running[testName]; len(starts) > 1 {
+		s.running[testName] = starts[1:]
+	} else {
+		delete(s.running, testName)
+	}
 	if isTestFailed(testRunResult.testState) {
 		s.failures++
 	}
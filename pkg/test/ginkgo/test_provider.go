@@ -0,0 +1,182 @@
+package ginkgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/clioptions/clusterdiscovery"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kubernetes/test/e2e/framework"
+	"k8s.io/utils/clock"
+)
+
+const testProviderTestName = "[sig-arch] openshift-tests should discover the cluster configuration for test processes"
+
+const (
+	// testProviderInitialBackoff is how long test processes run with the skeleton provider after discovery fails,
+	// before the next one tries again.  It doubles with every failure in a row, up to testProviderMaxBackoff.
+	testProviderInitialBackoff = 10 * time.Second
+	testProviderMaxBackoff     = 5 * time.Minute
+)
+
+// testProvider is the TEST_PROVIDER handed to every test process.  Discovering the cluster takes a number of API calls,
+// so it is done by the first test process and reused until refresh is called, for instance once an upgrade completes.
+// Only one discovery runs at a time, the test processes that start meanwhile wait for it.  After discovery fails, the
+// test processes run with the skeleton provider until a backoff has passed, then the next one tries again.
+type testProvider struct {
+	lock     sync.Mutex
+	discover func() (*clusterdiscovery.ClusterConfiguration, error)
+	clock    clock.PassiveClock
+
+	// provider is the JSON of the discovered ClusterConfiguration, empty until discovery succeeds.
+	provider string
+	// discovering is closed when the discovery in flight finishes, it is nil when none is.
+	discovering chan struct{}
+	// generation is bumped by refresh, so a discovery that was in flight does not replace the provider.
+	generation int
+	// failures is how many discoveries failed in a row, retryAfter is when the next discovery may run.
+	failures   int
+	retryAfter time.Time
+	// attempted is set once discovery has run, succeeded once it has succeeded at least once.
+	attempted bool
+	succeeded bool
+	// skeletonRuns is how many times a test process got the skeleton provider because discovery failed.
+	skeletonRuns int
+	errs         sets.String
+}
+
+func newTestProvider(discover func() (*clusterdiscovery.ClusterConfiguration, error)) *testProvider {
+	return &testProvider{
+		discover: discover,
+		clock:    clock.RealClock{},
+		errs:     sets.NewString(),
+	}
+}
+
+// get returns the TEST_PROVIDER, discovering the cluster if it has not been yet.
+func (p *testProvider) get() string {
+	p.lock.Lock()
+	for p.discovering != nil {
+		discovering := p.discovering
+		p.lock.Unlock()
+		<-discovering
+		p.lock.Lock()
+	}
+	if len(p.provider) > 0 {
+		defer p.lock.Unlock()
+		return p.provider
+	}
+	if p.clock.Now().Before(p.retryAfter) {
+		defer p.lock.Unlock()
+		return p.skeletonLocked()
+	}
+	p.attempted = true
+	discovering := make(chan struct{})
+	p.discovering = discovering
+	generation := p.generation
+	p.lock.Unlock()
+
+	provider, err := p.discoverProvider()
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.discovering = nil
+	close(discovering)
+	if err != nil {
+		p.failures++
+		backoff := testProviderInitialBackoff
+		for i := 1; i < p.failures && backoff < testProviderMaxBackoff; i++ {
+			backoff *= 2
+		}
+		p.retryAfter = p.clock.Now().Add(min(backoff, testProviderMaxBackoff))
+		p.errs.Insert(err.Error())
+		return p.skeletonLocked()
+	}
+	p.failures = 0
+	p.succeeded = true
+	if generation == p.generation {
+		p.provider = provider
+	}
+	return provider
+}
+
+func (p *testProvider) discoverProvider() (string, error) {
+	config, err := p.discover()
+	if err != nil {
+		return "", err
+	}
+	provider, err := marshalTestProvider(config)
+	if err != nil {
+		return "", err
+	}
+	return string(provider), nil
+}
+
+func (p *testProvider) skeletonLocked() string {
+	p.skeletonRuns++
+	skeleton, _ := marshalTestProvider(&clusterdiscovery.ClusterConfiguration{})
+	return string(skeleton)
+}
+
+// refresh discards the discovered provider so the next test process discovers the cluster again, without waiting for
+// the backoff of earlier failures.
+func (p *testProvider) refresh() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.provider = ""
+	p.generation++
+	p.failures = 0
+	p.retryAfter = time.Time{}
+}
+
+// junitResults reports whether discovery failed for any test process, or nothing when no test process ran.  Failures
+// followed by a successful discovery also report a pass so they read as a flake.
+func (p *testProvider) junitResults() []*junitapi.JUnitTestCase {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if !p.attempted {
+		return nil
+	}
+	ret := []*junitapi.JUnitTestCase{}
+	if p.errs.Len() > 0 {
+		output := fmt.Sprintf("%d test processes ran with the skeleton provider because cluster discovery failed:\n\n%s", p.skeletonRuns, strings.Join(p.errs.List(), "\n"))
+		ret = append(ret, &junitapi.JUnitTestCase{
+			Name:          testProviderTestName,
+			SystemOut:     output,
+			FailureOutput: &junitapi.FailureOutput{Output: output},
+		})
+	}
+	if p.succeeded {
+		ret = append(ret, &junitapi.JUnitTestCase{Name: testProviderTestName})
+	}
+	return ret
+}
+
+func marshalTestProvider(config *clusterdiscovery.ClusterConfiguration) ([]byte, error) {
+	if len(config.ProviderName) == 0 {
+		config.ProviderName = "skeleton"
+	}
+	return json.Marshal(config)
+}
+
+// discoverClusterConfiguration computes the ClusterConfiguration of the cluster the tests run against.
+func discoverClusterConfiguration() (*clusterdiscovery.ClusterConfiguration, error) {
+	clientConfig, err := framework.LoadConfig(true)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load client config: %w", err)
+	}
+	clusterState, err := clusterdiscovery.DiscoverClusterState(clientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to discover the cluster state: %w", err)
+	}
+	config, err := clusterdiscovery.LoadConfig(clusterState)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the cluster configuration: %w", err)
+	}
+	return config, nil
+}
@@ -0,0 +1,83 @@
+package ginkgo
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/clioptions/clusterdiscovery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestTestProvider(t *testing.T) {
+	discoveries := 0
+	var discoverErr error
+	provider := newTestProvider(func() (*clusterdiscovery.ClusterConfiguration, error) {
+		discoveries++
+		if discoverErr != nil {
+			return nil, discoverErr
+		}
+		return &clusterdiscovery.ClusterConfiguration{ProviderName: "aws", NumNodes: discoveries}, nil
+	})
+	fakeClock := testingclock.NewFakePassiveClock(time.Now())
+	provider.clock = fakeClock
+	assert.Nil(t, provider.junitResults(), "nothing to report before a test process runs")
+
+	discoverErr = fmt.Errorf("connection refused")
+	assert.Contains(t, provider.get(), `"type":"skeleton"`)
+	assert.Contains(t, provider.get(), `"type":"skeleton"`)
+	assert.Equal(t, 1, discoveries, "a failed discovery is not tried again until the backoff passes")
+
+	fakeClock.SetTime(fakeClock.Now().Add(testProviderInitialBackoff))
+	assert.Contains(t, provider.get(), `"type":"skeleton"`)
+	assert.Equal(t, 2, discoveries, "the next test process tries again once the backoff passed")
+	fakeClock.SetTime(fakeClock.Now().Add(testProviderInitialBackoff))
+	assert.Contains(t, provider.get(), `"type":"skeleton"`)
+	assert.Equal(t, 2, discoveries, "the backoff doubles with every failure")
+
+	discoverErr = nil
+	fakeClock.SetTime(fakeClock.Now().Add(testProviderInitialBackoff))
+	assert.Contains(t, provider.get(), `"type":"aws"`)
+	assert.Contains(t, provider.get(), `"NumNodes":3`)
+	assert.Equal(t, 3, discoveries, "the discovered provider is reused")
+
+	provider.refresh()
+	assert.Contains(t, provider.get(), `"NumNodes":4`)
+
+	results := provider.junitResults()
+	require.Len(t, results, 2, "failures followed by a discovery read as a flake")
+	require.NotNil(t, results[0].FailureOutput)
+	assert.Equal(t, "4 test processes ran with the skeleton provider because cluster discovery failed:\n\nconnection refused", results[0].FailureOutput.Output)
+	assert.Nil(t, results[1].FailureOutput)
+}
+
+func TestTestProviderSingleDiscovery(t *testing.T) {
+	discoveries := 0
+	release := make(chan struct{})
+	provider := newTestProvider(func() (*clusterdiscovery.ClusterConfiguration, error) {
+		discoveries++
+		<-release
+		return &clusterdiscovery.ClusterConfiguration{ProviderName: "aws"}, nil
+	})
+
+	wg := sync.WaitGroup{}
+	providers := make([]string, 5)
+	for i := range providers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			providers[i] = provider.get()
+		}(i)
+	}
+	// the test processes that start while discovery is in flight wait for it instead of discovering again.
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, 1, discoveries)
+	for _, curr := range providers {
+		assert.Contains(t, curr, `"type":"aws"`)
+	}
+}
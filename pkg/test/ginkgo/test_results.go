@@ -0,0 +1,147 @@
+package ginkgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// TestArtifactDirEnvVar names the directory a test process may write artifacts to, such as logs, YAML dumps, and
+	// screenshots.  The directory is removed after the test when the test leaves it empty.
+	TestArtifactDirEnvVar = "TEST_ARTIFACT_DIR"
+
+	// testArtifactsDirname is the directory in --junit-dir holding the artifact directory of every test.
+	testArtifactsDirname = "test-artifacts"
+
+	// testResultsFilename is the file in --junit-dir that a TestResult is appended to as each test finishes.
+	testResultsFilename = "e2e-test-results.jsonl"
+
+	// maxArtifactDirSlugLength keeps the artifact directories of tests with long names within filesystem limits.
+	maxArtifactDirSlugLength = 100
+)
+
+var unsafeArtifactDirChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// TestResult is one line of the results file, written when a test finishes.
+type TestResult struct {
+	Name string `json:"name"`
+	// Attempt is the 1-based index of this run of the test among its retries.
+	Attempt int       `json:"attempt"`
+	State   TestState `json:"state"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	// ExitCode is the exit code of the test process, -1 when the process did not run or did not exit on its own.
+	ExitCode int `json:"exitCode"`
+	// ArtifactDir is the directory the test wrote artifacts to, relative to --junit-dir.  It is empty when the test
+	// wrote none.
+	ArtifactDir string `json:"artifactDir,omitempty"`
+	// Binary is the external binary that ran the test, empty for tests built into openshift-tests.
+	Binary string `json:"binary,omitempty"`
+}
+
+// testResultsWriter appends a TestResult for every finished test.  It is threadsafe and a nil writer records nothing.
+type testResultsWriter struct {
+	lock sync.Mutex
+	file *os.File
+}
+
+// openTestResults starts a new results file in dir, or when resuming, appends to the existing one.
+func openTestResults(dir string, resume bool) (*testResultsWriter, error) {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if resume {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+	file, err := os.OpenFile(filepath.Join(dir, testResultsFilename), flags, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("could not create test results: %w", err)
+	}
+	return &testResultsWriter{file: file}, nil
+}
+
+// Record appends the result of a test that ran to completion.
+func (w *testResultsWriter) Record(test *testCase, testRunResult *testRunResult) error {
+	if w == nil {
+		return nil
+	}
+	content, err := json.Marshal(TestResult{
+		Name:        test.name,
+		Attempt:     test.attempt(),
+		State:       testRunResult.testState,
+		Start:       testRunResult.start,
+		End:         testRunResult.end,
+		ExitCode:    testRunResult.exitCode,
+		ArtifactDir: testRunResult.artifactDir,
+		Binary:      test.binary.source(),
+	})
+	if err != nil {
+		return err
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	_, err = w.file.Write(append(content, '\n'))
+	return err
+}
+
+func (w *testResultsWriter) Close() error {
+	if w == nil {
+		return nil
+	}
+	return w.file.Close()
+}
+
+// createTestArtifactDir creates a new directory for the artifacts of this run of the test in dir and returns it.
+// Runs of the same test are told apart by their attempt, and by a counter for tests that run several times.
+func createTestArtifactDir(dir string, test *testCase) (string, error) {
+	slug := strings.Trim(unsafeArtifactDirChars.ReplaceAllString(test.name, "_"), "_")
+	if len(slug) > maxArtifactDirSlugLength {
+		slug = slug[:maxArtifactDirSlugLength]
+	}
+	hash := fnv.New32a()
+	hash.Write([]byte(test.name))
+	parent := filepath.Join(dir, fmt.Sprintf("%s-%08x", slug, hash.Sum32()))
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return "", err
+	}
+
+	for run := 1; ; run++ {
+		name := fmt.Sprintf("attempt-%d", test.attempt())
+		if run > 1 {
+			name = fmt.Sprintf("%s.%d", name, run)
+		}
+		artifactDir := filepath.Join(parent, name)
+		err := os.Mkdir(artifactDir, 0755)
+		if os.IsExist(err) {
+			continue
+		}
+		if os.IsNotExist(err) {
+			// another run of the test removed the parent after it finished without artifacts
+			if err := os.MkdirAll(parent, 0755); err != nil {
+				return "", err
+			}
+			run--
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		return artifactDir, nil
+	}
+}
+
+// removeIfEmpty removes the artifact directory of a test that wrote no artifacts, along with the parent directory it
+// shares with the other runs of the test when that is empty too.  It returns whether the directory is kept.
+func removeIfEmpty(artifactDir string) bool {
+	if err := os.Remove(artifactDir); err != nil {
+		return !os.IsNotExist(err)
+	}
+	os.Remove(filepath.Dir(artifactDir))
+	return false
+}
@@ -0,0 +1,79 @@
+package ginkgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestArtifactDir(t *testing.T) {
+	dir := t.TempDir()
+	test := &testCase{name: "[sig-storage] CSI volumes [Suite:openshift/conformance/parallel]"}
+
+	first, err := createTestArtifactDir(dir, test)
+	require.NoError(t, err)
+	assert.Equal(t, "sig-storage_CSI_volumes_Suite_openshift_conformance_parallel-ce9f0cde/attempt-1", relativePath(t, dir, first))
+	second, err := createTestArtifactDir(dir, test)
+	require.NoError(t, err)
+	assert.Equal(t, "sig-storage_CSI_volumes_Suite_openshift_conformance_parallel-ce9f0cde/attempt-1.2", relativePath(t, dir, second), "tests run more than once get their own directory")
+	retry, err := createTestArtifactDir(dir, test.Retry())
+	require.NoError(t, err)
+	assert.Equal(t, "sig-storage_CSI_volumes_Suite_openshift_conformance_parallel-ce9f0cde/attempt-2", relativePath(t, dir, retry))
+
+	require.NoError(t, os.WriteFile(filepath.Join(first, "pod.yaml"), []byte("kind: Pod"), 0644))
+	assert.True(t, removeIfEmpty(first))
+	assert.False(t, removeIfEmpty(second))
+	assert.False(t, removeIfEmpty(retry))
+	assert.DirExists(t, first)
+	assert.NoDirExists(t, second)
+
+	assert.False(t, removeIfEmpty(first+"-missing"))
+	require.NoError(t, os.Remove(filepath.Join(first, "pod.yaml")))
+	assert.False(t, removeIfEmpty(first))
+	assert.NoDirExists(t, filepath.Dir(first), "the directory of a test without artifacts is removed")
+
+	long := &testCase{name: string(bytes.Repeat([]byte("a"), 300))}
+	longDir, err := createTestArtifactDir(dir, long)
+	require.NoError(t, err)
+	assert.Len(t, filepath.Base(filepath.Dir(longDir)), maxArtifactDirSlugLength+9)
+}
+
+func relativePath(t *testing.T, base, path string) string {
+	ret, err := filepath.Rel(base, path)
+	require.NoError(t, err)
+	return ret
+}
+
+func TestTestResults(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2023, 2, 14, 20, 30, 0, 0, time.UTC)
+
+	results, err := openTestResults(dir, false)
+	require.NoError(t, err)
+	test := &testCase{name: "a", failed: true}
+	require.NoError(t, results.Record(test, &testRunResult{name: "a", testState: TestFailed, start: start, end: start.Add(time.Second), exitCode: 1, artifactDir: "test-artifacts/a-050c5d7e/attempt-1"}))
+	require.NoError(t, results.Close())
+
+	results, err = openTestResults(dir, true)
+	require.NoError(t, err)
+	require.NoError(t, results.Record(test.Retry(), &testRunResult{name: "a", testState: TestSucceeded, start: start, end: start.Add(time.Second)}))
+	require.NoError(t, results.Close())
+	var nilResults *testResultsWriter
+	assert.NoError(t, nilResults.Record(test, &testRunResult{}))
+
+	content, err := os.ReadFile(filepath.Join(dir, testResultsFilename))
+	require.NoError(t, err)
+	lines := bytes.Split(bytes.TrimSpace(content), []byte("\n"))
+	require.Len(t, lines, 2, "a resumed run appends to the results")
+	assert.JSONEq(t, `{"name":"a","attempt":1,"state":"Failed","start":"2023-02-14T20:30:00Z","end":"2023-02-14T20:30:01Z","exitCode":1,"artifactDir":"test-artifacts/a-050c5d7e/attempt-1"}`, string(lines[0]))
+	result := TestResult{}
+	require.NoError(t, json.Unmarshal(lines[1], &result))
+	assert.Equal(t, 2, result.Attempt)
+	assert.Equal(t, TestSucceeded, result.State)
+}
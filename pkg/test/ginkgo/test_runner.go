@@ -8,14 +8,13 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"sync"
 	"syscall"
 	"time"
 
-	"github.com/openshift/origin/pkg/clioptions/clusterdiscovery"
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
-	"k8s.io/kubernetes/test/e2e/framework"
 )
 
 type testSuiteRunner interface {
@@ -57,6 +56,16 @@ func (r *testSuiteRunnerImpl) RunOneTest(ctx context.Context, test *testCase) {
 
 	testRunResult.testRunResult = r.commandContext.RunTestInNewProcess(ctx, test)
 	mutateTestCaseWithResults(test, testRunResult)
+
+	// tests interrupted by the end of the run have not completed and must run again on resume
+	if ctx.Err() == nil {
+		if err := r.testOutput.checkpoint.Record(test, testRunResult.testRunResult); err != nil {
+			fmt.Fprintf(r.testOutput.out, "error: Unable to checkpoint %q: %v\n", test.name, err)
+		}
+		if err := r.testOutput.results.Record(test, testRunResult.testRunResult); err != nil {
+			fmt.Fprintf(r.testOutput.out, "error: Unable to record the result of %q: %v\n", test.name, err)
+		}
+	}
 }
 
 func mutateTestCaseWithResults(test *testCase, testRunResult *testRunResultHandle) {
@@ -117,6 +126,10 @@ func mutateTestCaseWithResults(test *testCase, testRunResult *testRunResultHandl
 type commandContext struct {
 	env     []string
 	timeout time.Duration
+	// junitDir is optional and holds the artifact directory of every test process.
+	junitDir string
+	// testProvider is the TEST_PROVIDER passed to every test process.
+	testProvider *testProvider
 
 	testOutputConfig testOutputConfig
 }
@@ -125,6 +138,12 @@ type testOutputConfig struct {
 	testOutputLock  *sync.Mutex
 	out             io.Writer
 	monitorRecorder monitorapi.Recorder
+	// currentProgress is optional and exposes the progress of the executing tests.
+	currentProgress *currentTestSuiteProgress
+	// checkpoint is optional and records every completed test so an interrupted run can be resumed.
+	checkpoint *checkpointJournal
+	// results is optional and records every completed test for tools that do not read junit.
+	results *testResultsWriter
 
 	includeSuccessfulOutput bool
 }
@@ -139,6 +158,10 @@ type testRunResult struct {
 	end             time.Time
 	testState       TestState
 	testOutputBytes []byte
+	// exitCode is the exit code of the test process, -1 when the process did not run or did not exit on its own.
+	exitCode int
+	// artifactDir is the directory, relative to the junit dir, the test process wrote artifacts to.
+	artifactDir string
 }
 
 func (r testRunResult) duration() time.Duration {
@@ -173,26 +196,31 @@ func isTestFailed(testState TestState) bool {
 }
 
 // testOutputLock prevents parallel tests from interleaving their output.
-func newTestOutputConfig(testOutputLock *sync.Mutex, out io.Writer, monitorRecorder monitorapi.Recorder, includeSuccessfulOutput bool) testOutputConfig {
+func newTestOutputConfig(testOutputLock *sync.Mutex, out io.Writer, monitorRecorder monitorapi.Recorder, currentProgress *currentTestSuiteProgress, checkpoint *checkpointJournal, results *testResultsWriter, includeSuccessfulOutput bool) testOutputConfig {
 	return testOutputConfig{
 		testOutputLock:          testOutputLock,
 		out:                     out,
 		monitorRecorder:         monitorRecorder,
+		currentProgress:         currentProgress,
+		checkpoint:              checkpoint,
+		results:                 results,
 		includeSuccessfulOutput: includeSuccessfulOutput,
 	}
 }
 
 // construction provided so that if we add anything, we get a compile failure for all callers instead of weird behavior
-func newCommandContext(env []string, timeout time.Duration) *commandContext {
+func newCommandContext(env []string, timeout time.Duration, junitDir string) *commandContext {
 	return &commandContext{
-		env:     env,
-		timeout: timeout,
+		env:          env,
+		timeout:      timeout,
+		junitDir:     junitDir,
+		testProvider: newTestProvider(discoverClusterConfiguration),
 	}
 }
 
 func (c *commandContext) commandString(test *testCase) string {
 	buf := &bytes.Buffer{}
-	envs := updateEnvVars(c.env)
+	envs := updateEnvVars(c.env, c.testProvider.get())
 	for _, env := range envs {
 		parts := strings.SplitN(env, "=", 2)
 		fmt.Fprintf(buf, "%s=%q ", parts[0], parts[1])
@@ -203,9 +231,9 @@ func (c *commandContext) commandString(test *testCase) string {
 }
 
 func (c *commandContext) extractCommands(test *testCase) (string, string) {
-	testBinary := test.binaryName
-	if len(testBinary) == 0 {
-		testBinary = os.Args[0]
+	testBinary := os.Args[0]
+	if test.binary != nil {
+		testBinary = test.binary.path
 	}
 	testName := test.rawName
 	if len(testName) == 0 {
@@ -296,6 +324,7 @@ func (c *commandContext) RunTestInNewProcess(ctx context.Context, test *testCase
 	ret := &testRunResult{
 		name:      test.name,
 		testState: TestUnknown,
+		exitCode:  -1,
 	}
 
 	// if the test was already marked as skipped, skip it.
@@ -307,17 +336,52 @@ func (c *commandContext) RunTestInNewProcess(ctx context.Context, test *testCase
 	ret.start = time.Now()
 	testBinary, testName := c.extractCommands(test)
 	command := exec.Command(testBinary, "run-test", testName)
-	command.Env = append(os.Environ(), updateEnvVars(c.env)...)
+	command.Env = append(os.Environ(), updateEnvVars(c.env, c.testProvider.get())...)
+
+	var artifactDir string
+	var artifactDirErr error
+	if len(c.junitDir) > 0 {
+		artifactDir, artifactDirErr = createTestArtifactDir(filepath.Join(c.junitDir, testArtifactsDirname), test)
+		if artifactDirErr == nil {
+			command.Env = append(command.Env, fmt.Sprintf("%s=%s", TestArtifactDirEnvVar, artifactDir))
+		}
+	}
 
 	timeout := c.timeout
 	if test.testTimeout != 0 {
 		timeout = test.testTimeout
 	}
 
-	testOutputBytes, err := runWithTimeout(ctx, command, timeout)
+	var testOutputBytes []byte
+	var result *ExternalTestResult
+	var err error
+	if test.binary.reportsResults() {
+		testOutputBytes, result, err = runExternalTestWithTimeout(ctx, command, timeout)
+	} else {
+		testOutputBytes, err = runWithTimeout(ctx, command, timeout)
+	}
 	ret.end = time.Now()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		ret.exitCode = exitErr.ExitCode()
+	} else if err == nil {
+		ret.exitCode = 0
+	}
+	if len(artifactDir) > 0 && removeIfEmpty(artifactDir) {
+		ret.artifactDir, _ = filepath.Rel(c.junitDir, artifactDir)
+	}
 
 	ret.testOutputBytes = testOutputBytes
+	if artifactDirErr != nil {
+		ret.testOutputBytes = append(ret.testOutputBytes, []byte(fmt.Sprintf("\nunable to create an artifact directory: %v\n", artifactDirErr))...)
+	}
+	if ctx.Err() == nil && result != nil {
+		state, stateErr := result.testState()
+		if stateErr != nil {
+			ret.testOutputBytes = append(ret.testOutputBytes, []byte(fmt.Sprintf("\n%v\n", stateErr))...)
+		}
+		ret.testState = state
+		return ret
+	}
 	if err == nil {
 		ret.testState = TestSucceeded
 		return ret
@@ -352,25 +416,14 @@ func (c *commandContext) RunTestInNewProcess(ctx context.Context, test *testCase
 	return ret
 }
 
-func updateEnvVars(envs []string) []string {
+// updateEnvVars replaces any TEST_PROVIDER in envs with provider.
+func updateEnvVars(envs []string, provider string) []string {
 	result := []string{}
 	for _, env := range envs {
 		if !strings.HasPrefix(env, "TEST_PROVIDER") {
 			result = append(result, env)
 		}
 	}
-	// copied from provider.go
-	// TODO: add error handling, and maybe turn this into sharable helper?
-	config := &clusterdiscovery.ClusterConfiguration{}
-	clientConfig, _ := framework.LoadConfig(true)
-	clusterState, _ := clusterdiscovery.DiscoverClusterState(clientConfig)
-	if clusterState != nil {
-		config, _ = clusterdiscovery.LoadConfig(clusterState)
-	}
-	if len(config.ProviderName) == 0 {
-		config.ProviderName = "skeleton"
-	}
-	provider, _ := json.Marshal(config)
 	result = append(result, fmt.Sprintf("TEST_PROVIDER=%s", provider))
 	// TODO: do we need to inject KUBECONFIG?
 	// result = append(result, "KUBECONFIG=%s", )
@@ -378,6 +431,44 @@ func updateEnvVars(envs []string) []string {
 }
 
 func runWithTimeout(ctx context.Context, c *exec.Cmd, timeout time.Duration) ([]byte, error) {
+	interruptOnTimeout(ctx, c, timeout)
+	return c.CombinedOutput()
+}
+
+// runStdoutWithTimeout is runWithTimeout for commands that write JSON to stdout, stderr is written to errOut.
+func runStdoutWithTimeout(ctx context.Context, c *exec.Cmd, timeout time.Duration, errOut io.Writer) ([]byte, error) {
+	interruptOnTimeout(ctx, c, timeout)
+	c.Stderr = errOut
+	return c.Output()
+}
+
+// runExternalTestWithTimeout runs an external test that reports an ExternalTestResult.  The test output is the output
+// of the result followed by stderr.  The result is nil when the test did not write one.
+func runExternalTestWithTimeout(ctx context.Context, c *exec.Cmd, timeout time.Duration) ([]byte, *ExternalTestResult, error) {
+	stderr := &bytes.Buffer{}
+	stdout, err := runStdoutWithTimeout(ctx, c, timeout, stderr)
+
+	result := &ExternalTestResult{}
+	if decodeErr := json.Unmarshal(stdout, result); decodeErr != nil {
+		output := append(stdout, stderr.Bytes()...)
+		if err == nil {
+			err = fmt.Errorf("test did not report a result: %w", decodeErr)
+			output = append(output, []byte(fmt.Sprintf("\n%v\n", err))...)
+		}
+		return output, nil, err
+	}
+
+	output := &bytes.Buffer{}
+	output.WriteString(result.Output)
+	if len(result.Error) > 0 {
+		fmt.Fprintf(output, "\n%s\n", result.Error)
+	}
+	output.Write(stderr.Bytes())
+	return output.Bytes(), result, err
+}
+
+// interruptOnTimeout interrupts the command once it runs longer than timeout, or the context is finished.
+func interruptOnTimeout(ctx context.Context, c *exec.Cmd, timeout time.Duration) {
 	if timeout > 0 {
 		go func() {
 			select {
@@ -402,5 +493,4 @@ func runWithTimeout(ctx context.Context, c *exec.Cmd, timeout time.Duration) ([]
 
 		}()
 	}
-	return c.CombinedOutput()
 }
@@ -47,12 +47,24 @@ func testsForSuite() ([]*testCase, error) {
 
 var re = regexp.MustCompile(`.*\[Timeout:(.[^\]]*)\]`)
 
+var exclusiveRe = regexp.MustCompile(`\[Exclusive:([^\]]+)\]`)
+
+// exclusionGroup returns the group named by an [Exclusive:<group>] label in the test name.  No two tests of the same
+// group run at the same time, while tests of other groups and tests without a group keep running in parallel.
+func exclusionGroup(name string) string {
+	if match := exclusiveRe.FindStringSubmatch(name); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
 func newTestCaseFromGinkgoSpec(spec types.TestSpec) (*testCase, error) {
 	name := spec.Text()
 	tc := &testCase{
-		name:      name,
-		locations: spec.CodeLocations(),
-		spec:      spec,
+		name:          name,
+		locations:     spec.CodeLocations(),
+		spec:          spec,
+		testExclusion: exclusionGroup(name),
 	}
 
 	if match := re.FindStringSubmatch(name); match != nil {
@@ -73,10 +85,10 @@ type testCase struct {
 	name string
 	// rawName is the name as reported by external binary
 	rawName string
-	// binaryName is the name of the external binary
-	binaryName string
-	spec       types.TestSpec
-	locations  []types.CodeLocation
+	// binary is the external binary the test comes from, nil for tests built into openshift-tests
+	binary    *externalBinary
+	spec      types.TestSpec
+	locations []types.CodeLocation
 
 	// identifies which tests can be run in parallel (ginkgo runs suites linearly)
 	testExclusion string
@@ -101,6 +113,8 @@ type testCase struct {
 func (t *testCase) Retry() *testCase {
 	copied := &testCase{
 		name:          t.name,
+		rawName:       t.rawName,
+		binary:        t.binary,
 		spec:          t.spec,
 		locations:     t.locations,
 		testExclusion: t.testExclusion,
@@ -134,6 +148,8 @@ type TestSuite struct {
 	Parallelism int
 	// The number of flakes that may occur before this test is marked as a failure.
 	MaximumAllowedFlakes int
+	// RetryPolicy controls how failures are retried, DefaultRetryPolicy(MaximumAllowedFlakes) when nil.
+	RetryPolicy *RetryPolicy
 
 	ClusterStabilityDuringTest ClusterStabilityDuringTest
 